@@ -0,0 +1,271 @@
+package bingo
+
+import (
+	"fmt"
+	"sort"
+)
+
+type (
+	// BalanceReport describes how evenly the boards of a set share numbers and lines, from simulated games.
+	BalanceReport struct {
+		// Games is the number of games that were simulated.
+		Games int
+		// NumberCounts is how many boards each number is on, indexed by number.  The zero index is unused.
+		NumberCounts [MaxNumber + 1]int
+		// FirstWins is the chance that the first line of a game is made on each draw, indexed by how many numbers have been drawn.
+		FirstWins [MaxNumber + 1]float64
+		// Winners is the expected number of boards that make their first line on each draw, indexed by how many numbers have been drawn.
+		Winners [MaxNumber + 1]float64
+		// FirstWinners is the expected number of boards that make a line on the draw that makes the first line of a game.
+		FirstWinners float64
+	}
+	// linePairs counts how many lines of a set of boards have each pair of numbers, indexed by the smaller and larger number.
+	linePairs struct {
+		counts [MaxNumber + 1][MaxNumber + 1]int
+		score  int
+	}
+)
+
+// balanceRounds is the number of times every board is visited when spreading out the lines of a balanced set.
+const balanceRounds = 4
+
+// boardLines are the indexes of the cells of each five-in-a-row line on a board.
+var boardLines = func() [][]int {
+	lines := make([][]int, len(linePattern.Masks))
+	for i, m := range linePattern.Masks {
+		for j := range 25 {
+			if m.Has(j) {
+				lines[i] = append(lines[i], j)
+			}
+		}
+	}
+	return lines
+}()
+
+// NewBalancedBoards deals a set of n boards with unique ids where each number is on about the same number of boards.
+// The counts of boards that each number in a column is on differ by at most one.
+// Numbers are then swapped within columns so the same pairs of numbers are in as few lines as possible, spreading out when lines are made.
+// ErrBatchNotUnique is returned if a board with a unique id cannot be dealt.
+func (d *Dealer) NewBalancedBoards(n int) ([]Board, error) {
+	var counts [MaxNumber + 1]int
+	boards := make([]Board, 0, n)
+	ids := make(map[string]struct{}, n)
+	for len(boards) < n {
+		b, boardID, err := d.newBalancedBoard(&counts, ids)
+		if err != nil {
+			return nil, fmt.Errorf("board #%v: %w", len(boards)+1, err)
+		}
+		boards = append(boards, *b)
+		ids[boardID] = struct{}{}
+	}
+	d.spreadLines(boards)
+	if err := CheckBatch(boards, 0); err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
+
+// BalanceReport simulates the games to find how many boards of the set make a line on each draw.
+// The games are shuffled by the dealer.
+func (d *Dealer) BalanceReport(boards []Board, games int) BalanceReport {
+	r := BalanceReport{
+		Games: games,
+	}
+	for _, b := range boards {
+		for _, n := range b {
+			if n.Valid() {
+				r.NumberCounts[n]++
+			}
+		}
+	}
+	if games <= 0 {
+		return r
+	}
+	var draws [MaxNumber + 1]int
+	for range games {
+		var g Game
+		d.Reset(&g)
+		for i, n := range g.numbers {
+			draws[n] = i + 1
+		}
+		first, firstWinners := len(draws), 0
+		for _, b := range boards {
+			draw := b.firstDraw(linePattern, &draws)
+			r.Winners[draw]++
+			switch {
+			case draw < first:
+				first, firstWinners = draw, 1
+			case draw == first:
+				firstWinners++
+			}
+		}
+		if len(boards) != 0 {
+			r.FirstWins[first]++
+			r.FirstWinners += float64(firstWinners)
+		}
+	}
+	for i := range r.Winners {
+		r.Winners[i] /= float64(games)
+		r.FirstWins[i] /= float64(games)
+	}
+	r.FirstWinners /= float64(games)
+	return r
+}
+
+// firstDraw is how many numbers had been drawn when the board first had the pattern.
+// The draws are the positions of the numbers in the draw order of a game, counted from one and indexed by number.
+// The free cell is always drawn.
+func (b Board) firstDraw(p Pattern, draws *[MaxNumber + 1]int) int {
+	first := 0
+	for i, m := range p.Masks {
+		last := 0
+		for j, n := range b {
+			if m.Has(j) && n != 0 {
+				last = max(last, draws[n])
+			}
+		}
+		switch {
+		case i == 0, p.All && last > first, !p.All && last < first:
+			first = last
+		}
+	}
+	return first
+}
+
+// newBalancedBoard deals a board with the numbers of each column that are on the fewest boards, adding the numbers to the counts.
+// Numbers that are on the same number of boards are chosen randomly.
+func (d *Dealer) newBalancedBoard(counts *[MaxNumber + 1]int, ids map[string]struct{}) (*Board, string, error) {
+	for i := 0; i < maxBatchAttempts; i++ {
+		var b Board
+		for c := range 5 {
+			nums := make([]Number, 15)
+			for j := range nums {
+				nums[j] = Number(c*15 + j + 1)
+			}
+			d.deal(func(rs Resetter) {
+				rs.Shuffle(len(nums), func(i, j int) {
+					nums[i], nums[j] = nums[j], nums[i]
+				})
+			})
+			sort.SliceStable(nums, func(i, j int) bool {
+				return counts[nums[i]] < counts[nums[j]]
+			})
+			for r := range 5 {
+				if i := c*5 + r; i != 12 { // free cell
+					b[i], nums = nums[0], nums[1:]
+				}
+			}
+		}
+		boardID, err := b.ID()
+		if err != nil {
+			return nil, "", fmt.Errorf("getting board id: %v", err)
+		}
+		if _, ok := ids[boardID]; ok {
+			continue
+		}
+		for _, n := range b {
+			if n.Valid() {
+				counts[n]++
+			}
+		}
+		return &b, boardID, nil
+	}
+	return nil, "", fmt.Errorf("%w: try a smaller set", ErrBatchNotUnique)
+}
+
+// spreadLines swaps numbers in the columns of the boards when the swap puts fewer of the same pairs of numbers in lines.
+// Numbers are swapped between rows of a board and between the same cells of two boards, so the numbers on each board stay in their columns and the counts of boards that each number is on do not change.
+func (d *Dealer) spreadLines(boards []Board) {
+	var lp linePairs
+	for i := range boards {
+		lp.add(boards[i], 1)
+	}
+	order := make([]int, len(boards))
+	for i := range order {
+		order[i] = i
+	}
+	for range balanceRounds {
+		d.deal(func(rs Resetter) {
+			rs.Shuffle(len(order), func(i, j int) {
+				order[i], order[j] = order[j], order[i]
+			})
+		})
+		for k, i := range order {
+			b := &boards[i]
+			for c := range 5 {
+				for r1 := range 5 {
+					for r2 := r1 + 1; r2 < 5; r2++ {
+						if i1, i2 := c*5+r1, c*5+r2; i1 != 12 && i2 != 12 {
+							lp.trySwap(b, i1, b, i2)
+						}
+					}
+				}
+			}
+			if k+1 == len(order) {
+				continue
+			}
+			other := &boards[order[k+1]]
+			for j := range b {
+				if j != 12 && !b.has(other[j]) && !other.has(b[j]) {
+					lp.trySwap(b, j, other, j)
+				}
+			}
+		}
+	}
+}
+
+// trySwap swaps the numbers of the cells, keeping the swap only if it lowers the score.
+// The cells can be on the same board.
+func (lp *linePairs) trySwap(b1 *Board, i1 int, b2 *Board, i2 int) {
+	before := lp.score
+	lp.add(*b1, -1)
+	if b1 != b2 {
+		lp.add(*b2, -1)
+	}
+	b1[i1], b2[i2] = b2[i2], b1[i1]
+	lp.add(*b1, 1)
+	if b1 != b2 {
+		lp.add(*b2, 1)
+	}
+	if lp.score < before {
+		return
+	}
+	lp.add(*b1, -1)
+	if b1 != b2 {
+		lp.add(*b2, -1)
+	}
+	b1[i1], b2[i2] = b2[i2], b1[i1]
+	lp.add(*b1, 1)
+	if b1 != b2 {
+		lp.add(*b2, 1)
+	}
+}
+
+// add changes the counts of the pairs of numbers in the lines of the board by the delta, which is 1 or -1.
+// The score is the sum of the squares of the counts, so it is lowest when pairs of numbers are spread out in different lines.
+func (lp *linePairs) add(b Board, delta int) {
+	for _, line := range boardLines {
+		for j, i1 := range line {
+			for _, i2 := range line[j+1:] {
+				n1, n2 := b[i1], b[i2]
+				if n1 == 0 || n2 == 0 {
+					continue
+				}
+				lo, hi := min(n1, n2), max(n1, n2)
+				c := lp.counts[lo][hi]
+				lp.counts[lo][hi] = c + delta
+				lp.score += 2*c*delta + 1 // (c+delta)^2 - c^2
+			}
+		}
+	}
+}
+
+// has determines if the number is on the board.
+func (b Board) has(n Number) bool {
+	for _, n2 := range b {
+		if n == n2 {
+			return true
+		}
+	}
+	return false
+}
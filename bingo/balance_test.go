@@ -0,0 +1,114 @@
+package bingo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDealerNewBalancedBoards(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 7, 40} {
+		boards, err := NewDealer(1257894001).NewBalancedBoards(n)
+		if err != nil {
+			t.Errorf("%v boards: unwanted error: %v", n, err)
+			continue
+		}
+		if len(boards) != n {
+			t.Errorf("%v boards: got %v boards", n, len(boards))
+		}
+		for i, b := range boards {
+			if !b.isValid() || !b.HasFreeCell() {
+				t.Errorf("%v boards: board %v is not a valid board with a free cell: %v", n, i, b)
+			}
+		}
+		if err := CheckBatch(boards, 0); err != nil {
+			t.Errorf("%v boards: wanted unique boards: %v", n, err)
+		}
+		r := NewDealer(1257894001).BalanceReport(boards, 0)
+		for c := range 5 {
+			counts := r.NumberCounts[c*15+1 : c*15+16]
+			lo, hi := counts[0], counts[0]
+			for _, count := range counts {
+				lo, hi = min(lo, count), max(hi, count)
+			}
+			if hi-lo > 1 {
+				t.Errorf("%v boards: wanted counts of boards with numbers in column %v to differ by at most one: %v", n, c, counts)
+			}
+		}
+	}
+}
+
+func TestSpreadLines(t *testing.T) {
+	d := NewDealer(1257894001)
+	boards := make([]Board, 30)
+	for i := range boards {
+		boards[i] = *d.NewBoard()
+	}
+	var before, after linePairs
+	for _, b := range boards {
+		before.add(b, 1)
+	}
+	d.spreadLines(boards)
+	for _, b := range boards {
+		after.add(b, 1)
+	}
+	if after.score >= before.score {
+		t.Errorf("wanted spreading lines to lower the score of pairs of numbers in lines: before: %v, after: %v", before.score, after.score)
+	}
+	for i, b := range boards {
+		if !b.isValid() {
+			t.Errorf("board %v: not valid after spreading lines: %v", i, b)
+		}
+	}
+}
+
+func TestDealerBalanceReport(t *testing.T) {
+	boards := []Board{*NewBoardFromSeed(1), *NewBoardFromSeed(2), *NewBoardFromSeed(3)}
+	const games = 500
+	r := NewDealer(1257894001).BalanceReport(boards, games)
+	var firstWins, winners float64
+	for i := range r.Winners {
+		firstWins += r.FirstWins[i]
+		winners += r.Winners[i]
+	}
+	switch {
+	case r.Games != games:
+		t.Errorf("wanted %v games, got %v", games, r.Games)
+	case r.NumberCounts[0] != 0:
+		t.Errorf("wanted free cell to not be counted: %v", r.NumberCounts)
+	case math.Abs(firstWins-1) > 1e-9:
+		t.Errorf("wanted each game to have a first line, got total chance %v", firstWins)
+	case math.Abs(winners-float64(len(boards))) > 1e-9:
+		t.Errorf("wanted each board to make a line in each game, got %v expected winners", winners)
+	case r.FirstWinners < 1 || r.FirstWinners > float64(len(boards)):
+		t.Errorf("wanted between 1 and %v first winners, got %v", len(boards), r.FirstWinners)
+	case r.Winners[3] != 0, r.FirstWins[4] != 0:
+		t.Errorf("wanted no lines before the fourth draw: %v", r.Winners)
+	}
+}
+
+func TestBoardFirstDraw(t *testing.T) {
+	b := Board{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 0, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65}
+	var draws [MaxNumber + 1]int
+	for i, n := range []Number{31, 32, 34, 35, 1, 2, 3, 4, 5, 16, 17} {
+		draws[n] = i + 1
+	}
+	for n := range draws {
+		if draws[n] == 0 {
+			draws[n] = 100
+		}
+	}
+	tests := []struct {
+		name    string
+		pattern Pattern
+		want    int
+	}{
+		{"line", linePattern, 4},
+		{"filled", filledPattern, 100},
+		{"all masks", Pattern{Masks: []Mask{columnMask(0), columnMask(2)}, All: true}, 9},
+	}
+	for i, test := range tests {
+		if got := b.firstDraw(test.pattern, &draws); test.want != got {
+			t.Errorf("test %v (%v): wanted first draw %v, got %v", i, test.name, test.want, got)
+		}
+	}
+}
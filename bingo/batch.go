@@ -0,0 +1,177 @@
+package bingo
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+type (
+	// BoardRegistry records the ids of boards that have been issued, so boards are not repeated in later batches.
+	BoardRegistry interface {
+		// Issued determines if a board with the id has been issued.
+		Issued(boardID string) (bool, error)
+		// RegisterNew records the ids of a new batch of boards as issued.
+		// ErrBoardIssued is returned and no ids are recorded if any of the boards has already been issued.
+		// The ids must be checked and recorded together so concurrent batches do not share boards.
+		RegisterNew(boardIDs []string) error
+	}
+	// BatchOptions change how a batch of boards is dealt.
+	BatchOptions struct {
+		// NoFreeCell causes the boards to have a number in the middle square instead of the free cell.
+		NoFreeCell bool
+		// MinDistance is the fewest numbers that each board must have that are not on each other board of the batch.
+		// Boards with a free cell share at most 24-MinDistance numbers.  Zero only requires the board ids to be unique.
+		MinDistance int
+		// Registry is optional.  When it is set, boards it has issued are not dealt, and the new boards are registered.
+		Registry BoardRegistry
+	}
+	// numberBits is a bit set of numbers, where the n-th bit is set if number n is in the set.
+	numberBits [2]uint64
+)
+
+const (
+	// maxBatchAttempts is the number of boards dealt for each board in a batch before giving up on finding a board that is unique enough.
+	maxBatchAttempts = 1000
+	// maxRegisterAttempts is the number of batches dealt before giving up on registering a batch that has no boards issued by other batches.
+	maxRegisterAttempts = 10
+)
+
+var (
+	// ErrBatchNotUnique is returned when boards in a batch have the same id or are too close, or when no unique enough board can be dealt.
+	ErrBatchNotUnique = errors.New("boards are not unique enough")
+	// ErrBoardIssued is returned by registries when a board in a new batch has already been issued.
+	ErrBoardIssued = errors.New("board has already been issued")
+)
+
+// NewBoards deals a batch of n boards with unique ids.
+// If another batch issues one of the boards before the batch is registered, a new batch is dealt.
+// An error is returned if the options are invalid or the registry fails.
+// ErrBatchNotUnique is returned if a board that is far enough from the others cannot be found.
+func (d *Dealer) NewBoards(n int, opts BatchOptions) ([]Board, error) {
+	if opts.MinDistance < 0 || opts.MinDistance > len(Board{}) {
+		return nil, fmt.Errorf("minimum distance must be between 0 and %v", len(Board{}))
+	}
+	for i := 1; ; i++ {
+		boards, boardIDs, err := d.newBatch(n, opts)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Registry == nil {
+			return boards, nil
+		}
+		err = opts.Registry.RegisterNew(boardIDs)
+		switch {
+		case err == nil:
+			return boards, nil
+		case errors.Is(err, ErrBoardIssued) && i < maxRegisterAttempts:
+			continue
+		default:
+			return nil, fmt.Errorf("registering boards: %w", err)
+		}
+	}
+}
+
+// newBatch deals n boards that are not in the registry and are far enough from each other.
+func (d *Dealer) newBatch(n int, opts BatchOptions) ([]Board, []string, error) {
+	boards := make([]Board, 0, n)
+	boardIDs := make([]string, 0, n)
+	ids := make(map[string]struct{}, n)
+	for len(boards) < n {
+		b, boardID, err := d.newBatchBoard(boards, ids, opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("board #%v: %w", len(boards)+1, err)
+		}
+		boards = append(boards, *b)
+		boardIDs = append(boardIDs, boardID)
+		ids[boardID] = struct{}{}
+	}
+	return boards, boardIDs, nil
+}
+
+// CheckBatch ensures the boards have unique ids and each pair of boards has at least the minimum distance.
+// The error names the first pair of boards that collide, numbered from one.
+func CheckBatch(boards []Board, minDistance int) error {
+	ids := make(map[string]int, len(boards))
+	for i, b := range boards {
+		boardID, err := b.ID()
+		if err != nil {
+			return fmt.Errorf("board #%v: %v", i+1, err)
+		}
+		if j, ok := ids[boardID]; ok {
+			return fmt.Errorf("%w: boards #%v and #%v have the same id: %v", ErrBatchNotUnique, j+1, i+1, boardID)
+		}
+		ids[boardID] = i
+		for j, b2 := range boards[:i] {
+			if d := b.Distance(b2); d < minDistance {
+				return fmt.Errorf("%w: boards #%v and #%v only have %v different numbers, wanted at least %v", ErrBatchNotUnique, j+1, i+1, d, minDistance)
+			}
+		}
+	}
+	return nil
+}
+
+// Distance is the number of numbers on the board that are not on the other board.
+// The free cell is not a number, so boards with a free cell are at most 24 apart.
+func (b Board) Distance(other Board) int {
+	x, y := b.numberBits(), other.numberBits()
+	return bits.OnesCount64(x[0]&^y[0]) + bits.OnesCount64(x[1]&^y[1])
+}
+
+// newBatchBoard deals a board that is not in the batch or registry and is far enough from the other boards in the batch.
+func (d *Dealer) newBatchBoard(boards []Board, ids map[string]struct{}, opts BatchOptions) (*Board, string, error) {
+	for i := 0; i < maxBatchAttempts; i++ {
+		var b *Board
+		switch {
+		case opts.NoFreeCell:
+			b = d.NewNoFreeCellBoard()
+		default:
+			b = d.NewBoard()
+		}
+		boardID, err := b.ID()
+		if err != nil {
+			return nil, "", fmt.Errorf("getting board id: %v", err)
+		}
+		if _, ok := ids[boardID]; ok {
+			continue
+		}
+		if !b.farFrom(boards, opts.MinDistance) {
+			continue
+		}
+		if opts.Registry != nil {
+			issued, err := opts.Registry.Issued(boardID)
+			switch {
+			case err != nil:
+				return nil, "", fmt.Errorf("checking registry: %v", err)
+			case issued:
+				continue
+			}
+		}
+		return b, boardID, nil
+	}
+	return nil, "", fmt.Errorf("%w: try a smaller batch or minimum distance", ErrBatchNotUnique)
+}
+
+// farFrom determines if the board is at least the minimum distance from each of the other boards.
+func (b Board) farFrom(boards []Board, minDistance int) bool {
+	if minDistance == 0 {
+		return true
+	}
+	for _, b2 := range boards {
+		if b.Distance(b2) < minDistance {
+			return false
+		}
+	}
+	return true
+}
+
+// numberBits is the set of numbers on the board.  The free cell is not included.
+func (b Board) numberBits() numberBits {
+	var nb numberBits
+	for _, n := range b {
+		if n.Valid() {
+			nb[n/64] |= 1 << (n % 64)
+		}
+	}
+	return nb
+}
@@ -0,0 +1,215 @@
+package bingo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// mockBoardRegistry is a BoardRegistry that keeps the ids of boards in memory.
+// The concurrent ids are issued by another batch when the next batch is registered.
+type mockBoardRegistry struct {
+	issued      map[string]struct{}
+	concurrent  []string
+	issuedErr   error
+	registerErr error
+}
+
+func (r *mockBoardRegistry) Issued(boardID string) (bool, error) {
+	_, ok := r.issued[boardID]
+	return ok, r.issuedErr
+}
+
+func (r *mockBoardRegistry) RegisterNew(boardIDs []string) error {
+	if r.registerErr != nil {
+		return r.registerErr
+	}
+	for _, boardID := range r.concurrent {
+		r.issued[boardID] = struct{}{}
+	}
+	r.concurrent = nil
+	for _, boardID := range boardIDs {
+		if _, ok := r.issued[boardID]; ok {
+			return ErrBoardIssued
+		}
+	}
+	for _, boardID := range boardIDs {
+		r.issued[boardID] = struct{}{}
+	}
+	return nil
+}
+
+func TestBoardDistance(t *testing.T) {
+	b1 := Board{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 0, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65}
+	b2 := Board{5, 4, 3, 2, 1, 16, 17, 18, 19, 20, 31, 32, 0, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65}
+	b3 := Board{6, 7, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 0, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 70}
+	b4 := Board{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 33, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65}
+	tests := []struct {
+		name string
+		b1   Board
+		b2   Board
+		want int
+	}{
+		{"same board", b1, b1, 0},
+		{"same numbers in different rows", b1, b2, 0},
+		{"three different numbers", b1, b3, 3},
+		{"no free cell", b4, b1, 1},
+		{"free cell is not a number", b1, b4, 0},
+	}
+	for i, test := range tests {
+		if got := test.b1.Distance(test.b2); test.want != got {
+			t.Errorf("test %v (%v): distances not equal: wanted %v, got %v", i, test.name, test.want, got)
+		}
+	}
+}
+
+func TestDealerNewBoards(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		opts BatchOptions
+	}{
+		{"many boards", 1000, BatchOptions{}},
+		{"no free cell", 50, BatchOptions{NoFreeCell: true}},
+		{"minimum distance", 50, BatchOptions{MinDistance: 12}},
+	}
+	for i, test := range tests {
+		d := NewDealer(1257894001)
+		boards, err := d.NewBoards(test.n, test.opts)
+		switch {
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+		case len(boards) != test.n:
+			t.Errorf("test %v (%v): wanted %v boards, got %v", i, test.name, test.n, len(boards))
+		case boards[0].HasFreeCell() == test.opts.NoFreeCell:
+			t.Errorf("test %v (%v): wanted boards to have free cell: %v", i, test.name, !test.opts.NoFreeCell)
+		default:
+			if err := CheckBatch(boards, test.opts.MinDistance); err != nil {
+				t.Errorf("test %v (%v): wanted unique batch: %v", i, test.name, err)
+			}
+		}
+	}
+}
+
+func TestDealerNewBoardsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		opts BatchOptions
+	}{
+		{"negative minimum distance", 1, BatchOptions{MinDistance: -1}},
+		{"large minimum distance", 1, BatchOptions{MinDistance: 26}},
+		{"registry issued error", 1, BatchOptions{Registry: &mockBoardRegistry{issuedErr: errors.New("mock issued error")}}},
+		{"registry register error", 1, BatchOptions{Registry: &mockBoardRegistry{registerErr: errors.New("mock register error")}}},
+	}
+	for i, test := range tests {
+		d := NewDealer(1257894001)
+		if _, err := d.NewBoards(test.n, test.opts); err == nil {
+			t.Errorf("test %v (%v): wanted error", i, test.name)
+		}
+	}
+}
+
+func TestDealerNewBoardsNotUnique(t *testing.T) {
+	d := NewDealer(1257894001)
+	opts := BatchOptions{
+		MinDistance: 24,
+	}
+	if _, err := d.NewBoards(5, opts); !errors.Is(err, ErrBatchNotUnique) {
+		t.Errorf("wanted %v when boards cannot share any numbers, got: %v", ErrBatchNotUnique, err)
+	}
+	if err := CheckBatch([]Board{{}, {}}, 0); errors.Is(err, ErrBatchNotUnique) {
+		t.Errorf("wanted invalid boards to not be reported as not unique: %v", err)
+	}
+}
+
+func TestDealerNewBoardsRegistry(t *testing.T) {
+	r := mockBoardRegistry{
+		issued: make(map[string]struct{}),
+	}
+	opts := BatchOptions{
+		Registry: &r,
+	}
+	boards1, err := NewDealer(1257894001).NewBoards(5, opts)
+	if err != nil {
+		t.Fatalf("dealing first batch: %v", err)
+	}
+	boards2, err := NewDealer(1257894001).NewBoards(5, opts) // same seed deals the same boards first
+	if err != nil {
+		t.Fatalf("dealing second batch: %v", err)
+	}
+	if want, got := 10, len(r.issued); want != got {
+		t.Errorf("wanted %v registered boards, got %v", want, got)
+	}
+	if err := CheckBatch(append(boards1, boards2...), 0); err != nil {
+		t.Errorf("wanted registered boards to not be dealt again: %v", err)
+	}
+}
+
+func TestDealerNewBoardsRegistryConcurrentBatch(t *testing.T) {
+	boards1, err := NewDealer(1257894001).NewBoards(5, BatchOptions{})
+	if err != nil {
+		t.Fatalf("dealing first batch: %v", err)
+	}
+	boardID1, _ := boards1[0].ID()
+	r := mockBoardRegistry{
+		issued:     make(map[string]struct{}),
+		concurrent: []string{boardID1},
+	}
+	opts := BatchOptions{
+		Registry: &r,
+	}
+	boards2, err := NewDealer(1257894001).NewBoards(5, opts) // same seed deals the same boards first
+	switch {
+	case err != nil:
+		t.Fatalf("dealing second batch: %v", err)
+	case len(r.issued) != 6:
+		t.Errorf("wanted the board of the other batch and the new batch to be registered, got %v", r.issued)
+	}
+	for i, b := range boards2 {
+		if boardID, _ := b.ID(); boardID == boardID1 {
+			t.Errorf("board #%v: wanted board issued by other batch to not be dealt again", i+1)
+		}
+	}
+	t.Run("always issued", func(t *testing.T) {
+		r := mockBoardRegistry{
+			registerErr: ErrBoardIssued,
+		}
+		opts := BatchOptions{
+			Registry: &r,
+		}
+		if _, err := NewDealer(1257894001).NewBoards(5, opts); !errors.Is(err, ErrBoardIssued) {
+			t.Errorf("wanted %v when the boards are always issued by other batches, got: %v", ErrBoardIssued, err)
+		}
+	})
+}
+
+func TestCheckBatch(t *testing.T) {
+	b1 := *NewBoardFromSeed(1)
+	b2 := *NewBoardFromSeed(2)
+	invalid := Board{1, 1}
+	tests := []struct {
+		name        string
+		boards      []Board
+		minDistance int
+		wantErr     string
+	}{
+		{"unique", []Board{b1, b2}, 0, ""},
+		{"duplicate", []Board{b1, b2, b1}, 0, "boards #1 and #3 have the same id"},
+		{"too close", []Board{b1, b2}, 24, "boards #1 and #2 only have"},
+		{"invalid", []Board{b1, invalid}, 0, "board #2"},
+	}
+	for i, test := range tests {
+		err := CheckBatch(test.boards, test.minDistance)
+		switch {
+		case len(test.wantErr) == 0:
+			if err != nil {
+				t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+			}
+		case err == nil:
+			t.Errorf("test %v (%v): wanted error", i, test.name)
+		case !strings.Contains(err.Error(), test.wantErr):
+			t.Errorf("test %v (%v): wanted error to contain %q, got: %v", i, test.name, test.wantErr, err)
+		}
+	}
+}
@@ -1,54 +1,44 @@
 package bingo
 
-import (
-	"encoding/base64"
-	"errors"
-)
+import "errors"
 
 // Board represents a 5*5 square bingo board.
-// The middle square (index 12) is left empty (0).
+// The middle square (index 12) is usually left empty (0) as the free cell.
+// Boards without a free cell have a number from the N column in the middle square, so all 25 cells must be drawn.
 type Board [25]Number
 
-// NewBoard creates a board by drawing numbers from a game.
+// NoFreeCellRules are the rules of 75-ball games with 5x5 boards that do not have a free cell.
+var NoFreeCellRules = Rules{
+	Width:         5,
+	Height:        5,
+	ColumnNumbers: 15,
+}
+
+// NewBoard creates a board by drawing numbers from a game with the classic rules.
 // Each column of the board (5-cell group) only contains numbers of the same column.
+// Servers should use Dealer.NewBoard to shuffle with their own dealer.
 func NewBoard() *Board {
-	var g Game
-	GameResetter.Reset(&g)
-	g.numbersDrawn = g.NumbersLeft() // "draw" all the numbers
-	cols := g.DrawnNumberColumns()
-	var b Board
-	copy(b[0:], cols[0][:5])
-	copy(b[5:], cols[1][:5])
-	copy(b[10:], cols[2][:2])
-	copy(b[13:], cols[2][2:4]) // leave space for free cell, copying numbers and indexes 3 & 4 into rows 4 & 5 of middle column
-	copy(b[15:], cols[3][:5])
-	copy(b[20:], cols[4][:5])
-	return &b
+	return defaultDealer.NewBoard()
+}
+
+// NewNoFreeCellBoard creates a board by drawing numbers from a game, putting a number in the middle square instead of the free cell.
+func NewNoFreeCellBoard() *Board {
+	return defaultDealer.NewNoFreeCellBoard()
+}
+
+// HasFreeCell determines if the middle square of the board is the free cell.
+func (b Board) HasFreeCell() bool {
+	return b[12] == 0
 }
 
 // HasLine determines if the board has a five-in-a row line, creating a BINGO for the game.
 func (b Board) HasLine(g Game) bool {
-	nums := numberSet(g)
-	if b.hasDiagonal1(nums) || b.hasDiagonal2(nums) {
-		return true
-	}
-	for i := range 5 {
-		if b.hasColumn(i, nums) || b.hasRow(i, nums) {
-			return true
-		}
-	}
-	return false
+	return b.Matches(g, linePattern)
 }
 
 // IsFilled determines if all the numbers in the board have been called in the game.
 func (b Board) IsFilled(g Game) bool {
-	nums := numberSet(g)
-	for _, n := range b {
-		if _, ok := nums[n]; !ok {
-			return false
-		}
-	}
-	return true
+	return b.Matches(g, filledPattern)
 }
 
 // NumberSet creates a map of all the drawn numbers in the game.
@@ -63,52 +53,10 @@ func numberSet(g Game) map[Number]struct{} {
 	return nums
 }
 
-// hasColumn checks to see if the column on the board is completely included in nums.
-func (b Board) hasColumn(c int, nums map[Number]struct{}) bool {
-	for r := range 5 {
-		i := c*5 + r
-		if _, ok := nums[b[i]]; !ok {
-			return false
-		}
-	}
-	return true
-}
-
-// hasColumn checks to see if the row on the board is completely included in nums.
-func (b Board) hasRow(r int, nums map[Number]struct{}) bool {
-	for c := range 5 {
-		i := c*5 + r
-		if _, ok := nums[b[i]]; !ok {
-			return false
-		}
-	}
-	return true
-}
-
-// hasDiagonal1 checks to see if the leading 5-number diagonal on the board is completely included in nums.
-func (b Board) hasDiagonal1(nums map[Number]struct{}) bool {
-	for j := range 5 {
-		i := j*5 + j
-		if _, ok := nums[b[i]]; !ok {
-			return false
-		}
-	}
-	return true
-}
-
-// hasColumn checks to see if the trailing 5-number diagonal on the board is completely included in nums.
-func (b Board) hasDiagonal2(nums map[Number]struct{}) bool {
-	for j := range 5 {
-		i := j*5 + 4 - j
-		if _, ok := nums[b[i]]; !ok {
-			return false
-		}
-	}
-	return true
-}
-
 // ID encodes the board into a base64 string.
-// Each two numbers can be shrunk to a 0-14 number, concatenated, and converted to a byte.
+// It is the id of the card of the board with the classic rules.
+// Boards without a free cell use the id of their card with the NoFreeCellRules, which starts with "5x5n15." and is 25 characters long.
+// Each number other than the free cell is shrunk to a 0-14 number and stored in 4 bits, so each two numbers are stored in a byte.
 // This results in a byte array that is (25-1)/2 = 12 characters long.
 // Since there are 8 bits in a byte the array uses 8 * 12 = 96 bits.
 // Base 64 uses 6 bits for each character, so the string will be 96 / 6 = 16 characters long.
@@ -116,79 +64,56 @@ func (b Board) ID() (string, error) {
 	if !b.isValid() {
 		return "", errors.New("board has duplicate/invalid numbers")
 	}
-	data := make([]byte, 0, 12)
-	for i := 0; i < len(b); i++ {
-		l := b.encodeNumber(i)
-		i++
-		r := b.encodeNumber(i)
-		ch := byte(l<<4 | r)
-		data = append(data, ch)
-		if i == 11 { // free cell
-			i++
-		}
-	}
-	id := base64.URLEncoding.EncodeToString(data)
-	return id, nil
+	return b.card().ID()
 }
 
 // BoardFromID converts the board id to a Board.
-// An error is returned if the id is for an invalid board.
+// The id can also be a readable id, which is typed by hand more easily.
+// An error is returned if the id is for an invalid board or is for a card with rules other than the classic rules or NoFreeCellRules.
 func BoardFromID(id string) (*Board, error) {
-	if len(id) != 16 {
-		return nil, errors.New("id must be 16 characters long")
+	if isReadableID(id) {
+		return boardFromReadableID(id)
 	}
-	data, err := base64.URLEncoding.DecodeString(id)
-	if err != nil {
-		return nil, errors.New("decoding board from id: " + err.Error())
+	c, err := CardFromID(id)
+	switch {
+	case err != nil:
+		return nil, err
+	case c.Rules != ClassicRules && c.Rules != NoFreeCellRules:
+		return nil, errors.New("id is not for a 5x5 board with numbers 1-75")
 	}
 	var b Board
-	i := 0
-	for _, ch := range data {
-		l, err := decodeBoardNumber(ch>>4, i)
-		if err != nil {
-			return nil, err
-		}
-		r, err := decodeBoardNumber(ch&15, i+1)
-		if err != nil {
-			return nil, err
-		}
-		b[i], b[i+1] = l, r
-		i += 2
-		if i == 12 { // free cell
-			i++
-		}
-	}
-	if !b.isValid() {
-		return nil, errors.New("board has duplicate/invalid numbers")
-	}
+	copy(b[:], c.Numbers)
 	return &b, nil
 }
 
-// encodeNumber converts the number to one in [0,15).
-func (b Board) encodeNumber(index int) int {
-	encodedNumber := int(b[index]-1) % 15 // (mod 15 is same as subtract n.Column()*15)
-	return encodedNumber
-}
-
-// decodeBoardNumber converts the [0,15) byte back to a number at the index in the board.
-func decodeBoardNumber(encodedNumber byte, index int) (Number, error) {
-	c := index / 5
-	n := Number(int(encodedNumber+1) + c*15)
-	return n, nil
+// card is the board as a card with the classic rules, or NoFreeCellRules if the board does not have a free cell.
+func (b Board) card() Card {
+	r := ClassicRules
+	if !b.HasFreeCell() {
+		r = NoFreeCellRules
+	}
+	return Card{
+		Rules:   r,
+		Numbers: b[:],
+	}
 }
 
 // numbers gets the numbers on the board that should be valid.
+// The center is only included if the board does not have a free cell.
 func (b Board) numbers() numbers {
-	nums := make(numbers, len(b)-1) // do not check the center
+	nums := make(numbers, len(b)-1, len(b)) // do not check the free cell
 	copy(nums, b[:12])
 	copy(nums[12:], b[13:])
+	if !b.HasFreeCell() {
+		nums = append(nums, b[12])
+	}
 	return nums
 }
 
-// isValid determines if the board has valid numbers, no duplicates, and the center is the zero value.
+// isValid determines if the board has valid numbers and no duplicates.  The center is the zero value or a number of the N column.
 func (b Board) isValid() bool {
 	switch {
-	case b[12] != 0, !b.numbers().Valid(), !b.numbersInCorrectColumns():
+	case !b.numbers().Valid(), !b.numbersInCorrectColumns():
 		return false
 	}
 	return true
@@ -197,7 +122,7 @@ func (b Board) isValid() bool {
 // numbersInCorrectColumns ensures numbers are in correct columns.
 func (b Board) numbersInCorrectColumns() bool {
 	for i, n := range b {
-		if i != 12 && n.Column() != i/5 {
+		if (i != 12 || n != 0) && n.Column() != i/5 {
 			return false
 		}
 	}
@@ -6,8 +6,8 @@ import (
 )
 
 func TestNewBoard(t *testing.T) {
-	GameResetter.Seed(1257894001) // create the board from a specific game
-	if want, got := &board1257894001, NewBoard(); !reflect.DeepEqual(want, got) {
+	d := NewDealer(1257894001) // create the board from a specific game
+	if want, got := &board1257894001, d.NewBoard(); !reflect.DeepEqual(want, got) {
 		t.Errorf("boards not equal:\nwanted: %v\ngot:    %v", want, got)
 	}
 }
@@ -143,7 +143,17 @@ func TestBoardIsValid(t *testing.T) {
 		},
 		{
 			Board: Board{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 33, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65},
-			name:  "non-zero center square",
+			name:  "number in center square, no free cell",
+			want:  true,
+		},
+		{
+			Board: Board{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 13, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65},
+			name:  "center number in wrong column",
+			want:  false,
+		},
+		{
+			Board: Board{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 31, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65},
+			name:  "center number duplicated",
 			want:  false,
 		},
 		{
@@ -161,6 +171,11 @@ func TestBoardIsValid(t *testing.T) {
 			name:  "random new board should be valid",
 			want:  true,
 		},
+		{
+			Board: *NewNoFreeCellBoard(),
+			name:  "random new board without a free cell should be valid",
+			want:  true,
+		},
 	}
 	for i, test := range tests {
 		if want, got := test.want, test.Board.isValid(); want != got {
@@ -187,10 +202,60 @@ func TestBoardBase64URLEncoding(t *testing.T) {
 	}
 }
 
+func TestNoFreeCellBoard(t *testing.T) {
+	b := Board{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 33, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65}
+	tests := []struct {
+		name       string
+		nums       []Number
+		wantLine   bool
+		wantFilled bool
+	}{
+		{"N column without center", []Number{31, 32, 34, 35}, false, false},
+		{"N column", []Number{31, 32, 33, 34, 35}, true, false},
+		{"middle row", []Number{3, 18, 33, 48, 63}, true, false},
+		{"middle row without center", []Number{3, 18, 48, 63}, false, false},
+		{"all but center", []Number{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65}, true, false},
+		{"all", b[:], true, true},
+	}
+	for i, test := range tests {
+		g := newTestingGame(t, test.nums)
+		switch {
+		case test.wantLine != b.HasLine(g):
+			t.Errorf("test %v (%v): wanted HasLine to be %v", i, test.name, test.wantLine)
+		case test.wantFilled != b.IsFilled(g):
+			t.Errorf("test %v (%v): wanted IsFilled to be %v", i, test.name, test.wantFilled)
+		}
+	}
+	id, err := b.ID()
+	switch {
+	case err != nil:
+		t.Fatalf("unwanted error getting board id: %v", err)
+	case len(id) != 25, id[:7] != "5x5n15.":
+		t.Errorf("wanted 25 character id starting with the rules, got %q", id)
+	}
+	got, err := BoardFromID(id)
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error getting board from id: %v", err)
+	case !reflect.DeepEqual(&b, got):
+		t.Errorf("boards not equal:\nwanted: %v\ngot:    %v", b, got)
+	case got.HasFreeCell():
+		t.Errorf("wanted board from id not to have a free cell")
+	}
+	if _, err := BoardFromID("4x4n25.AEQwBEMARDAEQw"); err == nil {
+		t.Errorf("wanted error getting board from card id with other rules")
+	}
+	if NewNoFreeCellBoard().HasFreeCell() {
+		t.Errorf("wanted new board without a free cell to have a number in the middle")
+	}
+}
+
 func newTestingGame(t *testing.T, nums []Number) Game {
 	t.Helper()
-	var g Game
-	copy(g.numbers[:], nums)
+	g := Game{
+		numbers: make([]Number, Balls75),
+	}
+	copy(g.numbers, nums)
 	g.numbersDrawn = len(nums)
 	return g
 }
@@ -0,0 +1,257 @@
+package bingo
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+)
+
+type (
+	// Card80 represents a 4*4 card for 80-ball games.
+	// The cells are stored by column, so the cell in column c and row r is at index c*4+r.
+	// The columns have numbers 1-20, 21-40, 41-60, and 61-80, and are colored by Card80Colors.  There is no free cell.
+	Card80 [16]Number
+	// Card30 represents a 3*3 card for 30-ball speed games.
+	// The cells are stored by column, so the cell in column c and row r is at index c*3+r.
+	// The columns have numbers 1-10, 11-20, and 21-30.  There is no free cell.
+	Card30 [9]Number
+	// cardShape describes the square cards of a game with a ball count.
+	// The numbers are split evenly between the columns.
+	cardShape struct {
+		size  int
+		balls int
+		// bits is how many bits are needed to store the offset of a number from the first number of its column.
+		bits int
+	}
+)
+
+// Card80Colors are the colors of the columns of 80-ball cards.
+var Card80Colors = []string{"red", "yellow", "blue", "white"}
+
+var (
+	// card80Shape is the shape of 80-ball cards.
+	card80Shape = cardShape{size: 4, balls: Balls80, bits: 5}
+	// card30Shape is the shape of 30-ball cards.
+	card30Shape = cardShape{size: 3, balls: Balls30, bits: 4}
+	// card80Patterns is the library of patterns for 80-ball cards, in display order.
+	card80Patterns = []Pattern{
+		{
+			Name:  "HasLine",
+			Label: "Line",
+			Masks: card80Shape.lineMasks(),
+		},
+		{
+			Name:  "FourCorners",
+			Label: "Four corners",
+			Masks: []Mask{cellsMask(0, 3, 12, 15)},
+		},
+		{
+			Name:  "CenterSquare",
+			Label: "Center square",
+			Masks: []Mask{cellsMask(5, 6, 9, 10)},
+		},
+		{
+			Name:  "IsFilled",
+			Label: "All cells",
+			Masks: []Mask{card80Shape.fullMask()},
+		},
+	}
+	// card30Patterns is the library of patterns for 30-ball cards, in display order.
+	card30Patterns = []Pattern{
+		{
+			Name:  "IsFilled",
+			Label: "All cells",
+			Masks: []Mask{card30Shape.fullMask()},
+		},
+		{
+			Name:  "HasLine",
+			Label: "Line",
+			Masks: card30Shape.lineMasks(),
+		},
+	}
+)
+
+// NewCard80 creates a card for 80-ball games.
+func NewCard80() *Card80 {
+	return defaultDealer.NewCard80()
+}
+
+// NewCard30 creates a card for 30-ball games.
+func NewCard30() *Card30 {
+	return defaultDealer.NewCard30()
+}
+
+// Patterns80 is the library of patterns for 80-ball cards.
+func Patterns80() []Pattern {
+	patterns := make([]Pattern, len(card80Patterns))
+	copy(patterns, card80Patterns)
+	return patterns
+}
+
+// Patterns30 is the library of patterns for 30-ball cards.
+func Patterns30() []Pattern {
+	patterns := make([]Pattern, len(card30Patterns))
+	copy(patterns, card30Patterns)
+	return patterns
+}
+
+// Matches determines if the drawn numbers of the game form the pattern on the card.
+// The card does not match games that do not have 80 balls.
+func (c Card80) Matches(g Game, p Pattern) bool {
+	if g.Balls() != Balls80 {
+		return false
+	}
+	return p.matches(cardDrawnMask(c[:], g))
+}
+
+// Matches determines if the drawn numbers of the game form the pattern on the card.
+// The card does not match games that do not have 30 balls.
+func (c Card30) Matches(g Game, p Pattern) bool {
+	if g.Balls() != Balls30 {
+		return false
+	}
+	return p.matches(cardDrawnMask(c[:], g))
+}
+
+// ID encodes the card into a base64 string.
+// Each number is stored in 5 bits as its offset from the first number of its column.
+// This is 16*5 = 80 bits, which are stored in 10 bytes.
+// Base 64 uses 6 bits for each character, so without padding the string will be 14 characters long.
+func (c Card80) ID() (string, error) {
+	return card80Shape.id(c[:])
+}
+
+// ID encodes the card into a base64 string.
+// Each number is stored in 4 bits as its offset from the first number of its column.
+// This is 9*4 = 36 bits, which are stored in 5 bytes.
+// Base 64 uses 6 bits for each character, so without padding the string will be 7 characters long.
+func (c Card30) ID() (string, error) {
+	return card30Shape.id(c[:])
+}
+
+// Card80FromID converts the card id to a Card80.
+// An error is returned if the id is for an invalid card.
+func Card80FromID(id string) (*Card80, error) {
+	var c Card80
+	if err := card80Shape.fromID(id, c[:]); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Card30FromID converts the card id to a Card30.
+// An error is returned if the id is for an invalid card.
+func Card30FromID(id string) (*Card30, error) {
+	var c Card30
+	if err := card30Shape.fromID(id, c[:]); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// cardDrawnMask is the set of cells of the card that have been drawn in the game.
+func cardDrawnMask(cells []Number, g Game) Mask {
+	nums := numberSet(g)
+	var m Mask
+	for i, n := range cells {
+		if _, ok := nums[n]; ok && n != 0 {
+			m |= 1 << i
+		}
+	}
+	return m
+}
+
+// columnRange is the first and last numbers in the column of the card.
+func (s cardShape) columnRange(c int) (first, last Number) {
+	perColumn := s.balls / s.size
+	return Number(c*perColumn + 1), Number((c + 1) * perColumn)
+}
+
+// deal fills the cells of the card with different numbers from each column, shuffled with the Resetter.
+func (s cardShape) deal(rs Resetter, cells []Number) {
+	for c := range s.size {
+		first, last := s.columnRange(c)
+		nums := make([]Number, 0, last-first+1)
+		for n := first; n <= last; n++ {
+			nums = append(nums, n)
+		}
+		rs.Shuffle(len(nums), func(i, j int) {
+			nums[i], nums[j] = nums[j], nums[i]
+		})
+		copy(cells[c*s.size:], nums[:s.size])
+	}
+}
+
+// id encodes the offsets of the numbers of the card from the first numbers of their columns.
+func (s cardShape) id(cells []Number) (string, error) {
+	if !s.valid(cells) {
+		return "", errors.New("card has duplicate/invalid numbers")
+	}
+	var w bitWriter
+	for i, n := range cells {
+		first, _ := s.columnRange(i / s.size)
+		w.write(int(n-first), s.bits)
+	}
+	id := base64.RawURLEncoding.EncodeToString(w.data)
+	return id, nil
+}
+
+// fromID decodes the numbers of the card from the id into the cells.
+func (s cardShape) fromID(id string, cells []Number) error {
+	dataLength := (len(cells)*s.bits + 7) / 8
+	idLength := (dataLength*8 + 5) / 6
+	if len(id) != idLength {
+		return errors.New("id must be " + strconv.Itoa(idLength) + " characters long")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return errors.New("decoding card from id: " + err.Error())
+	}
+	r := bitReader{data: data}
+	for i := range cells {
+		first, _ := s.columnRange(i / s.size)
+		cells[i] = first + Number(r.read(s.bits))
+	}
+	if !s.valid(cells) {
+		return errors.New("card has duplicate/invalid numbers")
+	}
+	return nil
+}
+
+// valid determines if the card has unique numbers in the correct columns.
+func (s cardShape) valid(cells []Number) bool {
+	for i, n := range cells {
+		first, last := s.columnRange(i / s.size)
+		if n < first || n > last {
+			return false
+		}
+	}
+	return validNumbers(cells, Number(s.balls))
+}
+
+// fullMask is the set of all cells on the card.
+func (s cardShape) fullMask() Mask {
+	return 1<<(s.size*s.size) - 1
+}
+
+// lineMasks creates the masks of the columns, rows, and two diagonals of the card.
+func (s cardShape) lineMasks() []Mask {
+	masks := make([]Mask, 0, s.size*2+2)
+	column := Mask(1<<s.size - 1)
+	for c := range s.size {
+		masks = append(masks, column<<(c*s.size))
+	}
+	for r := range s.size {
+		var row Mask
+		for c := range s.size {
+			row |= 1 << (c*s.size + r)
+		}
+		masks = append(masks, row)
+	}
+	var leading, trailing Mask
+	for j := range s.size {
+		leading |= 1 << (j*s.size + j)
+		trailing |= 1 << (j*s.size + s.size - 1 - j)
+	}
+	return append(masks, leading, trailing)
+}
@@ -0,0 +1,181 @@
+package bingo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewCard80(t *testing.T) {
+	d := NewDealer(1257894001)
+	for i := 0; i < 20; i++ {
+		c := d.NewCard80()
+		id, err := c.ID()
+		if err != nil {
+			t.Fatalf("card %v: unwanted error getting id of %v: %v", i, *c, err)
+		}
+		got, err := Card80FromID(id)
+		switch {
+		case err != nil:
+			t.Fatalf("card %v: unwanted error getting card from id %q: %v", i, id, err)
+		case len(id) != 14:
+			t.Errorf("card %v: wanted 14 character id, got %q", i, id)
+		case !reflect.DeepEqual(c, got):
+			t.Errorf("card %v: cards not equal:\nwanted: %v\ngot:    %v", i, *c, *got)
+		}
+	}
+}
+
+func TestNewCard30(t *testing.T) {
+	d := NewDealer(1257894001)
+	for i := 0; i < 20; i++ {
+		c := d.NewCard30()
+		id, err := c.ID()
+		if err != nil {
+			t.Fatalf("card %v: unwanted error getting id of %v: %v", i, *c, err)
+		}
+		got, err := Card30FromID(id)
+		switch {
+		case err != nil:
+			t.Fatalf("card %v: unwanted error getting card from id %q: %v", i, id, err)
+		case len(id) != 7:
+			t.Errorf("card %v: wanted 7 character id, got %q", i, id)
+		case !reflect.DeepEqual(c, got):
+			t.Errorf("card %v: cards not equal:\nwanted: %v\ngot:    %v", i, *c, *got)
+		}
+	}
+}
+
+func TestCardIDInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   func() (string, error)
+	}{
+		{"empty 80-ball card", Card80{}.ID},
+		{"80-ball number in wrong column", Card80{21, 2, 3, 4, 1, 22, 23, 24, 41, 42, 43, 44, 61, 62, 63, 64}.ID},
+		{"80-ball duplicate number", Card80{1, 1, 3, 4, 21, 22, 23, 24, 41, 42, 43, 44, 61, 62, 63, 64}.ID},
+		{"empty 30-ball card", Card30{}.ID},
+		{"30-ball number too large", Card30{1, 2, 3, 11, 12, 13, 21, 22, 31}.ID},
+	}
+	for i, test := range tests {
+		if _, err := test.id(); err == nil {
+			t.Errorf("test %v (%v): wanted error getting id", i, test.name)
+		}
+	}
+}
+
+func TestCardFromIDInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		fromID func(id string) error
+		id     string
+	}{
+		{"80-ball short id", func(id string) error { _, err := Card80FromID(id); return err }, "AAAAAAA"},
+		{"80-ball bad base64", func(id string) error { _, err := Card80FromID(id); return err }, "AAAAAAAAAAAA!!"},
+		{"80-ball duplicate numbers", func(id string) error { _, err := Card80FromID(id); return err }, "AAAAAAAAAAAAAA"},
+		{"30-ball classic board id", func(id string) error { _, err := Card30FromID(id); return err }, "5zuTsMm6CTZAs7ad"},
+		{"30-ball offset too large", func(id string) error { _, err := Card30FromID(id); return err }, "_______"},
+	}
+	for i, test := range tests {
+		if err := test.fromID(test.id); err == nil {
+			t.Errorf("test %v (%v): wanted error getting card from %q", i, test.name, test.id)
+		}
+	}
+}
+
+func TestCard80Matches(t *testing.T) {
+	c := Card80{
+		1, 2, 3, 4,
+		21, 22, 23, 24,
+		41, 42, 43, 44,
+		61, 62, 63, 64,
+	}
+	tests := []struct {
+		pattern string
+		nums    []Number
+		want    bool
+	}{
+		{"HasLine", []Number{1, 2, 3}, false},
+		{"HasLine", []Number{1, 2, 3, 4}, true},
+		{"HasLine", []Number{2, 22, 42, 62}, true},
+		{"HasLine", []Number{1, 22, 43, 64}, true},
+		{"HasLine", []Number{4, 23, 42, 61}, true},
+		{"FourCorners", []Number{1, 4, 61, 64}, true},
+		{"FourCorners", []Number{1, 4, 61, 63}, false},
+		{"CenterSquare", []Number{22, 23, 42, 43}, true},
+		{"IsFilled", []Number{1, 2, 3, 4, 21, 22, 23, 24, 41, 42, 43, 44, 61, 62, 63}, false},
+		{"IsFilled", []Number{1, 2, 3, 4, 21, 22, 23, 24, 41, 42, 43, 44, 61, 62, 63, 64}, true},
+	}
+	for i, test := range tests {
+		p := findTestingPattern(t, Patterns80(), test.pattern)
+		g := newTestingGameBalls(t, Balls80, test.nums)
+		if want, got := test.want, c.Matches(g, p); want != got {
+			t.Errorf("test %v (%v): wanted %v, got %v", i, test.pattern, want, got)
+		}
+	}
+}
+
+func TestCard30Matches(t *testing.T) {
+	c := Card30{1, 2, 3, 11, 12, 13, 21, 22, 23}
+	tests := []struct {
+		pattern string
+		nums    []Number
+		want    bool
+	}{
+		{"IsFilled", []Number{1, 2, 3, 11, 12, 13, 21, 22}, false},
+		{"IsFilled", []Number{1, 2, 3, 11, 12, 13, 21, 22, 23}, true},
+		{"HasLine", []Number{3, 12, 21}, true},
+		{"HasLine", []Number{3, 12, 22}, false},
+	}
+	for i, test := range tests {
+		p := findTestingPattern(t, Patterns30(), test.pattern)
+		g := newTestingGameBalls(t, Balls30, test.nums)
+		if want, got := test.want, c.Matches(g, p); want != got {
+			t.Errorf("test %v (%v): wanted %v, got %v", i, test.pattern, want, got)
+		}
+	}
+}
+
+func TestCardMatchesOtherBalls(t *testing.T) {
+	c80 := Card80{1, 2, 3, 4}
+	c30 := Card30{1, 2, 3}
+	nums := []Number{1, 2, 3, 4}
+	tests := []struct {
+		name  string
+		balls int
+		match func(g Game) bool
+	}{
+		{"80-ball card, 75-ball game", Balls75, func(g Game) bool { return c80.Matches(g, findTestingPattern(t, Patterns80(), "HasLine")) }},
+		{"80-ball card, 90-ball game", Balls90, func(g Game) bool { return c80.Matches(g, findTestingPattern(t, Patterns80(), "HasLine")) }},
+		{"30-ball card, 75-ball game", Balls75, func(g Game) bool { return c30.Matches(g, findTestingPattern(t, Patterns30(), "HasLine")) }},
+		{"30-ball card, 80-ball game", Balls80, func(g Game) bool { return c30.Matches(g, findTestingPattern(t, Patterns30(), "HasLine")) }},
+	}
+	for i, test := range tests {
+		g := newTestingGameBalls(t, test.balls, nums)
+		if test.match(g) {
+			t.Errorf("test %v (%v): wanted card not to match game with other ball count", i, test.name)
+		}
+	}
+}
+
+// findTestingPattern gets the pattern with the name from the patterns.
+func findTestingPattern(t *testing.T, patterns []Pattern, name string) Pattern {
+	t.Helper()
+	for _, p := range patterns {
+		if p.Name == name {
+			return p
+		}
+	}
+	t.Fatalf("pattern %q not found", name)
+	return Pattern{}
+}
+
+// newTestingGameBalls creates a game with the ball count that has drawn the numbers.
+func newTestingGameBalls(t *testing.T, balls int, nums []Number) Game {
+	t.Helper()
+	g := Game{
+		numbers: make([]Number, balls),
+	}
+	copy(g.numbers, nums)
+	g.numbersDrawn = len(nums)
+	return g
+}
@@ -0,0 +1,172 @@
+package bingo
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// cryptoShuffler is a Resetter that shuffles with random values from crypto/rand, so shuffles cannot be predicted.
+type cryptoShuffler struct{}
+
+const (
+	// commitPrefix starts the ids of games that are committed to.
+	commitPrefix = "c"
+	// saltLength is the number of random bytes that are hashed with the numbers of committed games.
+	saltLength = 16
+)
+
+// CryptoResetter shuffles with a cryptographically secure random number generator.
+// It should be used to shuffle games that are played for prizes.  It cannot be seeded.
+var CryptoResetter Resetter = cryptoShuffler{}
+
+// Reset shuffles all the numbers of the game with secure random values, clearing the drawn numbers.
+func (s cryptoShuffler) Reset(g *Game) {
+	g.reset(s.Shuffle)
+}
+
+// Seed does nothing because secure shuffles cannot be predicted.
+func (cryptoShuffler) Seed(seed int64) {}
+
+// Shuffle randomizes the order of n elements with the Fisher-Yates algorithm, using swap to exchange the elements at two indexes.
+func (cryptoShuffler) Shuffle(n int, swap func(i, j int)) {
+	shuffle(n, swap, cryptoUint64)
+}
+
+// cryptoUint64 is a secure random value.
+func cryptoUint64() uint64 {
+	var b [8]byte
+	rand.Read(b[:]) // never returns an error
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// Commit shuffles the numbers of the game with the CryptoResetter and creates a random salt for the game.
+// The Commitment of the game can then be published before numbers are drawn.  The numbers are not shuffled again when the first number is drawn.
+// The ID of the game no longer has the salt and order, so the game must be saved with its SecretID.
+// When the game is over, the order of the numbers and the salt are revealed so anyone can check them with VerifyCommitment.
+// An error is returned if numbers have been drawn.
+func (g *Game) Commit() error {
+	g.normalizeNumbersDrawn()
+	if g.numbersDrawn != 0 {
+		return errors.New("games can only be committed to before numbers are drawn")
+	}
+	CryptoResetter.Reset(g)
+	g.salt = make([]byte, saltLength)
+	rand.Read(g.salt) // never returns an error
+	return nil
+}
+
+// Committed determines if the game has a commitment to the order of its numbers.
+func (g Game) Committed() bool {
+	return g.salt != nil
+}
+
+// Commitment is the hex encoded SHA-256 hash of the salt of the game followed by its numbers, one byte each, in the order they are drawn.
+// It is empty if the game is not committed.
+func (g Game) Commitment() string {
+	if g.salt == nil {
+		return ""
+	}
+	return commitment(g.salt, g.numbers)
+}
+
+// Salt is the hex encoded salt of the game, or an empty string if the game is not committed.
+// It is only revealed when the game is over, so it is also empty while numbers are left to draw.
+func (g Game) Salt() string {
+	if g.NumbersLeft() != 0 {
+		return ""
+	}
+	return hex.EncodeToString(g.salt)
+}
+
+// Order is the order that all the numbers of the game are drawn in.
+// It is only revealed when the game is over, so it is nil while numbers are left to draw.
+func (g Game) Order() []Number {
+	if g.NumbersLeft() != 0 {
+		return nil
+	}
+	nums := make([]Number, len(g.numbers))
+	copy(nums, g.numbers)
+	return nums
+}
+
+// VerifyCommitment checks that the commitment that was published for a game is the hash of its revealed salt and order of numbers.
+// The drawn numbers, which can be empty, must be the start of the order, so no numbers were drawn out of order.
+func VerifyCommitment(commitmentHex, saltHex string, order, drawn []Number) error {
+	salt, err := hex.DecodeString(saltHex)
+	switch {
+	case err != nil:
+		return errors.New("decoding salt: " + err.Error())
+	case len(order) == 0, len(order) > 255:
+		return errors.New("order must have between 1 and 255 numbers")
+	case !validNumbers(order, Number(len(order))):
+		return errors.New("order must have each number from 1 to the ball count once")
+	case len(drawn) > len(order):
+		return errors.New("more numbers drawn than in the order")
+	}
+	want := commitment(salt, order)
+	if subtle.ConstantTimeCompare([]byte(want), []byte(strings.ToLower(commitmentHex))) != 1 {
+		return errors.New("commitment is not the hash of the salt and order")
+	}
+	for i, n := range drawn {
+		if n != order[i] {
+			return errors.New("drawn number " + strconv.Itoa(n.Value()) + " was not drawn in order")
+		}
+	}
+	return nil
+}
+
+// commitment is the hex encoded SHA-256 hash of the salt followed by the numbers.
+func commitment(salt []byte, numbers []Number) string {
+	h := sha256.New()
+	h.Write(salt)
+	for _, n := range numbers {
+		h.Write([]byte{byte(n)})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// committedID is the public id of a committed game, which has the commitment and the drawn numbers, one byte each, but not the salt or the numbers that are left.
+func committedID(commitment string, drawn []Number) string {
+	data := make([]byte, len(drawn))
+	for i, n := range drawn {
+		data[i] = byte(n)
+	}
+	return commitPrefix + commitment + rulesSeparator + strconv.Itoa(len(drawn)) + "-" + base64.RawURLEncoding.EncodeToString(data)
+}
+
+// committedGameFromID creates a game from the secret id of a committed game, which has the salt and the id of the game without it.
+// Public ids of committed games, which have the commitment instead of the salt, do not have the order of the numbers, so they cannot be read.
+func committedGameFromID(id string) (*Game, error) {
+	saltHex, gameID, ok := strings.Cut(strings.TrimPrefix(id, commitPrefix), rulesSeparator)
+	if !ok {
+		return nil, errors.New("could not split committed id string into salt and game id")
+	}
+	if len(saltHex) == hex.EncodedLen(sha256.Size) {
+		return nil, errors.New("ids of committed games do not have the order of the numbers, which is kept secret until the game is over")
+	}
+	salt, err := hex.DecodeString(saltHex)
+	switch {
+	case err != nil:
+		return nil, errors.New("decoding salt: " + err.Error())
+	case len(salt) != saltLength:
+		return nil, errors.New("salt has wrong length")
+	case strings.HasPrefix(gameID, seedPrefix), strings.HasPrefix(gameID, commitPrefix):
+		return nil, errors.New("committed games cannot be seeded or committed again")
+	}
+	g, err := GameFromID(gameID)
+	switch {
+	case err != nil:
+		return nil, err
+	case len(g.numbers) == 0:
+		return nil, errors.New("committed games must have numbers")
+	}
+	g.salt = salt
+	return g, nil
+}
@@ -0,0 +1,184 @@
+package bingo
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCryptoResetter(t *testing.T) {
+	var g Game
+	CryptoResetter.Seed(1257894001) // should do nothing
+	CryptoResetter.Reset(&g)
+	if !g.valid() {
+		t.Errorf("wanted valid game after reset: %v", g.numbers)
+	}
+	var g2 Game
+	CryptoResetter.Seed(1257894001)
+	CryptoResetter.Reset(&g2)
+	if reflect.DeepEqual(g, g2) {
+		t.Errorf("wanted seeding to not make secure shuffles the same: %v", g.numbers)
+	}
+}
+
+func TestGameCommit(t *testing.T) {
+	g, err := NewGame(Balls30)
+	if err != nil {
+		t.Fatalf("unwanted error creating game: %v", err)
+	}
+	if err := g.Commit(); err != nil {
+		t.Fatalf("unwanted error committing game: %v", err)
+	}
+	commitment := g.Commitment()
+	secretOrder := make([]Number, len(g.numbers))
+	copy(secretOrder, g.numbers)
+	g.DrawNumber()
+	g.DrawNumber()
+	switch {
+	case !g.Committed():
+		t.Errorf("wanted game to be committed")
+	case commitment != g.Commitment():
+		t.Errorf("wanted drawing numbers to not change commitment")
+	case !reflect.DeepEqual(secretOrder[:2], g.DrawnNumbers()):
+		t.Errorf("wanted first numbers of order to be drawn: %v, got %v", secretOrder, g.DrawnNumbers())
+	case len(g.Salt()) != 0, g.Order() != nil:
+		t.Errorf("wanted salt and order to not be revealed while numbers are left: %q, %v", g.Salt(), g.Order())
+	case len(commitment) != 64:
+		t.Errorf("wanted hex SHA-256 commitment, got %q", commitment)
+	}
+	if err := g.Commit(); err == nil {
+		t.Errorf("wanted error committing game after numbers are drawn")
+	}
+	secretID, err := g.SecretID()
+	if err != nil {
+		t.Fatalf("unwanted error getting secret id: %v", err)
+	}
+	got, err := GameFromID(secretID)
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error getting game from secret id %q: %v", secretID, err)
+	case !reflect.DeepEqual(g, got):
+		t.Errorf("games not equal:\nwanted: %v\ngot:    %v", g, got)
+	}
+	for g.NumbersLeft() != 0 {
+		g.DrawNumber()
+	}
+	switch {
+	case len(g.Salt()) != saltLength*2:
+		t.Errorf("wanted hex salt of %v bytes when game is over, got %q", saltLength, g.Salt())
+	case !reflect.DeepEqual(secretOrder, g.Order()):
+		t.Errorf("wanted order to be revealed when game is over: %v, got %v", secretOrder, g.Order())
+	}
+	if err := VerifyCommitment(commitment, g.Salt(), g.Order(), g.DrawnNumbers()); err != nil {
+		t.Errorf("unwanted error verifying commitment: %v", err)
+	}
+	NewDealer(1257894001).Reset(g)
+	if g.Committed() || len(g.Commitment()) != 0 {
+		t.Errorf("wanted game to not be committed after being reset")
+	}
+}
+
+func TestCommittedGameID(t *testing.T) {
+	g, err := NewGame(Balls30)
+	if err != nil {
+		t.Fatalf("unwanted error creating game: %v", err)
+	}
+	if err := g.Commit(); err != nil {
+		t.Fatalf("unwanted error committing game: %v", err)
+	}
+	g.DrawNumber()
+	g.DrawNumber()
+	id, err := g.ID()
+	if err != nil {
+		t.Fatalf("unwanted error getting id: %v", err)
+	}
+	drawn := g.DrawnNumbers()
+	want := "c" + g.Commitment() + ".2-" + base64.RawURLEncoding.EncodeToString([]byte{byte(drawn[0]), byte(drawn[1])})
+	switch {
+	case want != id:
+		t.Errorf("ids not equal:\nwanted: %q\ngot:    %q", want, id)
+	case strings.Contains(id, hex.EncodeToString(g.salt)):
+		t.Errorf("wanted id to not have salt: %q", id)
+	}
+	if _, err := GameFromID(id); err == nil {
+		t.Errorf("wanted error getting game from id that does not have its order: %q", id)
+	}
+}
+
+func TestCommittedClassicGameID(t *testing.T) {
+	var g Game
+	if err := g.Commit(); err != nil {
+		t.Fatalf("unwanted error committing game: %v", err)
+	}
+	id, err := g.SecretID()
+	if err != nil {
+		t.Fatalf("unwanted error getting secret id: %v", err)
+	}
+	got, err := GameFromID(id)
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error getting game from id %q: %v", id, err)
+	case !reflect.DeepEqual(&g, got):
+		t.Errorf("games not equal:\nwanted: %v\ngot:    %v", g, got)
+	}
+}
+
+func TestVerifyCommitment(t *testing.T) {
+	salt := "000102030405060708090a0b0c0d0e0f"
+	order := []Number{3, 1, 2}
+	// sha256 of the bytes 00-0f, 03, 01, 02
+	commitment := commitment([]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, order)
+	tests := []struct {
+		name       string
+		commitment string
+		salt       string
+		order      []Number
+		drawn      []Number
+		wantOk     bool
+	}{
+		{"ok", commitment, salt, order, nil, true},
+		{"ok with drawn numbers", commitment, salt, order, []Number{3, 1}, true},
+		{"upper case commitment", strings.ToUpper(commitment), salt, order, nil, true},
+		{"wrong commitment", "abc", salt, order, nil, false},
+		{"bad salt", commitment, "xyz", order, nil, false},
+		{"different salt", commitment, "00", order, nil, false},
+		{"reordered", commitment, salt, []Number{1, 3, 2}, nil, false},
+		{"no numbers", commitment, salt, nil, nil, false},
+		{"duplicate numbers", commitment, salt, []Number{3, 3, 2}, nil, false},
+		{"drawn out of order", commitment, salt, order, []Number{1}, false},
+		{"too many drawn", commitment, salt, order, []Number{3, 1, 2, 4}, false},
+	}
+	for i, test := range tests {
+		err := VerifyCommitment(test.commitment, test.salt, test.order, test.drawn)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("test %v (%v): wanted error", i, test.name)
+			}
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+		}
+	}
+}
+
+func TestGameFromCommittedIDInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"no game id", "c000102030405060708090a0b0c0d0e0f"},
+		{"bad salt", "cxyz.0"},
+		{"short salt", "c0001.0"},
+		{"classic game without numbers", "c000102030405060708090a0b0c0d0e0f.0"},
+		{"seeded", "c000102030405060708090a0b0c0d0e0f.s42-0"},
+		{"committed twice", "c000102030405060708090a0b0c0d0e0f.c000102030405060708090a0b0c0d0e0f.0"},
+		{"public id without order", "c" + strings.Repeat("ab", 32) + ".0-"},
+	}
+	for i, test := range tests {
+		if _, err := GameFromID(test.id); err == nil {
+			t.Errorf("test %v (%v): wanted error getting game from %q", i, test.name, test.id)
+		}
+	}
+}
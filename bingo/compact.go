@@ -0,0 +1,107 @@
+package bingo
+
+import (
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// compactPrefix starts the part of game ids after the rules that stores the numbers of the game as a permutation.
+// The "v2" is the version of the encoding.  Legacy game ids, which store each number in a byte, do not have a version.
+const compactPrefix = "v2-"
+
+// compactID encodes how many numbers have been drawn and the order of the numbers of the game, such as v2-3-....
+// The order is stored as its Lehmer code: the position of each number among the numbers that come after it, in the mixed radix of the count of those numbers.
+// The code is the rank of the order among all orders of the numbers, so a 75-ball game needs 365 bits, which is 62 base64 characters.
+func (g Game) compactID() string {
+	n := len(g.numbers)
+	v := new(big.Int)
+	radix, digit := new(big.Int), new(big.Int)
+	used := make([]bool, n+1)
+	for i, num := range g.numbers {
+		pos := 0
+		for n2 := Number(1); n2 < num; n2++ {
+			if !used[n2] {
+				pos++
+			}
+		}
+		used[num] = true
+		v.Mul(v, radix.SetInt64(int64(n-i)))
+		v.Add(v, digit.SetInt64(int64(pos)))
+	}
+	data := v.FillBytes(make([]byte, permutationBytes(n)))
+	nums := base64.RawURLEncoding.EncodeToString(data)
+	return compactPrefix + strconv.Itoa(g.numbersDrawn) + "-" + nums
+}
+
+// compactGameFromID creates a game from the part of a compact game id after the rules, if any.
+// The ball count of games without rules is found from the length of the encoded numbers.
+func compactGameFromID(id string, r *Rules) (*Game, error) {
+	numbersDrawnStr, numsStr, ok := strings.Cut(strings.TrimPrefix(id, compactPrefix), "-")
+	if !ok {
+		return nil, errors.New("could not split compact id string into numbersDrawn and numbers")
+	}
+	numbersDrawn, err := strconv.Atoi(numbersDrawnStr)
+	if err != nil {
+		return nil, errors.New("parsing numbersDrawn: " + err.Error())
+	}
+	data, err := base64.RawURLEncoding.DecodeString(numsStr)
+	if err != nil {
+		return nil, errors.New("decoding game numbers: " + err.Error())
+	}
+	n := 0
+	switch {
+	case r != nil:
+		if len(data) == permutationBytes(r.Balls()) {
+			n = r.Balls()
+		}
+	default:
+		for _, balls := range []int{Balls30, Balls75, Balls80, Balls90} {
+			if len(data) == permutationBytes(balls) {
+				n = balls
+			}
+		}
+	}
+	switch {
+	case n == 0:
+		return nil, errors.New("decoded numbers too large/small")
+	case numbersDrawn < 0 || numbersDrawn > n:
+		return nil, errors.New("numbersDrawn must be between 0 and the number of balls")
+	}
+	v := new(big.Int).SetBytes(data)
+	radix, digit := new(big.Int), new(big.Int)
+	positions := make([]int, n)
+	for i := n - 1; i >= 0; i-- {
+		v.DivMod(v, radix.SetInt64(int64(n-i)), digit)
+		positions[i] = int(digit.Int64())
+	}
+	if v.Sign() != 0 {
+		return nil, errors.New("decoded numbers are not an order of the balls")
+	}
+	g := Game{
+		numbers:      make([]Number, n),
+		numbersDrawn: numbersDrawn,
+		rules:        r,
+	}
+	used := make([]bool, n+1)
+	for i, pos := range positions {
+		num := Number(1)
+		for ; used[num] || pos > 0; num++ {
+			if !used[num] {
+				pos--
+			}
+		}
+		used[num] = true
+		g.numbers[i] = num
+	}
+	return &g, nil
+}
+
+// permutationBytes is the number of bytes needed to store the rank of any order of n numbers, which is less than n factorial.
+func permutationBytes(n int) int {
+	f := new(big.Int).MulRange(1, int64(n))
+	f.Sub(f, big.NewInt(1))
+	return (f.BitLen() + 7) / 8
+}
@@ -0,0 +1,156 @@
+package bingo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGameFromLegacyID(t *testing.T) {
+	tests := []struct {
+		name     string
+		legacyID string
+		wantID   string
+	}{
+		{"3 drawn", "3-QSMsSRIBJSlFPkgNCR4OPAIQQEcYFQZLNx09NgwXNSowKxxGDzEuP0QbHy9DNDgZCwQnO0ITGkoWJC0KMiIDBTkUIBEoCDoHMyYh", "v2-3-CRSDqllsTMYcbziezWB_vu1sAxviaFna8MzTz-yb2KKiJPDnMYlAJ8RF3oR3fQ"},
+		{"all drawn", "75-Oh0hOyw9JDwQDC4yKS8aQzk3HiI1GBUmCzgjMA80BBsDKicIDQIBLTMxGSBIHyUoEUUSKxdBNgc_HBMFBgkWPg4UCkJKREdJS0ZA", "v2-75-CBUdnTZka9VRvU2gx3L-eflfsKg7qvWQLWZrkaYypMEPfoV9-gdmwn4Yxh69-Q"},
+		{"'5zuTsMm6CTZAs7ad' first", "5-DwgEDAoTGxAcGSopHygxNDIuOUBIQ0ZKAQIDBQYHCQsNDhESFBUWFxgaHR4gISIjJCUmJyssLS8wMzU2Nzg6Ozw9Pj9BQkRFR0lL", "v2-5-Afw7gy6ui4e5qrGSKpge9xWBhNDH0BBROdLpifV1KxyCnScOQDDuBQAAAAAAAA"},
+	}
+	for i, test := range tests {
+		legacy, err := GameFromID(test.legacyID)
+		if err != nil {
+			t.Errorf("test %v (%v): unwanted error reading legacy id: %v", i, test.name, err)
+			continue
+		}
+		compact, err := GameFromID(test.wantID)
+		if err != nil {
+			t.Errorf("test %v (%v): unwanted error reading compact id: %v", i, test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(legacy, compact) {
+			t.Errorf("test %v (%v): games not equal:\nlegacy:  %v\ncompact: %v", i, test.name, legacy, compact)
+		}
+		got, err := legacy.ID()
+		switch {
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error getting id: %v", i, test.name, err)
+		case test.wantID != got:
+			t.Errorf("test %v (%v): ids not equal:\nwanted: %q\ngot:    %q", i, test.name, test.wantID, got)
+		}
+	}
+}
+
+func TestGameCompactIDLength(t *testing.T) {
+	tests := []struct {
+		balls      int
+		wantLength int
+	}{
+		{Balls30, 19},
+		{Balls75, 62},
+		{Balls80, 67},
+		{Balls90, 78},
+	}
+	for i, test := range tests {
+		g, err := NewGame(test.balls)
+		if err != nil {
+			t.Fatalf("test %v: creating game: %v", i, err)
+		}
+		g.DrawNumber()
+		id, err := g.ID()
+		if err != nil {
+			t.Errorf("test %v: unwanted error getting id: %v", i, err)
+			continue
+		}
+		_, nums, _ := strings.Cut(strings.TrimPrefix(id, compactPrefix), "-")
+		if want, got := test.wantLength, len(nums); want != got {
+			t.Errorf("test %v: encoded numbers of %v ball game not %v characters long: got %v: %q", i, test.balls, want, got, id)
+		}
+	}
+}
+
+func TestGameFromCompactIDInvalid(t *testing.T) {
+	tests := []struct {
+		id   string
+		name string
+	}{
+		{"v2-", "no hyphen"},
+		{"v2-a-" + strings.Repeat("A", 62), "bad numbersDrawn"},
+		{"v2--1-" + strings.Repeat("A", 62), "negative numbersDrawn"},
+		{"v2-76-" + strings.Repeat("A", 62), "too many numbersDrawn"},
+		{"v2-1-!@#%*!@$", "bad numbers"},
+		{"v2-1-AAAA", "unsupported ball count"},
+		{"v2-1-" + strings.Repeat("_", 62), "numbers not valid (too large)"},
+		{"4x4n25.v2-1-" + strings.Repeat("A", 62), "ball count not for rules"},
+		{"5x5n15f22.v2-1-" + strings.Repeat("A", 62), "classic rules"},
+	}
+	for i, test := range tests {
+		if _, err := GameFromID(test.id); err == nil {
+			t.Errorf("test %v (%v): wanted error getting game from %q", i, test.name, test.id)
+		}
+	}
+}
+
+func FuzzGameFromID(f *testing.F) {
+	for _, test := range gameTests {
+		f.Add(test.wantID)
+	}
+	f.Add("3-QSMsSRIBJSlFPkgNCR4OPAIQQEcYFQZLNx09NgwXNSowKxxGDzEuP0QbHy9DNDgZCwQnO0ITGkoWJC0KMiIDBTkUIBEoCDoHMyYh")
+	f.Add("4x4n25.v2-7-" + strings.Repeat("A", 62))
+	f.Add("s42-3")
+	f.Fuzz(func(t *testing.T, id string) {
+		g, err := GameFromID(id)
+		if err != nil {
+			return
+		}
+		id2, err := g.SecretID() // the ids of committed games do not have their order
+		if err != nil {
+			t.Fatalf("unwanted error getting id of game from %q: %v", id, err)
+		}
+		g2, err := GameFromID(id2)
+		if err != nil {
+			t.Fatalf("unwanted error getting game from %q (from %q): %v", id2, id, err)
+		}
+		// classic games that have not drawn numbers are not shuffled until the first number is drawn, so only the ids are compared
+		id3, err := g2.SecretID()
+		switch {
+		case err != nil:
+			t.Errorf("unwanted error getting id of game from %q (from %q): %v", id2, id, err)
+		case id2 != id3:
+			t.Errorf("ids not equal after round trip of %q:\nwanted: %q\ngot:    %q", id, id2, id3)
+		}
+	})
+}
+
+func FuzzGameCompactID(f *testing.F) {
+	f.Add(int64(1257894001), 0, 3)
+	f.Add(int64(42), 1, 30)
+	f.Add(int64(7), 2, 80)
+	f.Add(int64(-1), 3, 90)
+	f.Add(int64(0), 4, 16)
+	f.Fuzz(func(t *testing.T, seed int64, kind, numbersDrawn int) {
+		var g *Game
+		var err error
+		switch k := uint(kind) % 5; k {
+		case 4:
+			g, err = Rules{Width: 4, Height: 4, ColumnNumbers: 25}.NewGame()
+		default:
+			g, err = NewGame([]int{Balls75, Balls30, Balls80, Balls90}[k])
+		}
+		if err != nil {
+			t.Fatalf("creating game: %v", err)
+		}
+		NewDealer(seed).Reset(g)
+		g.numbersDrawn = int(uint(numbersDrawn) % uint(len(g.numbers)+1))
+		id, err := g.ID()
+		if err != nil {
+			t.Fatalf("unwanted error getting id: %v", err)
+		}
+		got, err := GameFromID(id)
+		switch {
+		case err != nil:
+			t.Errorf("unwanted error getting game from %q: %v", id, err)
+		case !reflect.DeepEqual(g, got):
+			t.Errorf("games not equal after round trip through %q:\nwanted: %v\ngot:    %v", id, g, got)
+		}
+	})
+}
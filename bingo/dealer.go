@@ -0,0 +1,108 @@
+package bingo
+
+import (
+	"sync"
+	"time"
+)
+
+// Dealer shuffles the numbers of games, boards, cards, and tickets with a Resetter.
+// It is safe for concurrent use: the Resetter is locked while each game or card is shuffled.
+// Dealers are created with NewDealer or NewDealerFrom.
+type Dealer struct {
+	mu sync.Mutex
+	rs Resetter
+}
+
+// defaultDealer is used by the functions that do not take a dealer, such as NewBoard and Game.DrawNumber.
+// It is seeded to the time it is created.
+var defaultDealer = NewDealer(time.Now().UnixNano())
+
+// NewDealer creates a dealer that shuffles with a math/rand source from the seed.
+// Dealers with the same seed deal the same games and boards, so a constant seed should only be used when testing.
+func NewDealer(seed int64) *Dealer {
+	return NewDealerFrom(newShuffler(seed))
+}
+
+// NewDealerFrom creates a dealer that shuffles with the Resetter, such as the CryptoResetter for games played for prizes.
+func NewDealerFrom(rs Resetter) *Dealer {
+	return &Dealer{
+		rs: rs,
+	}
+}
+
+// Reset clears the drawn numbers of the game and shuffles all of its numbers.
+func (d *Dealer) Reset(g *Game) {
+	d.deal(func(rs Resetter) {
+		rs.Reset(g)
+	})
+}
+
+// DrawNumber moves the next available number of the game to its DrawnNumbers.
+// The game is shuffled by the dealer if no numbers have been drawn, unless it was shuffled from a seed or committed to.
+func (d *Dealer) DrawNumber(g *Game) {
+	d.deal(g.drawNumber)
+}
+
+// NewBoard creates a board with the classic rules.
+func (d *Dealer) NewBoard() *Board {
+	return d.newBoard(ClassicRules)
+}
+
+// NewNoFreeCellBoard creates a board with a number in the middle square instead of the free cell.
+func (d *Dealer) NewNoFreeCellBoard() *Board {
+	return d.newBoard(NoFreeCellRules)
+}
+
+// NewCard creates a card with the rules.  An error is returned if the rules are not valid.
+func (d *Dealer) NewCard(r Rules) (*Card, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	var c *Card
+	d.deal(func(rs Resetter) {
+		c = r.newCard(rs)
+	})
+	return c, nil
+}
+
+// NewCard80 creates a card for 80-ball games.
+func (d *Dealer) NewCard80() *Card80 {
+	var c Card80
+	d.deal(func(rs Resetter) {
+		card80Shape.deal(rs, c[:])
+	})
+	return &c
+}
+
+// NewCard30 creates a card for 30-ball games.
+func (d *Dealer) NewCard30() *Card30 {
+	var c Card30
+	d.deal(func(rs Resetter) {
+		card30Shape.deal(rs, c[:])
+	})
+	return &c
+}
+
+// NewStrip creates six tickets that each use a different part of the 90 numbers.
+func (d *Dealer) NewStrip() *Strip {
+	var s *Strip
+	d.deal(func(rs Resetter) {
+		s = newStrip(rs)
+	})
+	return s
+}
+
+// newBoard creates a board with 5x5 rules.
+func (d *Dealer) newBoard(r Rules) *Board {
+	c, _ := d.NewCard(r) // the rules are valid
+	var b Board
+	copy(b[:], c.Numbers)
+	return &b
+}
+
+// deal calls the function with the Resetter of the dealer while it is locked.
+func (d *Dealer) deal(f func(rs Resetter)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f(d.rs)
+}
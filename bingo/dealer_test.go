@@ -0,0 +1,66 @@
+package bingo
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestDealerSeed(t *testing.T) {
+	d1, d2 := NewDealer(1257894001), NewDealer(1257894001)
+	if want, got := d1.NewBoard(), d2.NewBoard(); !reflect.DeepEqual(want, got) {
+		t.Errorf("wanted dealers with the same seed to deal the same boards:\nwanted: %v\ngot:    %v", want, got)
+	}
+	var g1, g2 Game
+	d1.DrawNumber(&g1)
+	d2.DrawNumber(&g2)
+	if !reflect.DeepEqual(g1, g2) {
+		t.Errorf("wanted dealers with the same seed to shuffle the same games:\nwanted: %v\ngot:    %v", g1, g2)
+	}
+}
+
+func TestDealerFrom(t *testing.T) {
+	d := NewDealerFrom(NewSeededResetter(42))
+	if want, got := NewBoardFromSeed(42), d.NewBoard(); !reflect.DeepEqual(want, got) {
+		t.Errorf("wanted board dealt from seeded resetter to be the board from the seed:\nwanted: %v\ngot:    %v", want, got)
+	}
+	s := NewDealerFrom(CryptoResetter).NewStrip()
+	if !s.isValid() {
+		t.Errorf("wanted valid strip from secure dealer: %v", s)
+	}
+}
+
+func TestDealerNewCard(t *testing.T) {
+	d := NewDealer(1257894001)
+	if _, err := d.NewCard(Rules{}); err == nil {
+		t.Errorf("wanted error dealing card with invalid rules")
+	}
+	b := d.NewNoFreeCellBoard()
+	if b.HasFreeCell() || !b.isValid() {
+		t.Errorf("wanted valid board without free cell: %v", b)
+	}
+}
+
+func TestDealerConcurrent(t *testing.T) {
+	d := NewDealer(1257894001)
+	var wg sync.WaitGroup
+	boards := make([]*Board, 20)
+	games := make([]Game, 20)
+	for i := range boards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			boards[i] = d.NewBoard()
+			d.DrawNumber(&games[i])
+		}()
+	}
+	wg.Wait()
+	for i := range boards {
+		switch {
+		case !boards[i].isValid():
+			t.Errorf("board %v: not valid: %v", i, boards[i])
+		case !games[i].valid(), games[i].NumbersLeft() != Balls75-1:
+			t.Errorf("game %v: not valid after drawing a number: %v", i, games[i])
+		}
+	}
+}
@@ -3,75 +3,225 @@ package bingo
 
 import (
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"math/rand"
 	"strconv"
 	"strings"
-	"time"
 )
 
 type (
-	// Game represents a bingo game.  The zero value can be used to start a new game.
+	// Game represents a bingo game.  The zero value can be used to start a new 75-ball game.
+	// Games with other ball counts are created with NewGame and games for cards of other shapes are created with Rules.NewGame.
+	// Games that can be reproduced from a seed are created with NewGameFromSeed.
+	// Games with a published commitment to the order of their numbers are created by calling Commit before numbers are drawn.
 	Game struct {
-		numbers      [MaxNumber - MinNumber + 1]Number
+		numbers      []Number
 		numbersDrawn int
+		rules        *Rules
+		seed         *uint64
+		salt         []byte
 	}
 	// Resetter resets games to valid, shuffled states.  It can be seeded to be predictable reset the next reset game.
 	Resetter interface {
 		// Reset resets the game.
 		Reset(g *Game)
-		// Seed sets the GameResetter to reset the next game from a starting point.
+		// Seed sets the Resetter to reset the next game from a starting point.
 		Seed(seed int64)
+		// Shuffle randomizes the order of n elements, using swap to exchange the elements at two indexes.
+		Shuffle(n int, swap func(i, j int))
 	}
-	// shuffler is the internal implementation of GameResetter.
-	// It uses a random source to randomly swap numbers when shuffling.
+	// shuffler is the Resetter of dealers created with NewDealer.
+	// It uses a random source to randomly swap numbers when shuffling.  It is not safe for concurrent use.
 	shuffler struct {
 		*rand.Rand
 		swap func(numbers []Number) func(i, j int)
 	}
 )
 
-// GameResetter shuffles the game numbers.  It is seeded to the time it is created; it should only be used when testing.
-var GameResetter Resetter = &shuffler{
-	Rand: rand.New(rand.NewSource(time.Now().UnixNano())),
-	swap: func(numbers []Number) func(i, j int) {
-		return func(i, j int) {
-			numbers[i], numbers[j] = numbers[j], numbers[i]
-		}
-	},
+// newShuffler creates a shuffler with a random source from the seed.
+// Use NewGameFromSeed and NewBoardFromSeed for games and boards that can be reproduced from a published seed.
+func newShuffler(seed int64) *shuffler {
+	return &shuffler{
+		Rand: rand.New(rand.NewSource(seed)),
+		swap: func(numbers []Number) func(i, j int) {
+			return func(i, j int) {
+				numbers[i], numbers[j] = numbers[j], numbers[i]
+			}
+		},
+	}
+}
+
+// Ball counts of the supported games.
+const (
+	// Balls30 is the ball count of speed games played with 3x3 cards.
+	Balls30 = 30
+	// Balls75 is the ball count of classic games played with 5x5 boards.
+	Balls75 = 75
+	// Balls80 is the ball count of games played with 4x4 cards.
+	Balls80 = 80
+	// Balls90 is the ball count of games played with 3x9 tickets.
+	Balls90 = 90
+)
+
+// NewGame creates a game of numbers from 1 to the ball count.
+// An error is returned if the ball count is not 30, 75, 80, or 90.
+func NewGame(balls int) (*Game, error) {
+	if !validBalls(balls) {
+		return nil, errors.New("games must have 30, 75, 80, or 90 balls")
+	}
+	g := Game{
+		numbers: make([]Number, balls),
+	}
+	for i := range g.numbers {
+		g.numbers[i] = Number(i + 1)
+	}
+	return &g, nil
+}
+
+// validBalls determines if games can have the ball count.
+func validBalls(balls int) bool {
+	switch balls {
+	case Balls30, Balls75, Balls80, Balls90:
+		return true
+	}
+	return false
+}
+
+// Balls is the amount of numbers in the game.
+func (g Game) Balls() int {
+	switch {
+	case g.rules != nil:
+		return g.rules.Balls()
+	case len(g.numbers) == 0:
+		return Balls75
+	}
+	return len(g.numbers)
+}
+
+// Rules is a copy of the rules of the cards played in the game, or nil if the game was not created from rules other than the classic rules.
+func (g Game) Rules() *Rules {
+	if g.rules == nil {
+		return nil
+	}
+	r := *g.rules
+	return &r
+}
+
+// Classic determines if the game is a 75-ball game that is played with classic boards.
+func (g Game) Classic() bool {
+	return g.rules == nil && g.Balls() == Balls75
+}
+
+// Columns is the number of columns of the cards played in the game.
+func (g Game) Columns() int {
+	if g.rules != nil {
+		return g.rules.Width
+	}
+	switch g.Balls() {
+	case Balls30:
+		return 3
+	case Balls80:
+		return 4
+	case Balls90:
+		return TicketColumns
+	}
+	return 5
+}
+
+// Column is the column of the cards played in the game that the number is located in.
+func (g Game) Column(n Number) int {
+	switch {
+	case g.rules != nil:
+		return g.rules.Column(n)
+	case g.Balls() == Balls90:
+		return ticketColumn(n)
+	}
+	perColumn := g.Balls() / g.Columns()
+	return int(n-1) / perColumn
+}
+
+// ColumnName is the display text of the column of the cards played in the game.
+// Classic games use the letters of BINGO, 80-ball games use the colors of the card columns, and other games use the range of the column.
+func (g Game) ColumnName(c int) string {
+	switch {
+	case g.rules != nil:
+		first, last := g.rules.columnRange(c)
+		return strconv.Itoa(int(first)) + "-" + strconv.Itoa(int(last))
+	case g.Balls() == Balls75:
+		return string("BINGO"[c])
+	case g.Balls() == Balls80:
+		return Card80Colors[c]
+	case g.Balls() == Balls90:
+		first, last := ticketColumnRange(c)
+		return strconv.Itoa(int(first)) + "-" + strconv.Itoa(int(last))
+	}
+	perColumn := g.Balls() / g.Columns()
+	return strconv.Itoa(c*perColumn+1) + "-" + strconv.Itoa((c+1)*perColumn)
+}
+
+// Label is the display text of the number in the game.
+// Numbers of classic games include their column letter.
+func (g Game) Label(n Number) string {
+	if g.Classic() {
+		return n.String()
+	}
+	return strconv.Itoa(n.Value())
 }
 
 // NumbersLeft reports how many available numbers in the game can be drawn.
 func (g Game) NumbersLeft() int {
 	g.normalizeNumbersDrawn()
-	return len(g.numbers) - g.numbersDrawn
+	return g.Balls() - g.numbersDrawn
 }
 
 // DrawnNumbers is the numbers in the game that have been drawn.
 func (g Game) DrawnNumbers() []Number {
 	g.normalizeNumbersDrawn()
-	return g.numbers[:g.numbersDrawn]
+	nums := make([]Number, g.numbersDrawn)
+	copy(nums, g.numbers)
+	return nums
 }
 
 // DrawNumber move the next available number to DrawnNumbers.
-// The game is reset if no numbers have been drawn.
+// The game is reset when the first number is drawn if its numbers have not been shuffled.
+// Games shuffled from a seed, committed to, or that have had all of their drawn numbers undone keep their order.
+// Servers should use Dealer.DrawNumber to shuffle with their own dealer.
 func (g *Game) DrawNumber() {
+	defaultDealer.DrawNumber(g)
+}
+
+// drawNumber draws the next number, resetting the game with the Resetter if no numbers have been drawn and the game has not been shuffled.
+func (g *Game) drawNumber(rs Resetter) {
 	g.normalizeNumbersDrawn()
 	switch {
 	case g.numbersDrawn == 0:
-		GameResetter.Reset(g)
+		if g.seed == nil && g.salt == nil && !g.shuffled() {
+			rs.Reset(g)
+		}
 		g.numbersDrawn = 1
 	case g.numbersDrawn < len(g.numbers):
 		g.numbersDrawn++
 	}
 }
 
+// UndoDraw moves the last drawn number back to the available numbers, returning it, or 0 if no numbers have been drawn.
+// The order of the numbers is not changed, so the number is drawn again next.
+func (g *Game) UndoDraw() Number {
+	g.normalizeNumbersDrawn()
+	n := g.PreviousNumberDrawn()
+	if n != 0 {
+		g.numbersDrawn--
+	}
+	return n
+}
+
 // DrawnNumberColumns partitions the drawn numbers by columns in the order that they were drawn.
 func (g Game) DrawnNumberColumns() map[int][]Number {
-	cols := make(map[int][]Number, 5)
+	cols := make(map[int][]Number, g.Columns())
 	drawnNumbers := g.DrawnNumbers()
 	for _, n := range drawnNumbers {
-		c := n.Column()
+		c := g.Column(n)
 		cols[c] = append(cols[c], n)
 	}
 	return cols
@@ -86,17 +236,37 @@ func (g Game) PreviousNumberDrawn() Number {
 	return g.numbers[g.numbersDrawn-1]
 }
 
-// Reset clears drawn numbers and resets/shuffles all the possible available numbers.
-// To shuffle the numbers to a specific order, call rand.Seed() with a constant value.
+// Reset clears drawn numbers and resets/shuffles all the possible available numbers, keeping the ball count of the game.
+// To shuffle the numbers to a specific order, create the shuffler with a constant seed.
 func (s *shuffler) Reset(g *Game) {
+	g.reset(s.Rand.Shuffle)
+}
+
+// reset creates new numbers for the game and shuffles them, clearing the drawn numbers, seed, and salt.
+func (g *Game) reset(shuffle func(n int, swap func(i, j int))) {
+	g.numbers = make([]Number, g.Balls()) // do not change the numbers of copies of the game
 	for i := range g.numbers {
 		g.numbers[i] = Number(i + 1)
 	}
-	s.Rand.Shuffle(len(g.numbers), s.swap(g.numbers[:]))
+	shuffle(len(g.numbers), func(i, j int) {
+		g.numbers[i], g.numbers[j] = g.numbers[j], g.numbers[i]
+	})
 	g.numbersDrawn = 0
+	g.seed = nil
+	g.salt = nil
 }
 
-// normalizeNumbersDrawn clamps numbersDrawn to [0,75].
+// shuffled determines if the numbers of the game are not in the order that new games have.
+func (g Game) shuffled() bool {
+	for i, n := range g.numbers {
+		if n != Number(i+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeNumbersDrawn clamps numbersDrawn to [0,balls].
 func (g *Game) normalizeNumbersDrawn() {
 	switch {
 	case g.numbersDrawn < 0:
@@ -107,28 +277,72 @@ func (g *Game) normalizeNumbersDrawn() {
 }
 
 // ID encodes the game into an easy to transport string.
+// The numbers are stored in the compact encoding, such as v2-3-...; the ball count of the game is found from the length of its encoded numbers.
+// Classic games that have not been shuffled have the id "0".
+// Ids of games played with rules start with the rules, followed by a period.
+// Games shuffled from a seed only store the seed and how many numbers have been drawn, such as s42-3.
+// Ids of committed games only have the commitment and the drawn numbers, so the order of the numbers is kept secret.
+// They cannot be read by GameFromID, so committed games should be stored on the server with their SecretID.
 func (g Game) ID() (string, error) {
+	if g.salt == nil {
+		return g.SecretID()
+	}
+	g.normalizeNumbersDrawn()
+	if !g.valid() {
+		return "", errors.New("game has duplicate/invalid numbers")
+	}
+	return committedID(g.Commitment(), g.DrawnNumbers()), nil
+}
+
+// SecretID encodes the game into a string that has the salt and order of the numbers of committed games.
+// It is the same as the ID of games that are not committed.  The ids of committed games start with the commit prefix and the salt, followed by a period.
+// The ids of committed games should only be saved on the server until the game is over.
+func (g Game) SecretID() (string, error) {
 	g.normalizeNumbersDrawn()
 	switch {
-	case g.numbersDrawn == 0:
+	case g.seed != nil:
+		return seedPrefix + strconv.FormatUint(*g.seed, 10) + "-" + strconv.Itoa(g.numbersDrawn), nil
+	case g.numbersDrawn == 0 && g.Classic() && g.salt == nil && !g.shuffled():
 		return "0", nil
-	case !numbers(g.numbers[:]).Valid():
+	case !g.valid():
 		return "", errors.New("game has duplicate/invalid numbers")
 	}
-	data := make([]byte, len(g.numbers))
-	for i, n := range g.numbers {
-		data[i] = byte(n)
+	id := g.compactID()
+	if g.rules != nil {
+		id = g.rules.String() + rulesSeparator + id
+	}
+	if g.salt != nil {
+		id = commitPrefix + hex.EncodeToString(g.salt) + rulesSeparator + id
 	}
-	nums := base64.URLEncoding.EncodeToString(data)
-	id := strconv.Itoa(g.numbersDrawn) + "-" + nums
 	return id, nil
 }
 
 // GameFromID creates a game from the identifying string.
+// Ids with numbers in the compact encoding and legacy ids with each number in a byte are both read.
 func GameFromID(id string) (*Game, error) {
+	if strings.HasPrefix(id, seedPrefix) {
+		return seededGameFromID(id)
+	}
+	if strings.HasPrefix(id, commitPrefix) {
+		return committedGameFromID(id)
+	}
+	var r *Rules
+	if i := strings.Index(id, rulesSeparator); i >= 0 {
+		idRules, err := ParseRules(id[:i])
+		if err != nil {
+			return nil, errors.New("parsing game rules: " + err.Error())
+		}
+		if *idRules == ClassicRules {
+			return nil, errors.New("ids of classic games do not start with rules")
+		}
+		r, id = idRules, id[i+1:]
+	}
+	if strings.HasPrefix(id, compactPrefix) {
+		return compactGameFromID(id, r)
+	}
 	i := strings.IndexAny(id, "-")
 	switch {
-	case id == "0":
+	case id == "0" && r == nil:
 		return new(Game), nil
 	case i < 0, i >= len(id):
 		return nil, errors.New("could not split id string into numbersDrawn and numbers")
@@ -142,16 +356,44 @@ func GameFromID(id string) (*Game, error) {
 	if err != nil {
 		return nil, errors.New("decoding game numbers: " + err.Error())
 	}
-	var g Game
-	if len(data) != len(g.numbers) {
+	if r == nil && !validBalls(len(data)) || r != nil && len(data) != r.Balls() {
 		return nil, errors.New("decoded numbers too large/small")
 	}
+	g := Game{
+		numbers: make([]Number, len(data)),
+		rules:   r,
+	}
 	for i, n := range data {
 		g.numbers[i] = Number(n)
 	}
-	if !numbers(g.numbers[:]).Valid() {
+	if !g.valid() {
 		return nil, errors.New("game has duplicate/invalid numbers")
 	}
 	g.numbersDrawn = numbersDrawn
 	return &g, nil
 }
+
+// valid determines if the game has a supported ball count and each number from 1 to the ball count once.
+// Games played with rules must have the ball count of the rules.
+func (g Game) valid() bool {
+	switch {
+	case g.rules != nil && len(g.numbers) != g.rules.Balls(),
+		g.rules == nil && !validBalls(len(g.numbers)):
+		return false
+	}
+	return validNumbers(g.numbers, Number(len(g.numbers)))
+}
+
+// validNumbers determines if the all the numbers are between 1 and the max and there are no duplicates.
+func validNumbers(nums []Number, max Number) bool {
+	m := make(map[Number]struct{}, len(nums))
+	for _, n := range nums {
+		_, duplicate := m[n]
+		switch {
+		case duplicate, n < MinNumber, n > max:
+			return false
+		}
+		m[n] = struct{}{}
+	}
+	return true
+}
@@ -5,12 +5,12 @@ import (
 	"testing"
 )
 
-func TestGameResetterSwap(t *testing.T) {
+func TestShufflerSwap(t *testing.T) {
 	nums := []Number{1, 2, 3, 4, 5}
-	swapIndexes := GameResetter.(*shuffler).swap(nums)
+	swapIndexes := newShuffler(1257894001).swap(nums)
 	swapIndexes(1, 3)
 	if want, got := []Number{1, 4, 3, 2, 5}, nums; !reflect.DeepEqual(want, got) {
-		t.Errorf("swap did not work as expected on GameResetter: nums not equal:\nwanted: %v\ngot:    %v", want, got)
+		t.Errorf("swap did not work as expected on shuffler: nums not equal:\nwanted: %v\ngot:    %v", want, got)
 	}
 }
 
@@ -32,14 +32,64 @@ func TestGameDrawnNumbers(t *testing.T) {
 
 func TestGameDrawNumber(t *testing.T) {
 	for i, test := range gameTests {
-		GameResetter.Seed(1257894000) // if necessary, reset the game using a specific board when drawing a number
-		test.game.DrawNumber()
+		d := NewDealer(1257894000) // if necessary, reset the game using a specific board when drawing a number
+		d.DrawNumber(&test.game)
 		if want, got := test.wantAvailableAfterDraw, test.game; !reflect.DeepEqual(want, got) {
 			t.Errorf("test %v (%v): games not equal after number drawn:\nwanted: %v\ngot:    %v", i, test.name, want, got)
 		}
 	}
 }
 
+func TestGameUndoDraw(t *testing.T) {
+	for i, test := range gameTests {
+		g := test.game
+		if want, got := test.wantPreviousNumberDrawn, g.UndoDraw(); want != got {
+			t.Errorf("test %v (%v): undone numbers not equal: wanted %v, got %v", i, test.name, want, got)
+			continue
+		}
+		wantNumbersLeft := test.wantNumbersLeft
+		if test.wantPreviousNumberDrawn != 0 {
+			wantNumbersLeft++
+		}
+		if want, got := wantNumbersLeft, g.NumbersLeft(); want != got {
+			t.Errorf("test %v (%v): numbers left not equal after undo: wanted %v, got %v", i, test.name, want, got)
+		}
+		if len(g.DrawnNumbers()) == 0 {
+			continue
+		}
+		NewDealer(1257894000).DrawNumber(&g)
+		if want, got := test.wantPreviousNumberDrawn, g.PreviousNumberDrawn(); want != got {
+			t.Errorf("test %v (%v): wanted undone number to be drawn again: wanted %v, got %v", i, test.name, want, got)
+		}
+	}
+}
+
+func TestGameUndoOnlyDraw(t *testing.T) {
+	g, err := NewGame(Balls75)
+	if err != nil {
+		t.Fatalf("creating game: %v", err)
+	}
+	d := NewDealer(1257894001)
+	d.DrawNumber(g)
+	want := g.UndoDraw()
+	id, err := g.ID()
+	if err != nil {
+		t.Fatalf("unwanted error getting game id: %v", err)
+	}
+	g2, err := GameFromID(id)
+	if err != nil {
+		t.Fatalf("unwanted error getting game from %q: %v", id, err)
+	}
+	d.DrawNumber(g)
+	d.DrawNumber(g2)
+	switch {
+	case want != g.PreviousNumberDrawn():
+		t.Errorf("wanted undone number to be drawn again: wanted %v, got %v", want, g.PreviousNumberDrawn())
+	case want != g2.PreviousNumberDrawn():
+		t.Errorf("wanted undone number to be drawn again from %q: wanted %v, got %v", id, want, g2.PreviousNumberDrawn())
+	}
+}
+
 func TestDrawnNumberColumns(t *testing.T) {
 	for i, test := range gameTests {
 		if want, got := test.wantDrawnNumberColumns, test.game.DrawnNumberColumns(); !reflect.DeepEqual(want, got) {
@@ -74,10 +124,10 @@ func TestGameID(t *testing.T) {
 			game Game
 			name string
 		}{
-			{Game{numbersDrawn: 1}, "first number is invalid"},
-			{Game{numbers: [numbersLength]Number{99}, numbersDrawn: 1}, "first number is invalid"},
-			{Game{numbers: [numbersLength]Number{1, 1}, numbersDrawn: 1}, "duplicate numbers"},
-			{Game{numbers: [numbersLength]Number{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44, 45, 46, 47, 48, 49, 50, 51, 52, 53, 54, 55, 56, 57, 58, 59, 60, 61, 62, 63, 64, 65, 66, 67, 68, 69, 70, 71, 72, 73, 74, 1}, numbersDrawn: 1}, "duplicate numbers (first and last)"},
+			{Game{numbers: make([]Number, numbersLength), numbersDrawn: 1}, "first number is invalid"},
+			{Game{numbers: []Number{99}, numbersDrawn: 1}, "first number is invalid"},
+			{Game{numbers: []Number{1, 1}, numbersDrawn: 1}, "duplicate numbers"},
+			{Game{numbers: []Number{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44, 45, 46, 47, 48, 49, 50, 51, 52, 53, 54, 55, 56, 57, 58, 59, 60, 61, 62, 63, 64, 65, 66, 67, 68, 69, 70, 71, 72, 73, 74, 1}, numbersDrawn: 1}, "duplicate numbers (first and last)"},
 		}
 		for i, test := range tests {
 			if _, err := test.game.ID(); err == nil {
@@ -110,6 +160,7 @@ func TestGameFromID(t *testing.T) {
 			{"a-", "bad numbersDrawn"},
 			{"1-", "no numbers"},
 			{"1-!@#%*!@$", "bad numbersLeft"},
+			{"1-AAAA", "unsupported ball count"},
 			{"75-AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", "numbers not valid (all zeroes)"},
 		}
 		for i, test := range tests {
@@ -122,11 +173,11 @@ func TestGameFromID(t *testing.T) {
 
 func TestResetGame(t *testing.T) {
 	for i, test := range gameTests {
-		GameResetter.Reset(&test.game)
+		NewDealer(1257894001).Reset(&test.game)
 		switch {
 		case test.game.numbersDrawn != 0:
 			t.Errorf("test %v (%v): drawn numbers not empty after reset: got %v", i, test.name, test.game.numbersDrawn)
-		case !numbers(test.game.numbers[:]).Valid():
+		case !numbers(test.game.numbers).Valid():
 			t.Errorf("test %v (%v): not all numbers available after reset: %v", i, test.name, test.game.numbers)
 		}
 	}
@@ -134,15 +185,15 @@ func TestResetGame(t *testing.T) {
 
 func TestGameBase64URLEncoding(t *testing.T) {
 	g := Game{
-		numbers:      [numbersLength]Number{69, 12, 41, 1, 8, 65, 5, 48, 32, 28, 39, 9, 2, 29, 37, 72, 33, 53, 66, 15, 54, 71, 49, 46, 34, 50, 56, 20, 25, 73, 6, 38, 21, 67, 44, 3, 52, 35, 4, 36, 45, 23, 17, 40, 58, 24, 74, 19, 59, 13, 14, 61, 64, 51, 10, 7, 16, 43, 68, 31, 75, 27, 30, 22, 57, 62, 18, 42, 63, 11, 55, 47, 60, 70, 26},
+		numbers:      []Number{69, 12, 41, 1, 8, 65, 5, 48, 32, 28, 39, 9, 2, 29, 37, 72, 33, 53, 66, 15, 54, 71, 49, 46, 34, 50, 56, 20, 25, 73, 6, 38, 21, 67, 44, 3, 52, 35, 4, 36, 45, 23, 17, 40, 58, 24, 74, 19, 59, 13, 14, 61, 64, 51, 10, 7, 16, 43, 68, 31, 75, 27, 30, 22, 57, 62, 18, 42, 63, 11, 55, 47, 60, 70, 26},
 		numbersDrawn: 1,
 	}
 	id, err := g.ID()
 	if err != nil {
 		t.Fatalf("unwanted error getting game id: %v", err)
 	}
-	if want, got := "1-RQwpAQhBBTAgHCcJAh0lSCE1Qg82RzEuIjI4FBlJBiYVQywDNCMEJC0XESg6GEoTOw0OPUAzCgcQK0QfSxseFjk-Eio_CzcvPEYa", id; want != got {
-		t.Errorf("game ids not equal: wanted game id to contain '-' (url base64 encoding, not std encoding with [+/]:\nwanted: %q\ngot:    %q", want, got)
+	if want, got := "v2-1-CZmCfIY6HJgro68LgeohvkrYCkonQJlHH2f21SCAqIBYLBm2-jePo-ZZS_lZqQ", id; want != got {
+		t.Errorf("game ids not equal: wanted game id to contain '-' and '_' (url base64 encoding, not std encoding with [+/]):\nwanted: %q\ngot:    %q", want, got)
 	}
 	g2, err := GameFromID(id)
 	if err != nil {
@@ -153,7 +204,7 @@ func TestGameBase64URLEncoding(t *testing.T) {
 	}
 }
 
-const numbersLength int = len(Game{}.numbers)
+const numbersLength int = Balls75
 
 var gameTests = []struct {
 	name                    string
@@ -170,7 +221,7 @@ var gameTests = []struct {
 		name: "shuffle numbers if game is not initialized; random generator is seeded at 1257894000 for these results",
 		game: Game{},
 		wantAvailableAfterDraw: Game{
-			numbers:      [numbersLength]Number{24, 20, 64, 54, 6, 62, 25, 43, 22, 57, 10, 40, 28, 29, 30, 73, 75, 69, 68, 23, 2, 37, 36, 15, 38, 26, 8, 18, 51, 49, 53, 42, 1, 32, 52, 71, 16, 65, 5, 35, 31, 9, 12, 59, 34, 4, 33, 39, 17, 41, 27, 67, 70, 11, 55, 56, 13, 72, 46, 19, 58, 3, 47, 14, 74, 45, 66, 48, 44, 63, 21, 50, 61, 60, 7},
+			numbers:      []Number{24, 20, 64, 54, 6, 62, 25, 43, 22, 57, 10, 40, 28, 29, 30, 73, 75, 69, 68, 23, 2, 37, 36, 15, 38, 26, 8, 18, 51, 49, 53, 42, 1, 32, 52, 71, 16, 65, 5, 35, 31, 9, 12, 59, 34, 4, 33, 39, 17, 41, 27, 67, 70, 11, 55, 56, 13, 72, 46, 19, 58, 3, 47, 14, 74, 45, 66, 48, 44, 63, 21, 50, 61, 60, 7},
 			numbersDrawn: 1,
 		},
 		wantNumbersLeft:         75,
@@ -183,11 +234,11 @@ var gameTests = []struct {
 	{
 		name: "draw from front of available numbers, add to end of drawn numbers",
 		game: Game{
-			numbers:      [numbersLength]Number{65, 35, 44, 73, 18, 1, 37, 41, 69, 62, 72, 13, 9, 30, 14, 60, 2, 16, 64, 71, 24, 21, 6, 75, 55, 29, 61, 54, 12, 23, 53, 42, 48, 43, 28, 70, 15, 49, 46, 63, 68, 27, 31, 47, 67, 52, 56, 25, 11, 4, 39, 59, 66, 19, 26, 74, 22, 36, 45, 10, 50, 34, 3, 5, 57, 20, 32, 17, 40, 8, 58, 7, 51, 38, 33},
+			numbers:      []Number{65, 35, 44, 73, 18, 1, 37, 41, 69, 62, 72, 13, 9, 30, 14, 60, 2, 16, 64, 71, 24, 21, 6, 75, 55, 29, 61, 54, 12, 23, 53, 42, 48, 43, 28, 70, 15, 49, 46, 63, 68, 27, 31, 47, 67, 52, 56, 25, 11, 4, 39, 59, 66, 19, 26, 74, 22, 36, 45, 10, 50, 34, 3, 5, 57, 20, 32, 17, 40, 8, 58, 7, 51, 38, 33},
 			numbersDrawn: 3,
 		},
 		wantAvailableAfterDraw: Game{
-			numbers:      [numbersLength]Number{65, 35, 44, 73, 18, 1, 37, 41, 69, 62, 72, 13, 9, 30, 14, 60, 2, 16, 64, 71, 24, 21, 6, 75, 55, 29, 61, 54, 12, 23, 53, 42, 48, 43, 28, 70, 15, 49, 46, 63, 68, 27, 31, 47, 67, 52, 56, 25, 11, 4, 39, 59, 66, 19, 26, 74, 22, 36, 45, 10, 50, 34, 3, 5, 57, 20, 32, 17, 40, 8, 58, 7, 51, 38, 33},
+			numbers:      []Number{65, 35, 44, 73, 18, 1, 37, 41, 69, 62, 72, 13, 9, 30, 14, 60, 2, 16, 64, 71, 24, 21, 6, 75, 55, 29, 61, 54, 12, 23, 53, 42, 48, 43, 28, 70, 15, 49, 46, 63, 68, 27, 31, 47, 67, 52, 56, 25, 11, 4, 39, 59, 66, 19, 26, 74, 22, 36, 45, 10, 50, 34, 3, 5, 57, 20, 32, 17, 40, 8, 58, 7, 51, 38, 33},
 			numbersDrawn: 4,
 		},
 		wantNumbersLeft:  72,
@@ -196,9 +247,9 @@ var gameTests = []struct {
 			2: {35, 44},
 			4: {65},
 		},
-		wantID: "3-QSMsSRIBJSlFPkgNCR4OPAIQQEcYFQZLNx09NgwXNSowKxxGDzEuP0QbHy9DNDgZCwQnO0ITGkoWJC0KMiIDBTkUIBEoCDoHMyYh",
+		wantID: "v2-3-CRSDqllsTMYcbziezWB_vu1sAxviaFna8MzTz-yb2KKiJPDnMYlAJ8RF3oR3fQ",
 		wantFromID: Game{
-			numbers:      [numbersLength]Number{65, 35, 44, 73, 18, 1, 37, 41, 69, 62, 72, 13, 9, 30, 14, 60, 2, 16, 64, 71, 24, 21, 6, 75, 55, 29, 61, 54, 12, 23, 53, 42, 48, 43, 28, 70, 15, 49, 46, 63, 68, 27, 31, 47, 67, 52, 56, 25, 11, 4, 39, 59, 66, 19, 26, 74, 22, 36, 45, 10, 50, 34, 3, 5, 57, 20, 32, 17, 40, 8, 58, 7, 51, 38, 33},
+			numbers:      []Number{65, 35, 44, 73, 18, 1, 37, 41, 69, 62, 72, 13, 9, 30, 14, 60, 2, 16, 64, 71, 24, 21, 6, 75, 55, 29, 61, 54, 12, 23, 53, 42, 48, 43, 28, 70, 15, 49, 46, 63, 68, 27, 31, 47, 67, 52, 56, 25, 11, 4, 39, 59, 66, 19, 26, 74, 22, 36, 45, 10, 50, 34, 3, 5, 57, 20, 32, 17, 40, 8, 58, 7, 51, 38, 33},
 			numbersDrawn: 3,
 		},
 		wantPreviousNumberDrawn: 44,
@@ -206,11 +257,11 @@ var gameTests = []struct {
 	{
 		name: "do not draw if all numbers have been drawn",
 		game: Game{
-			numbers:      [numbersLength]Number{58, 29, 33, 59, 44, 61, 36, 60, 16, 12, 46, 50, 41, 47, 26, 67, 57, 55, 30, 34, 53, 24, 21, 38, 11, 56, 35, 48, 15, 52, 4, 27, 3, 42, 39, 8, 13, 2, 1, 45, 51, 49, 25, 32, 72, 31, 37, 40, 17, 69, 18, 43, 23, 65, 54, 7, 63, 28, 19, 5, 6, 9, 22, 62, 14, 20, 10, 66, 74, 68, 71, 73, 75, 70, 64},
+			numbers:      []Number{58, 29, 33, 59, 44, 61, 36, 60, 16, 12, 46, 50, 41, 47, 26, 67, 57, 55, 30, 34, 53, 24, 21, 38, 11, 56, 35, 48, 15, 52, 4, 27, 3, 42, 39, 8, 13, 2, 1, 45, 51, 49, 25, 32, 72, 31, 37, 40, 17, 69, 18, 43, 23, 65, 54, 7, 63, 28, 19, 5, 6, 9, 22, 62, 14, 20, 10, 66, 74, 68, 71, 73, 75, 70, 64},
 			numbersDrawn: 75,
 		},
 		wantAvailableAfterDraw: Game{
-			numbers:      [numbersLength]Number{58, 29, 33, 59, 44, 61, 36, 60, 16, 12, 46, 50, 41, 47, 26, 67, 57, 55, 30, 34, 53, 24, 21, 38, 11, 56, 35, 48, 15, 52, 4, 27, 3, 42, 39, 8, 13, 2, 1, 45, 51, 49, 25, 32, 72, 31, 37, 40, 17, 69, 18, 43, 23, 65, 54, 7, 63, 28, 19, 5, 6, 9, 22, 62, 14, 20, 10, 66, 74, 68, 71, 73, 75, 70, 64},
+			numbers:      []Number{58, 29, 33, 59, 44, 61, 36, 60, 16, 12, 46, 50, 41, 47, 26, 67, 57, 55, 30, 34, 53, 24, 21, 38, 11, 56, 35, 48, 15, 52, 4, 27, 3, 42, 39, 8, 13, 2, 1, 45, 51, 49, 25, 32, 72, 31, 37, 40, 17, 69, 18, 43, 23, 65, 54, 7, 63, 28, 19, 5, 6, 9, 22, 62, 14, 20, 10, 66, 74, 68, 71, 73, 75, 70, 64},
 			numbersDrawn: 75,
 		},
 		wantNumbersLeft:  0,
@@ -222,9 +273,9 @@ var gameTests = []struct {
 			3: {58, 59, 60, 46, 50, 47, 57, 55, 53, 56, 48, 52, 51, 49, 54},
 			4: {61, 67, 72, 69, 65, 63, 62, 66, 74, 68, 71, 73, 75, 70, 64},
 		},
-		wantID: "75-Oh0hOyw9JDwQDC4yKS8aQzk3HiI1GBUmCzgjMA80BBsDKicIDQIBLTMxGSBIHyUoEUUSKxdBNgc_HBMFBgkWPg4UCkJKREdJS0ZA",
+		wantID: "v2-75-CBUdnTZka9VRvU2gx3L-eflfsKg7qvWQLWZrkaYypMEPfoV9-gdmwn4Yxh69-Q",
 		wantFromID: Game{
-			numbers:      [numbersLength]Number{58, 29, 33, 59, 44, 61, 36, 60, 16, 12, 46, 50, 41, 47, 26, 67, 57, 55, 30, 34, 53, 24, 21, 38, 11, 56, 35, 48, 15, 52, 4, 27, 3, 42, 39, 8, 13, 2, 1, 45, 51, 49, 25, 32, 72, 31, 37, 40, 17, 69, 18, 43, 23, 65, 54, 7, 63, 28, 19, 5, 6, 9, 22, 62, 14, 20, 10, 66, 74, 68, 71, 73, 75, 70, 64},
+			numbers:      []Number{58, 29, 33, 59, 44, 61, 36, 60, 16, 12, 46, 50, 41, 47, 26, 67, 57, 55, 30, 34, 53, 24, 21, 38, 11, 56, 35, 48, 15, 52, 4, 27, 3, 42, 39, 8, 13, 2, 1, 45, 51, 49, 25, 32, 72, 31, 37, 40, 17, 69, 18, 43, 23, 65, 54, 7, 63, 28, 19, 5, 6, 9, 22, 62, 14, 20, 10, 66, 74, 68, 71, 73, 75, 70, 64},
 			numbersDrawn: 75,
 		},
 		wantPreviousNumberDrawn: 64,
@@ -232,11 +283,11 @@ var gameTests = []struct {
 	{
 		name: "first 5 numbers are for '5zuTsMm6CTZAs7ad' the rest are sequential",
 		game: Game{
-			numbers:      [numbersLength]Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
+			numbers:      []Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
 			numbersDrawn: 5,
 		},
 		wantAvailableAfterDraw: Game{
-			numbers:      [numbersLength]Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
+			numbers:      []Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
 			numbersDrawn: 6,
 		},
 		wantNumbersLeft:  70,
@@ -244,9 +295,9 @@ var gameTests = []struct {
 		wantDrawnNumberColumns: map[int][]Number{
 			0: {15, 8, 4, 12, 10},
 		},
-		wantID: "5-DwgEDAoTGxAcGSopHygxNDIuOUBIQ0ZKAQIDBQYHCQsNDhESFBUWFxgaHR4gISIjJCUmJyssLS8wMzU2Nzg6Ozw9Pj9BQkRFR0lL",
+		wantID: "v2-5-Afw7gy6ui4e5qrGSKpge9xWBhNDH0BBROdLpifV1KxyCnScOQDDuBQAAAAAAAA",
 		wantFromID: Game{
-			numbers:      [numbersLength]Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
+			numbers:      []Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
 			numbersDrawn: 5,
 		},
 		wantPreviousNumberDrawn: 10,
@@ -254,11 +305,11 @@ var gameTests = []struct {
 	{
 		name: "first 24 numbers are for '5zuTsMm6CTZAs7ad' the rest are sequential",
 		game: Game{
-			numbers:      [numbersLength]Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
+			numbers:      []Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
 			numbersDrawn: 24,
 		},
 		wantAvailableAfterDraw: Game{
-			numbers:      [numbersLength]Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
+			numbers:      []Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
 			numbersDrawn: 25,
 		},
 		wantNumbersLeft:  51,
@@ -270,38 +321,40 @@ var gameTests = []struct {
 			3: {49, 52, 50, 46, 57},
 			4: {64, 72, 67, 70, 74},
 		},
-		wantID: "24-DwgEDAoTGxAcGSopHygxNDIuOUBIQ0ZKAQIDBQYHCQsNDhESFBUWFxgaHR4gISIjJCUmJyssLS8wMzU2Nzg6Ozw9Pj9BQkRFR0lL",
+		wantID: "v2-24-Afw7gy6ui4e5qrGSKpge9xWBhNDH0BBROdLpifV1KxyCnScOQDDuBQAAAAAAAA",
 		wantFromID: Game{
-			numbers:      [numbersLength]Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
+			numbers:      []Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
 			numbersDrawn: 24,
 		},
 		wantPreviousNumberDrawn: 74,
 	},
 	{
-		name: "negative numbers drawn for '5zuTsMm6CTZAs7ad' (want reset on draw)",
+		name: "negative numbers drawn for '5zuTsMm6CTZAs7ad' (want shuffled order kept on draw)",
 		game: Game{
-			numbers:      [numbersLength]Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
+			numbers:      []Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
 			numbersDrawn: -1,
 		},
 		wantAvailableAfterDraw: Game{
 			numbersDrawn: 1,
-			numbers:      [numbersLength]Number{24, 20, 64, 54, 6, 62, 25, 43, 22, 57, 10, 40, 28, 29, 30, 73, 75, 69, 68, 23, 2, 37, 36, 15, 38, 26, 8, 18, 51, 49, 53, 42, 1, 32, 52, 71, 16, 65, 5, 35, 31, 9, 12, 59, 34, 4, 33, 39, 17, 41, 27, 67, 70, 11, 55, 56, 13, 72, 46, 19, 58, 3, 47, 14, 74, 45, 66, 48, 44, 63, 21, 50, 61, 60, 7},
+			numbers:      []Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
+		},
+		wantNumbersLeft:        75,
+		wantDrawnNumbers:       []Number{},
+		wantDrawnNumberColumns: map[int][]Number{},
+		wantID:                 "v2-0-Afw7gy6ui4e5qrGSKpge9xWBhNDH0BBROdLpifV1KxyCnScOQDDuBQAAAAAAAA",
+		wantFromID: Game{
+			numbers: []Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
 		},
-		wantNumbersLeft:         75,
-		wantDrawnNumbers:        []Number{},
-		wantDrawnNumberColumns:  map[int][]Number{},
-		wantID:                  "0",
-		wantFromID:              Game{},
 		wantPreviousNumberDrawn: 0,
 	},
 	{
 		name: "huge numbers drawn for '5zuTsMm6CTZAs7ad' (want clamped value on draw)",
 		game: Game{
-			numbers:      [numbersLength]Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
+			numbers:      []Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
 			numbersDrawn: 99999,
 		},
 		wantAvailableAfterDraw: Game{
-			numbers:      [numbersLength]Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
+			numbers:      []Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
 			numbersDrawn: 75,
 		},
 		wantNumbersLeft:  0,
@@ -313,11 +366,126 @@ var gameTests = []struct {
 			3: {49, 52, 50, 46, 57, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60},
 			4: {64, 72, 67, 70, 74, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
 		},
-		wantID: "75-DwgEDAoTGxAcGSopHygxNDIuOUBIQ0ZKAQIDBQYHCQsNDhESFBUWFxgaHR4gISIjJCUmJyssLS8wMzU2Nzg6Ozw9Pj9BQkRFR0lL",
+		wantID: "v2-75-Afw7gy6ui4e5qrGSKpge9xWBhNDH0BBROdLpifV1KxyCnScOQDDuBQAAAAAAAA",
 		wantFromID: Game{
-			numbers:      [numbersLength]Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
+			numbers:      []Number{15, 8, 4, 12, 10, 19, 27, 16, 28, 25, 42, 41, 31, 40, 49, 52, 50, 46, 57, 64, 72, 67, 70, 74, 1, 2, 3, 5, 6, 7, 9, 11, 13, 14, 17, 18, 20, 21, 22, 23, 24, 26, 29, 30, 32, 33, 34, 35, 36, 37, 38, 39, 43, 44, 45, 47, 48, 51, 53, 54, 55, 56, 58, 59, 60, 61, 62, 63, 65, 66, 68, 69, 71, 73, 75},
 			numbersDrawn: 75,
 		},
 		wantPreviousNumberDrawn: 75,
 	},
 }
+
+func TestNewGame(t *testing.T) {
+	tests := []struct {
+		balls       int
+		wantOk      bool
+		wantColumns int
+	}{
+		{0, false, 0},
+		{74, false, 0},
+		{30, true, 3},
+		{75, true, 5},
+		{80, true, 4},
+		{90, true, 9},
+	}
+	for i, test := range tests {
+		g, err := NewGame(test.balls)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("test %v: wanted error creating game with %v balls", i, test.balls)
+			}
+		case err != nil:
+			t.Errorf("test %v: unwanted error: %v", i, err)
+		case g.Balls() != test.balls:
+			t.Errorf("test %v: ball counts not equal: wanted %v, got %v", i, test.balls, g.Balls())
+		case g.NumbersLeft() != test.balls:
+			t.Errorf("test %v: wanted all numbers left, got %v", i, g.NumbersLeft())
+		case g.Columns() != test.wantColumns:
+			t.Errorf("test %v: column counts not equal: wanted %v, got %v", i, test.wantColumns, g.Columns())
+		}
+	}
+}
+
+func TestGameBallsDrawNumber(t *testing.T) {
+	for i, balls := range []int{Balls30, Balls80, Balls90} {
+		g, err := NewGame(balls)
+		if err != nil {
+			t.Fatalf("test %v: creating game: %v", i, err)
+		}
+		g2 := *g
+		for j := 0; j <= balls; j++ {
+			g.DrawnNumbers()
+			g.DrawNumber()
+		}
+		switch {
+		case g.NumbersLeft() != 0:
+			t.Errorf("test %v: wanted all numbers drawn, got %v left", i, g.NumbersLeft())
+		case !validNumbers(g.DrawnNumbers(), Number(balls)), len(g.DrawnNumbers()) != balls:
+			t.Errorf("test %v: drawn numbers not valid: %v", i, g.DrawnNumbers())
+		case g.PreviousNumberDrawn() != g.DrawnNumbers()[balls-1]:
+			t.Errorf("test %v: wanted previous number drawn to be last number, got %v", i, g.PreviousNumberDrawn())
+		case g2.numbers[0] != 1:
+			t.Errorf("test %v: wanted drawing numbers not to change copies of the game: %v", i, g2.numbers)
+		}
+	}
+}
+
+func TestGameBallsID(t *testing.T) {
+	for i, balls := range []int{Balls30, Balls80, Balls90} {
+		g, err := NewGame(balls)
+		if err != nil {
+			t.Fatalf("test %v: creating game: %v", i, err)
+		}
+		for j := 0; j < 2; j++ {
+			id, err := g.ID()
+			if err != nil {
+				t.Fatalf("test %v: unwanted error getting id: %v", i, err)
+			}
+			got, err := GameFromID(id)
+			switch {
+			case err != nil:
+				t.Errorf("test %v: unwanted error getting game from id %q: %v", i, id, err)
+			case !reflect.DeepEqual(g, got):
+				t.Errorf("test %v: games not equal:\nwanted: %v\ngot:    %v", i, g, got)
+			case got.Balls() != balls:
+				t.Errorf("test %v: ball counts not equal: wanted %v, got %v", i, balls, got.Balls())
+			}
+			g.DrawNumber()
+		}
+	}
+}
+
+func TestGameLabels(t *testing.T) {
+	tests := []struct {
+		balls          int
+		n              Number
+		wantColumn     int
+		wantColumnName string
+		wantLabel      string
+	}{
+		{75, 31, 2, "N", "N 31"},
+		{80, 41, 2, "blue", "41"},
+		{80, 80, 3, "white", "80"},
+		{30, 10, 0, "1-10", "10"},
+		{30, 11, 1, "11-20", "11"},
+		{90, 9, 0, "1-9", "9"},
+		{90, 90, 8, "80-90", "90"},
+		{90, 55, 5, "50-59", "55"},
+	}
+	for i, test := range tests {
+		g, err := NewGame(test.balls)
+		if err != nil {
+			t.Fatalf("test %v: creating game: %v", i, err)
+		}
+		c := g.Column(test.n)
+		switch {
+		case test.wantColumn != c:
+			t.Errorf("test %v: columns not equal: wanted %v, got %v", i, test.wantColumn, c)
+		case test.wantColumnName != g.ColumnName(c):
+			t.Errorf("test %v: column names not equal: wanted %q, got %q", i, test.wantColumnName, g.ColumnName(c))
+		case test.wantLabel != g.Label(test.n):
+			t.Errorf("test %v: labels not equal: wanted %q, got %q", i, test.wantLabel, g.Label(test.n))
+		}
+	}
+}
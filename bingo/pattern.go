@@ -0,0 +1,314 @@
+package bingo
+
+import (
+	"encoding/base64"
+	"errors"
+	"math/bits"
+)
+
+type (
+	// Mask is a set of cells on a board.  Bit i is set if the cell at index i of the board is in the set.
+	Mask uint64
+	// Pattern is a shape of cells that must be drawn on a board for it to have a BINGO.
+	// A board matches the pattern if any of the masks are drawn, or all of them if All is set.
+	Pattern struct {
+		// Name identifies the pattern.
+		Name string
+		// Label is the display text of the pattern.
+		Label string
+		// Masks are the groups of cells that form the pattern.
+		Masks []Mask
+		// All requires every mask to be drawn, rather than any single one.
+		All bool
+	}
+)
+
+const (
+	// CustomPatternName is the name of patterns that are created from cells rather than taken from the library.
+	CustomPatternName = "Custom"
+	// fullMask is the set of all 25 cells on a board.
+	fullMask Mask = 1<<25 - 1
+	// freeCellMask is the set of only the middle free cell on a board.
+	freeCellMask Mask = 1 << 12
+)
+
+var (
+	// linePattern is a five-in-a-row column, row, or diagonal.
+	linePattern = Pattern{
+		Name:  "HasLine",
+		Label: "Line",
+		Masks: lineMasks(),
+	}
+	// filledPattern is every cell on the board.
+	filledPattern = Pattern{
+		Name:  "IsFilled",
+		Label: "All cells",
+		Masks: []Mask{fullMask},
+	}
+	// standardPatterns is the library of named patterns, in display order.
+	standardPatterns = []Pattern{
+		linePattern,
+		filledPattern,
+		{
+			Name:  "FourCorners",
+			Label: "Four corners",
+			Masks: []Mask{cellsMask(0, 4, 20, 24)},
+		},
+		{
+			Name:  "PostageStamp",
+			Label: "Postage stamp",
+			Masks: []Mask{
+				cellsMask(0, 1, 5, 6),
+				cellsMask(3, 4, 8, 9),
+				cellsMask(15, 16, 20, 21),
+				cellsMask(18, 19, 23, 24),
+			},
+		},
+		{
+			Name:  "LetterX",
+			Label: "Letter X",
+			Masks: []Mask{diagonalMask(1), diagonalMask(2)},
+			All:   true,
+		},
+		{
+			Name:  "PictureFrame",
+			Label: "Picture frame",
+			Masks: []Mask{columnMask(0), columnMask(4), rowMask(0), rowMask(4)},
+			All:   true,
+		},
+		{
+			Name:  "LetterT",
+			Label: "Letter T",
+			Masks: []Mask{rowMask(0) | columnMask(2)},
+		},
+	}
+)
+
+// Patterns is the library of standard patterns.
+func Patterns() []Pattern {
+	patterns := make([]Pattern, len(standardPatterns))
+	copy(patterns, standardPatterns)
+	return patterns
+}
+
+// PatternByName looks up the standard pattern with the name.
+func PatternByName(name string) (p Pattern, ok bool) {
+	for _, p := range standardPatterns {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Pattern{}, false
+}
+
+// CustomPattern creates a pattern that requires all the cells at the indexes of a board.
+// The free cell is implicit: it is drawn on boards that have one and is not needed on boards that do not.  An error is returned if an index is not on the board or no cells other than the free cell are used.
+func CustomPattern(indexes ...int) (*Pattern, error) {
+	var m Mask
+	for _, i := range indexes {
+		if i < 0 || i >= 25 {
+			return nil, errors.New("pattern cell index must be between 0 and 24")
+		}
+		m |= 1 << i
+	}
+	return customPattern(m)
+}
+
+// customPattern creates a single-mask pattern, removing the free cell so the center of boards without one is not needed.
+func customPattern(m Mask) (*Pattern, error) {
+	m &^= freeCellMask
+	if m == 0 {
+		return nil, errors.New("pattern has no cells other than the free cell")
+	}
+	p := Pattern{
+		Name:  CustomPatternName,
+		Label: "Custom",
+		Masks: []Mask{m},
+	}
+	return &p, nil
+}
+
+// Has determines if the cell at the index is in the mask.
+func (m Mask) Has(index int) bool {
+	return m&(1<<index) != 0
+}
+
+// ID encodes the cells of the pattern into a base64 string.
+// Only patterns with a single mask can be encoded.
+// The free cell is implicit, so the other 24 cells are each stored as a bit.
+// This results in a byte array that is 24 / 8 = 3 characters long.
+// Base 64 uses 6 bits for each character, so the string will be 24 / 6 = 4 characters long.
+func (p Pattern) ID() (string, error) {
+	if len(p.Masks) != 1 {
+		return "", errors.New("only patterns with one mask have ids")
+	}
+	m := p.Masks[0]
+	switch {
+	case m&^fullMask != 0:
+		return "", errors.New("pattern has cells that are not on the board")
+	case m|freeCellMask == freeCellMask:
+		return "", errors.New("pattern has no cells other than the free cell")
+	}
+	var v uint32
+	j := 0
+	for i := range 25 {
+		if i == 12 { // free cell
+			continue
+		}
+		if m.Has(i) {
+			v |= 1 << j
+		}
+		j++
+	}
+	data := []byte{byte(v >> 16), byte(v >> 8), byte(v)}
+	id := base64.URLEncoding.EncodeToString(data)
+	return id, nil
+}
+
+// PatternFromID converts the pattern id to a custom Pattern.
+// An error is returned if the id is for an invalid pattern.
+func PatternFromID(id string) (*Pattern, error) {
+	if len(id) != 4 {
+		return nil, errors.New("id must be 4 characters long")
+	}
+	data, err := base64.URLEncoding.DecodeString(id)
+	switch {
+	case err != nil:
+		return nil, errors.New("decoding pattern from id: " + err.Error())
+	case len(data) != 3:
+		return nil, errors.New("id must encode 3 bytes, padding is not allowed")
+	}
+	v := uint32(data[0])<<16 | uint32(data[1])<<8 | uint32(data[2])
+	var m Mask
+	j := 0
+	for i := range 25 {
+		if i == 12 { // free cell
+			continue
+		}
+		if v&(1<<j) != 0 {
+			m |= 1 << i
+		}
+		j++
+	}
+	return customPattern(m)
+}
+
+// Matches determines if the drawn numbers of the game form the pattern on the board.
+func (b Board) Matches(g Game, p Pattern) bool {
+	drawn := b.drawnMask(g)
+	return p.matches(drawn)
+}
+
+// Daubed is the set of cells on the board that have been drawn in the game, including the free cell.
+func (b Board) Daubed(g Game) Mask {
+	return b.drawnMask(g)
+}
+
+// OneAway is the set of cells on the board that would complete one of the patterns if drawn next.
+// Patterns that the board already matches are skipped.
+func (b Board) OneAway(g Game, patterns ...Pattern) Mask {
+	drawn := b.drawnMask(g)
+	var m Mask
+	for _, p := range patterns {
+		m |= p.oneAway(drawn)
+	}
+	return m
+}
+
+// drawnMask is the set of cells on the board that have been drawn in the game, including the free cell.
+func (b Board) drawnMask(g Game) Mask {
+	nums := numberSet(g)
+	var m Mask
+	for i, n := range b {
+		if _, ok := nums[n]; ok {
+			m |= 1 << i
+		}
+	}
+	return m
+}
+
+// matches determines if the drawn cells contain the masks of the pattern.
+func (p Pattern) matches(drawn Mask) bool {
+	if len(p.Masks) == 0 {
+		return false
+	}
+	for _, m := range p.Masks {
+		switch {
+		case drawn&m == m && !p.All:
+			return true
+		case drawn&m != m && p.All:
+			return false
+		}
+	}
+	return p.All
+}
+
+// oneAway is the set of cells that each complete the pattern when added to the drawn cells.
+func (p Pattern) oneAway(drawn Mask) Mask {
+	if p.matches(drawn) {
+		return 0
+	}
+	var missing, near Mask
+	for _, m := range p.Masks {
+		cells := m &^ drawn
+		missing |= cells
+		if bits.OnesCount64(uint64(cells)) == 1 {
+			near |= cells
+		}
+	}
+	if p.All {
+		if bits.OnesCount64(uint64(missing)) != 1 {
+			return 0
+		}
+		return missing
+	}
+	return near
+}
+
+// lineMasks creates the masks of the five columns, five rows, and two diagonals.
+func lineMasks() []Mask {
+	masks := make([]Mask, 0, 12)
+	for i := range 5 {
+		masks = append(masks, columnMask(i))
+	}
+	for i := range 5 {
+		masks = append(masks, rowMask(i))
+	}
+	return append(masks, diagonalMask(1), diagonalMask(2))
+}
+
+// columnMask is the set of cells in column c.
+func columnMask(c int) Mask {
+	return Mask(1<<5-1) << (c * 5)
+}
+
+// rowMask is the set of cells in row r.
+func rowMask(r int) Mask {
+	var m Mask
+	for c := range 5 {
+		m |= 1 << (c*5 + r)
+	}
+	return m
+}
+
+// diagonalMask is the set of cells in the leading (1) or trailing (2) diagonal.
+func diagonalMask(d int) Mask {
+	var m Mask
+	for j := range 5 {
+		i := j*5 + j
+		if d == 2 {
+			i = j*5 + 4 - j
+		}
+		m |= 1 << i
+	}
+	return m
+}
+
+// cellsMask is the set of cells at the indexes.
+func cellsMask(indexes ...int) Mask {
+	var m Mask
+	for _, i := range indexes {
+		m |= 1 << i
+	}
+	return m
+}
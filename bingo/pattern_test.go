@@ -0,0 +1,362 @@
+package bingo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPatterns(t *testing.T) {
+	patterns := Patterns()
+	if want, got := standardPatterns, patterns; !reflect.DeepEqual(want, got) {
+		t.Errorf("patterns not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+	patterns[0].Name = "changed"
+	if standardPatterns[0].Name == "changed" {
+		t.Errorf("wanted copy of standard patterns to be returned")
+	}
+}
+
+func TestPatternByName(t *testing.T) {
+	t.Run("standard patterns", func(t *testing.T) {
+		for i, want := range standardPatterns {
+			got, ok := PatternByName(want.Name)
+			switch {
+			case !ok:
+				t.Errorf("test %v (%v): pattern not found", i, want.Name)
+			case !reflect.DeepEqual(want, got):
+				t.Errorf("test %v (%v): patterns not equal:\nwanted: %v\ngot:    %v", i, want.Name, want, got)
+			}
+		}
+	})
+	t.Run("unknown", func(t *testing.T) {
+		if _, ok := PatternByName("Blackout"); ok {
+			t.Errorf("wanted unknown pattern to not be found")
+		}
+	})
+}
+
+func TestBoardMatches(t *testing.T) {
+	b := board1257894001
+	tests := []struct {
+		name    string
+		pattern string
+		nums    []Number
+		want    bool
+	}{
+		{
+			name:    "four corners",
+			pattern: "FourCorners",
+			nums:    []Number{15, 10, 64, 74},
+			want:    true,
+		},
+		{
+			name:    "three corners",
+			pattern: "FourCorners",
+			nums:    []Number{15, 10, 64},
+		},
+		{
+			name:    "postage stamp, top left",
+			pattern: "PostageStamp",
+			nums:    []Number{15, 8, 19, 27},
+			want:    true,
+		},
+		{
+			name:    "postage stamp, bottom right",
+			pattern: "PostageStamp",
+			nums:    []Number{46, 57, 70, 74},
+			want:    true,
+		},
+		{
+			name:    "postage stamp, center",
+			pattern: "PostageStamp",
+			nums:    []Number{27, 16, 41, 31, 52, 50, 46},
+		},
+		{
+			name:    "letter x",
+			pattern: "LetterX",
+			nums:    []Number{15, 27, 46, 74, 10, 28, 52, 64},
+			want:    true,
+		},
+		{
+			name:    "letter x, only one diagonal",
+			pattern: "LetterX",
+			nums:    []Number{15, 27, 46, 74},
+		},
+		{
+			name:    "picture frame",
+			pattern: "PictureFrame",
+			nums:    []Number{15, 8, 4, 12, 10, 64, 72, 67, 70, 74, 19, 42, 49, 25, 40, 57},
+			want:    true,
+		},
+		{
+			name:    "picture frame, missing top of N column",
+			pattern: "PictureFrame",
+			nums:    []Number{15, 8, 4, 12, 10, 64, 72, 67, 70, 74, 19, 49, 25, 40, 57},
+		},
+		{
+			name:    "letter t",
+			pattern: "LetterT",
+			nums:    []Number{15, 19, 42, 49, 64, 41, 31, 40},
+			want:    true,
+		},
+		{
+			name:    "letter t, only top row",
+			pattern: "LetterT",
+			nums:    []Number{15, 19, 42, 49, 64},
+		},
+	}
+	for i, test := range tests {
+		p, ok := PatternByName(test.pattern)
+		if !ok {
+			t.Fatalf("test %v (%v): pattern %q not found", i, test.name, test.pattern)
+		}
+		g := newTestingGame(t, test.nums)
+		if want, got := test.want, b.Matches(g, p); want != got {
+			t.Errorf("test %v (%v): wanted %v, got %v", i, test.name, want, got)
+		}
+	}
+}
+
+func TestBoardDaubed(t *testing.T) {
+	b := board1257894001
+	g := newTestingGame(t, []Number{15, 19, 33})
+	if want, got := cellsMask(0, 5, 12), b.Daubed(g); want != got {
+		t.Errorf("daubed cells not equal: wanted %025b, got %025b", want, got)
+	}
+}
+
+func TestBoardOneAway(t *testing.T) {
+	b := board1257894001
+	tests := []struct {
+		name     string
+		patterns []string
+		nums     []Number
+		want     Mask
+	}{
+		{
+			name:     "no numbers",
+			patterns: []string{"HasLine"},
+		},
+		{
+			name:     "B column missing last cell",
+			patterns: []string{"HasLine"},
+			nums:     []Number{15, 8, 4, 12},
+			want:     cellsMask(4),
+		},
+		{
+			name:     "row and diagonal through free cell",
+			patterns: []string{"HasLine"},
+			nums:     []Number{4, 16, 50, 10, 28, 52},
+			want:     cellsMask(20, 22),
+		},
+		{
+			name:     "already has line",
+			patterns: []string{"HasLine"},
+			nums:     []Number{15, 8, 4, 12, 10},
+		},
+		{
+			name:     "letter x, missing one cell",
+			patterns: []string{"LetterX"},
+			nums:     []Number{15, 27, 46, 74, 10, 28, 52},
+			want:     cellsMask(20),
+		},
+		{
+			name:     "letter x, missing a cell in each diagonal",
+			patterns: []string{"LetterX"},
+			nums:     []Number{15, 27, 46, 10, 28, 52},
+		},
+		{
+			name:     "several patterns",
+			patterns: []string{"FourCorners", "LetterT"},
+			nums:     []Number{15, 10, 64, 19, 42, 49, 31, 40},
+			want:     cellsMask(11, 24),
+		},
+	}
+	for i, test := range tests {
+		patterns := make([]Pattern, len(test.patterns))
+		for j, name := range test.patterns {
+			p, ok := PatternByName(name)
+			if !ok {
+				t.Fatalf("test %v (%v): pattern %q not found", i, test.name, name)
+			}
+			patterns[j] = p
+		}
+		g := newTestingGame(t, test.nums)
+		if want, got := test.want, b.OneAway(g, patterns...); want != got {
+			t.Errorf("test %v (%v): one away cells not equal:\nwanted: %025b\ngot:    %025b", i, test.name, want, got)
+		}
+	}
+}
+
+func TestPatternMatchesEmpty(t *testing.T) {
+	tests := []struct {
+		name string
+		Pattern
+	}{
+		{"any", Pattern{}},
+		{"all", Pattern{All: true}},
+	}
+	for i, test := range tests {
+		if test.matches(fullMask) {
+			t.Errorf("test %v (%v): wanted pattern without masks to never match", i, test.name)
+		}
+	}
+}
+
+func TestLineMasks(t *testing.T) {
+	want := []Mask{
+		0b00000_00000_00000_00000_11111,
+		0b00000_00000_00000_11111_00000,
+		0b00000_00000_11111_00000_00000,
+		0b00000_11111_00000_00000_00000,
+		0b11111_00000_00000_00000_00000,
+		0b00001_00001_00001_00001_00001,
+		0b00010_00010_00010_00010_00010,
+		0b00100_00100_00100_00100_00100,
+		0b01000_01000_01000_01000_01000,
+		0b10000_10000_10000_10000_10000,
+		0b10000_01000_00100_00010_00001,
+		0b00001_00010_00100_01000_10000,
+	}
+	if got := lineMasks(); !reflect.DeepEqual(want, got) {
+		t.Errorf("line masks not equal:\nwanted: %b\ngot:    %b", want, got)
+	}
+}
+
+func TestCustomPattern(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		want := &Pattern{
+			Name:  CustomPatternName,
+			Label: "Custom",
+			Masks: []Mask{cellsMask(0, 4, 20, 24)},
+		}
+		got, err := CustomPattern(0, 4, 20, 24)
+		switch {
+		case err != nil:
+			t.Errorf("unwanted error: %v", err)
+		case !reflect.DeepEqual(want, got):
+			t.Errorf("patterns not equal:\nwanted: %v\ngot:    %v", want, got)
+		}
+	})
+	t.Run("free cell", func(t *testing.T) {
+		p, err := CustomPattern(0, 4, 20, 24)
+		if err != nil {
+			t.Fatalf("unwanted error: %v", err)
+		}
+		corners := []Number{1, 5, 61, 65}
+		tests := []struct {
+			name string
+			b    Board
+		}{
+			{"free cell", Board{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 0, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65}},
+			{"no free cell", Board{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 33, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65}},
+		}
+		for i, test := range tests {
+			g := newTestingGame(t, corners)
+			if !test.b.Matches(g, *p) {
+				t.Errorf("test %v (%v): wanted custom pattern to match without center number", i, test.name)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			indexes []int
+		}{
+			{"no cells", nil},
+			{"only free cell", []int{12}},
+			{"negative index", []int{0, -1}},
+			{"index too large", []int{25}},
+		}
+		for i, test := range tests {
+			if _, err := CustomPattern(test.indexes...); err == nil {
+				t.Errorf("test %v (%v): wanted error", i, test.name)
+			}
+		}
+	})
+}
+
+func TestPatternID(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		tests := []struct {
+			name string
+			Mask
+			want string
+		}{
+			{"B column", columnMask(0), "AAAf"},
+			{"B column with free cell", columnMask(0) | freeCellMask, "AAAf"},
+			{"O column", columnMask(4), "-AAA"},
+			{"all cells", fullMask, "____"},
+			{"four corners", cellsMask(0, 4, 20, 24), "iAAR"},
+		}
+		for i, test := range tests {
+			p := Pattern{Masks: []Mask{test.Mask}}
+			got, err := p.ID()
+			switch {
+			case err != nil:
+				t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+			case test.want != got:
+				t.Errorf("test %v (%v): ids not equal: wanted %q, got %q", i, test.name, test.want, got)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name string
+			Pattern
+		}{
+			{"no masks", Pattern{}},
+			{"multiple masks", linePattern},
+			{"only free cell", Pattern{Masks: []Mask{freeCellMask}}},
+			{"cell not on board", Pattern{Masks: []Mask{1 << 25}}},
+		}
+		for i, test := range tests {
+			if id, err := test.ID(); err == nil {
+				t.Errorf("test %v (%v): wanted error, got %q", i, test.name, id)
+			}
+		}
+	})
+}
+
+func TestPatternFromID(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		for i, test := range standardPatterns {
+			if len(test.Masks) != 1 {
+				continue
+			}
+			id, err := test.ID()
+			if err != nil {
+				t.Errorf("test %v (%v): unwanted error getting id: %v", i, test.Name, err)
+				continue
+			}
+			p, err := PatternFromID(id)
+			switch {
+			case err != nil:
+				t.Errorf("test %v (%v): unwanted error getting pattern from id: %v", i, test.Name, err)
+			case !reflect.DeepEqual(test.Masks[0]&^freeCellMask, p.Masks[0]):
+				t.Errorf("test %v (%v): masks not equal:\nwanted: %b\ngot:    %b", i, test.Name, test.Masks[0]&^freeCellMask, p.Masks[0])
+			case p.Name != CustomPatternName:
+				t.Errorf("test %v (%v): wanted custom pattern name, got %q", i, test.Name, p.Name)
+			}
+		}
+	})
+	t.Run("invalid ids", func(t *testing.T) {
+		tests := []struct {
+			id   string
+			name string
+		}{
+			{"", "too short"},
+			{"AAAfA", "too long"},
+			{"AA f", "spaces not allowed"},
+			{"AA+f", "not url encoding"},
+			{"AAAA", "no cells"},
+			{"000=", "padded to 2 bytes"},
+			{"00==", "padded to 1 byte"},
+		}
+		for i, test := range tests {
+			if _, err := PatternFromID(test.id); err == nil {
+				t.Errorf("test %v (%v): wanted id to be invalid", i, test.name)
+			}
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package bingo
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+const (
+	// readableAlphabet is the Crockford base32 alphabet, which leaves out I, L, O, and U so letters are not mistaken for numbers.
+	readableAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// readableBlockLength is the number of characters in each block of a readable id.
+	readableBlockLength = 5
+	// readableBlockSeparator separates the blocks of a readable id.
+	readableBlockSeparator = "-"
+	// readableFreeCellDigits is the number of base32 digits that store the numbers of a board with a free cell, before the check digit.
+	// Each column of 15 numbers is an ordered pick of 5 numbers, or 4 numbers in the free cell column, so the numbers need 89 bits.
+	readableFreeCellDigits = 18
+	// readableNoFreeCellDigits is the number of base32 digits that store the 93 bits of the numbers of a board without a free cell, before the check digit.
+	readableNoFreeCellDigits = 19
+)
+
+// ReadableID encodes the board into a Crockford base32 string that is easy to type by hand, such as "DTC2H-8T21S-MNA24-8PJZ".
+// The numbers of each column are stored as the positions of the numbers among the column numbers that are not higher on the board, so no bits are wasted.
+// The last character is a check digit that catches any single mistyped character and most swapped neighboring characters.
+// The characters are grouped into blocks of five that are separated by dashes.
+func (b Board) ReadableID() (string, error) {
+	if !b.isValid() {
+		return "", errors.New("board has duplicate/invalid numbers")
+	}
+	v := new(big.Int)
+	radix, digit := new(big.Int), new(big.Int)
+	var used [MaxNumber + 1]bool
+	for _, rc := range readableCells(b.HasFreeCell()) {
+		n := b[rc.index]
+		pos := 0
+		for n2 := rc.first; n2 < n; n2++ {
+			if !used[n2] {
+				pos++
+			}
+		}
+		used[n] = true
+		v.Mul(v, radix.SetInt64(int64(rc.choices)))
+		v.Add(v, digit.SetInt64(int64(pos)))
+	}
+	n := readableNoFreeCellDigits
+	if b.HasFreeCell() {
+		n = readableFreeCellDigits
+	}
+	digits := make([]byte, n+1)
+	base := big.NewInt(int64(len(readableAlphabet)))
+	for i := n - 1; i >= 0; i-- {
+		v.DivMod(v, base, digit)
+		digits[i] = readableAlphabet[digit.Int64()]
+	}
+	digits[n] = readableCheckDigit(string(digits[:n]))
+	var sb strings.Builder
+	for i, d := range digits {
+		if i != 0 && i%readableBlockLength == 0 {
+			sb.WriteString(readableBlockSeparator)
+		}
+		sb.WriteByte(d)
+	}
+	return sb.String(), nil
+}
+
+// isReadableID determines if the id looks like a readable id rather than a base64 id.
+// Readable ids have the count of characters of a board, other than separators, and only have characters in the readable alphabet.
+// Base64 card ids can have the same length, but they have a rules prefix or characters that are not in the alphabet.
+func isReadableID(id string) bool {
+	digits := normalizeReadableID(id)
+	switch len(digits) - 1 { // check digit
+	case readableFreeCellDigits, readableNoFreeCellDigits:
+	default:
+		return false
+	}
+	for _, d := range digits {
+		if !strings.ContainsRune(readableAlphabet, d) {
+			return false
+		}
+	}
+	return true
+}
+
+// boardFromReadableID converts the readable board id to a Board.
+// Lowercase letters and spaces are allowed, and the letters I, L, and O are read as the numbers they look like.
+// An error is returned if the check digit does not match, so most typing mistakes are not read as another board.
+func boardFromReadableID(id string) (*Board, error) {
+	digits := normalizeReadableID(id)
+	var freeCell bool
+	switch len(digits) - 1 {
+	case readableFreeCellDigits:
+		freeCell = true
+	case readableNoFreeCellDigits:
+	default:
+		return nil, fmt.Errorf("readable id must have %v or %v characters", readableFreeCellDigits+1, readableNoFreeCellDigits+1)
+	}
+	for _, d := range digits {
+		if !strings.ContainsRune(readableAlphabet, d) {
+			return nil, fmt.Errorf("readable id has invalid character %q", d)
+		}
+	}
+	data, check := digits[:len(digits)-1], digits[len(digits)-1]
+	if readableCheckDigit(data) != check {
+		return nil, errors.New("readable id check digit does not match, it might be mistyped")
+	}
+	v := new(big.Int)
+	base := big.NewInt(int64(len(readableAlphabet)))
+	radix, digit := new(big.Int), new(big.Int)
+	for i := range len(data) {
+		v.Mul(v, base)
+		v.Add(v, digit.SetInt64(int64(strings.IndexByte(readableAlphabet, data[i]))))
+	}
+	cells := readableCells(freeCell)
+	positions := make([]int, len(cells))
+	for i := len(cells) - 1; i >= 0; i-- {
+		v.DivMod(v, radix.SetInt64(int64(cells[i].choices)), digit)
+		positions[i] = int(digit.Int64())
+	}
+	if v.Sign() != 0 {
+		return nil, errors.New("readable id is not for a board")
+	}
+	var b Board
+	var used [MaxNumber + 1]bool
+	for i, rc := range cells {
+		n := rc.first
+		for pos := positions[i]; used[n] || pos > 0; n++ {
+			if !used[n] {
+				pos--
+			}
+		}
+		used[n] = true
+		b[rc.index] = n
+	}
+	return &b, nil
+}
+
+// readableCell is a cell of a board that is stored in readable ids.
+type readableCell struct {
+	// index is the index of the cell on the board.
+	index int
+	// first is the first number of the column of the cell.
+	first Number
+	// choices is the number of numbers of the column that are not higher on the board, which the number of the cell is one of.
+	choices int
+}
+
+// readableCells are the cells of boards with or without a free cell that are stored in readable ids, in order.
+func readableCells(freeCell bool) []readableCell {
+	cells := make([]readableCell, 0, 25)
+	for c := range 5 {
+		choices := 15
+		for r := range 5 {
+			i := c*5 + r
+			if i == 12 && freeCell {
+				continue
+			}
+			rc := readableCell{
+				index:   i,
+				first:   Number(c*15 + 1),
+				choices: choices,
+			}
+			cells = append(cells, rc)
+			choices--
+		}
+	}
+	return cells
+}
+
+// normalizeReadableID removes the separators of the readable id, making letters uppercase and replacing letters that look like numbers with the numbers.
+func normalizeReadableID(id string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ':
+			return -1
+		case 'O', 'o':
+			return '0'
+		case 'I', 'i', 'L', 'l':
+			return '1'
+		}
+		return unicode.ToUpper(r)
+	}, id)
+}
+
+// readableCheckDigit is the Luhn mod 32 check digit of the readable digits, which must be in the alphabet.
+func readableCheckDigit(digits string) byte {
+	n := len(readableAlphabet)
+	sum := 0
+	factor := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(readableAlphabet, digits[i])
+		sum += addend/n + addend%n
+		factor = 3 - factor
+	}
+	return readableAlphabet[(n-sum%n)%n]
+}
@@ -0,0 +1,111 @@
+package bingo
+
+import (
+	"strings"
+	"testing"
+)
+
+const board1257894001ReadableID = "DTC2H-8T21S-MNA24-8PJZ"
+
+func TestBoardReadableID(t *testing.T) {
+	t.Run("board1257894001", func(t *testing.T) {
+		got, err := board1257894001.ReadableID()
+		switch {
+		case err != nil:
+			t.Errorf("unwanted error: %v", err)
+		case board1257894001ReadableID != got:
+			t.Errorf("ids not equal:\nwanted: %q\ngot:    %q", board1257894001ReadableID, got)
+		}
+	})
+	t.Run("round trip", func(t *testing.T) {
+		d := NewDealer(1257894001)
+		for i := range 100 {
+			b := d.NewBoard()
+			if i%2 == 0 {
+				b = d.NewNoFreeCellBoard()
+			}
+			id, err := b.ReadableID()
+			if err != nil {
+				t.Errorf("board %v: unwanted error: %v: %v", i, err, b)
+				continue
+			}
+			got, err := BoardFromID(id)
+			switch {
+			case err != nil:
+				t.Errorf("board %v: unwanted error decoding %q: %v", i, id, err)
+			case *b != *got:
+				t.Errorf("board %v: boards not equal after decoding %q:\nwanted: %v\ngot:    %v", i, id, b, got)
+			}
+		}
+	})
+	t.Run("invalid board", func(t *testing.T) {
+		var b Board
+		if _, err := b.ReadableID(); err == nil {
+			t.Errorf("wanted error for invalid board")
+		}
+	})
+}
+
+func TestBoardFromReadableID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"readable", board1257894001ReadableID},
+		{"lowercase", strings.ToLower(board1257894001ReadableID)},
+		{"spaces", "DTC2H 8T21S MNA24 8PJZ"},
+		{"no separators", "DTC2H8T21SMNA248PJZ"},
+		{"look-alike letters", "DTC2H-8T2IS-MNA24-8PJZ"},
+	}
+	for i, test := range tests {
+		got, err := BoardFromID(test.id)
+		switch {
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+		case board1257894001 != *got:
+			t.Errorf("test %v (%v): boards not equal:\nwanted: %v\ngot:    %v", i, test.name, board1257894001, got)
+		}
+	}
+}
+
+func TestBoardFromIDReadableLength(t *testing.T) {
+	id := "5x5n15.A-EgE-I-ASA-EgE-IA" // has as many characters other than dashes as a readable id
+	b, err := BoardFromID(id)
+	if err != nil {
+		t.Fatalf("unwanted error getting board from card id with length of readable id: %v", err)
+	}
+	if got, err := b.ID(); err != nil || id != got {
+		t.Errorf("board ids not equal:\nwanted: %q\ngot:    %q (%v)", id, got, err)
+	}
+}
+
+func TestBoardFromReadableIDInvalid(t *testing.T) {
+	data := strings.Repeat("Z", readableFreeCellDigits)
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"invalid character", "DTC2H-8T21S-MNA24-8PJU"},
+		{"not a board", data + string(readableCheckDigit(data))},
+		{"swapped characters", "DTC2H-8T21S-MNA42-8PJZ"},
+	}
+	for i, test := range tests {
+		if _, err := BoardFromID(test.id); err == nil {
+			t.Errorf("test %v (%v): wanted error", i, test.name)
+		}
+	}
+	t.Run("mistyped characters", func(t *testing.T) {
+		id := normalizeReadableID(board1257894001ReadableID)
+		for i := range len(id) {
+			for j := range len(readableAlphabet) {
+				if id[i] == readableAlphabet[j] {
+					continue
+				}
+				mistyped := id[:i] + readableAlphabet[j:j+1] + id[i+1:]
+				if _, err := BoardFromID(mistyped); err == nil {
+					t.Errorf("wanted error when character %v is mistyped: %q", i, mistyped)
+				}
+			}
+		}
+	})
+}
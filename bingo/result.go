@@ -0,0 +1,133 @@
+package bingo
+
+import "strconv"
+
+type (
+	// LineKind is the shape of the cells that formed a BINGO.
+	LineKind int
+	// Result is the outcome of checking a board for a pattern in a game.
+	Result struct {
+		// Bingo is whether the board has the pattern.
+		Bingo bool
+		// Line is the kind of line the winning cells are, or NoLine if they are another shape.
+		Line LineKind
+		// Index is the column or row index of the winning line.  It is 0 for the leading diagonal and 1 for the trailing diagonal.
+		Index int
+		// Cells are the winning cells on the board.
+		Cells Mask
+		// Drawn are all the cells on the board that have been drawn, including the free cell.
+		Drawn Mask
+		// Number is the drawn number that completed the pattern.
+		// It is zero if the board does not have a BINGO or the free cell completed it.
+		Number Number
+	}
+)
+
+const (
+	// NoLine is the kind of winning cells that are not a single line, such as four corners.
+	NoLine LineKind = iota
+	// Column is a vertical line of cells.
+	Column
+	// Row is a horizontal line of cells.
+	Row
+	// Diagonal is a line from corner to corner.
+	Diagonal
+)
+
+// String is the lowercase name of the LineKind.
+func (k LineKind) String() string {
+	switch k {
+	case Column:
+		return "column"
+	case Row:
+		return "row"
+	case Diagonal:
+		return "diagonal"
+	}
+	return "pattern"
+}
+
+// LineName describes the winning line, such as "B column", "row 3", or "leading diagonal".
+// It is empty if the winning cells are not a line.
+func (r Result) LineName() string {
+	switch r.Line {
+	case Column:
+		return string("BINGO"[r.Index]) + " column"
+	case Row:
+		return "row " + strconv.Itoa(r.Index+1)
+	case Diagonal:
+		if r.Index == 1 {
+			return "trailing diagonal"
+		}
+		return "leading diagonal"
+	}
+	return ""
+}
+
+// Check determines if the board has the pattern in the game, reporting the winning cells.
+// When the pattern allows any of its masks, the first one that is drawn wins.
+func (b Board) Check(g Game, p Pattern) Result {
+	drawn := b.drawnMask(g)
+	r := Result{
+		Drawn: drawn,
+	}
+	if !p.matches(drawn) {
+		return r
+	}
+	r.Bingo = true
+	r.Cells = p.winningCells(drawn)
+	r.Line, r.Index = lineOf(r.Cells)
+	r.Number = b.completingNumber(g, r.Cells)
+	return r
+}
+
+// winningCells is the union of the masks of the pattern that are drawn.
+// When the pattern allows any mask, only the first drawn mask is used.
+func (p Pattern) winningCells(drawn Mask) Mask {
+	var cells Mask
+	for _, m := range p.Masks {
+		if drawn&m == m {
+			cells |= m
+			if !p.All {
+				break
+			}
+		}
+	}
+	return cells
+}
+
+// completingNumber is the number in the cells that was drawn last in the game.
+func (b Board) completingNumber(g Game, cells Mask) Number {
+	onBoard := make(map[Number]struct{}, len(b))
+	for i, n := range b {
+		if cells.Has(i) && n != 0 {
+			onBoard[n] = struct{}{}
+		}
+	}
+	drawnNumbers := g.DrawnNumbers()
+	for i := len(drawnNumbers) - 1; i >= 0; i-- {
+		n := drawnNumbers[i]
+		if _, ok := onBoard[n]; ok {
+			return n
+		}
+	}
+	return 0
+}
+
+// lineOf determines if the cells are exactly a column, row, or diagonal.
+func lineOf(cells Mask) (k LineKind, index int) {
+	for i := range 5 {
+		switch cells {
+		case columnMask(i):
+			return Column, i
+		case rowMask(i):
+			return Row, i
+		}
+	}
+	for i := range 2 {
+		if cells == diagonalMask(i+1) {
+			return Diagonal, i
+		}
+	}
+	return NoLine, 0
+}
@@ -0,0 +1,132 @@
+package bingo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLineKindString(t *testing.T) {
+	tests := []struct {
+		LineKind
+		want string
+	}{
+		{NoLine, "pattern"},
+		{Column, "column"},
+		{Row, "row"},
+		{Diagonal, "diagonal"},
+		{LineKind(-1), "pattern"},
+	}
+	for i, test := range tests {
+		if want, got := test.want, test.String(); want != got {
+			t.Errorf("test %v: wanted %q, got %q", i, want, got)
+		}
+	}
+}
+
+func TestResultLineName(t *testing.T) {
+	tests := []struct {
+		Result
+		want string
+	}{
+		{Result{}, ""},
+		{Result{Line: Column}, "B column"},
+		{Result{Line: Column, Index: 4}, "O column"},
+		{Result{Line: Row}, "row 1"},
+		{Result{Line: Row, Index: 2}, "row 3"},
+		{Result{Line: Diagonal}, "leading diagonal"},
+		{Result{Line: Diagonal, Index: 1}, "trailing diagonal"},
+	}
+	for i, test := range tests {
+		if want, got := test.want, test.LineName(); want != got {
+			t.Errorf("test %v: wanted %q, got %q", i, want, got)
+		}
+	}
+}
+
+func TestBoardCheck(t *testing.T) {
+	b := board1257894001
+	letterX, _ := PatternByName("LetterX")
+	fourCorners, _ := PatternByName("FourCorners")
+	tests := []struct {
+		name    string
+		pattern Pattern
+		nums    []Number
+		want    Result
+	}{
+		{
+			name:    "no numbers",
+			pattern: linePattern,
+			want: Result{
+				Drawn: freeCellMask,
+			},
+		},
+		{
+			name:    "B column",
+			pattern: linePattern,
+			nums:    []Number{15, 8, 4, 12, 10},
+			want: Result{
+				Bingo:  true,
+				Line:   Column,
+				Cells:  columnMask(0),
+				Drawn:  columnMask(0) | freeCellMask,
+				Number: 10,
+			},
+		},
+		{
+			name:    "row 3, completed before last number",
+			pattern: linePattern,
+			nums:    []Number{4, 16, 67, 50, 1},
+			want: Result{
+				Bingo:  true,
+				Line:   Row,
+				Index:  2,
+				Cells:  rowMask(2),
+				Drawn:  rowMask(2),
+				Number: 50,
+			},
+		},
+		{
+			name:    "diagonal 2",
+			pattern: linePattern,
+			nums:    []Number{10, 28, 52, 64},
+			want: Result{
+				Bingo:  true,
+				Line:   Diagonal,
+				Index:  1,
+				Cells:  diagonalMask(2),
+				Drawn:  diagonalMask(2),
+				Number: 64,
+			},
+		},
+		{
+			name:    "four corners",
+			pattern: fourCorners,
+			nums:    []Number{74, 15, 10, 64},
+			want: Result{
+				Bingo:  true,
+				Line:   NoLine,
+				Cells:  cellsMask(0, 4, 20, 24),
+				Drawn:  cellsMask(0, 4, 12, 20, 24),
+				Number: 64,
+			},
+		},
+		{
+			name:    "letter x",
+			pattern: letterX,
+			nums:    []Number{15, 27, 46, 74, 10, 28, 52, 64},
+			want: Result{
+				Bingo:  true,
+				Line:   NoLine,
+				Cells:  diagonalMask(1) | diagonalMask(2),
+				Drawn:  diagonalMask(1) | diagonalMask(2),
+				Number: 64,
+			},
+		},
+	}
+	for i, test := range tests {
+		g := newTestingGame(t, test.nums)
+		if want, got := test.want, b.Check(g, test.pattern); !reflect.DeepEqual(want, got) {
+			t.Errorf("test %v (%v): results not equal:\nwanted: %+v\ngot:    %+v", i, test.name, want, got)
+		}
+	}
+}
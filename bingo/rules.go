@@ -0,0 +1,346 @@
+package bingo
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+type (
+	// Rules describe the shape of the cards of a game and the range of numbers in each column.
+	// Column c of a card has numbers from c*ColumnNumbers+1 to (c+1)*ColumnNumbers, so the game has Width*ColumnNumbers balls.
+	Rules struct {
+		// Width is the number of columns of cards.
+		Width int
+		// Height is the number of rows of cards.
+		Height int
+		// FreeCell is whether cards have a free cell, which is always drawn.
+		FreeCell bool
+		// FreeColumn is the column of the free cell.
+		FreeColumn int
+		// FreeRow is the row of the free cell.
+		FreeRow int
+		// ColumnNumbers is how many numbers are in the range of each column.
+		ColumnNumbers int
+	}
+	// Card is a bingo card of any shape, made under the rules.
+	// The cells are stored by column, so the cell in column c and row r is at index c*Height+r.  The free cell, if any, is 0.
+	Card struct {
+		Rules   Rules
+		Numbers []Number
+	}
+)
+
+const (
+	// maxRulesSize is the most rows or columns a card can have, so all cells fit in a Mask.
+	maxRulesSize = 8
+	// rulesSeparator separates the rules from the rest of an id.  It is not in the base64 url alphabet.
+	rulesSeparator = "."
+)
+
+// ClassicRules are the rules of 75-ball games with 5x5 boards that have a free cell in the middle.
+// Boards and games made with these rules have the same ids as other boards and games.
+var ClassicRules = Rules{
+	Width:         5,
+	Height:        5,
+	FreeCell:      true,
+	FreeColumn:    2,
+	FreeRow:       2,
+	ColumnNumbers: 15,
+}
+
+// Validate checks that cards can be made with the rules.
+// Cards can have up to 8 rows and columns, and each column must have enough numbers for its cells.
+// Games can have at most 255 balls so the numbers can be stored in bytes.
+func (r Rules) Validate() error {
+	switch {
+	case r.Width < 1, r.Width > maxRulesSize, r.Height < 1, r.Height > maxRulesSize:
+		return fmt.Errorf("cards must have between 1 and %v rows and columns", maxRulesSize)
+	case r.Width*r.Height < 2:
+		return errors.New("cards must have at least two cells")
+	case r.ColumnNumbers < r.Height:
+		return errors.New("columns must have at least as many numbers as cards have rows")
+	case r.Balls() > 255:
+		return errors.New("games can have at most 255 balls")
+	case r.FreeCell && (r.FreeColumn < 0 || r.FreeColumn >= r.Width || r.FreeRow < 0 || r.FreeRow >= r.Height):
+		return errors.New("free cell must be on the card")
+	}
+	return nil
+}
+
+// Balls is the amount of numbers in games played with the rules.
+func (r Rules) Balls() int {
+	return r.Width * r.ColumnNumbers
+}
+
+// Plays determines if cards with the rules are played in the game.
+// Games with rules are played with cards that have the same rules.  Classic 75-ball games are played with cards with the classic rules or NoFreeCellRules.
+func (r Rules) Plays(g Game) bool {
+	if g.rules != nil {
+		return *g.rules == r
+	}
+	return g.Classic() && (r == ClassicRules || r == NoFreeCellRules)
+}
+
+// Column is the column of cards that the number is located in.
+func (r Rules) Column(n Number) int {
+	return int(n-1) / r.ColumnNumbers
+}
+
+// columnRange is the first and last numbers in the column of cards.
+func (r Rules) columnRange(c int) (first, last Number) {
+	return Number(c*r.ColumnNumbers + 1), Number((c + 1) * r.ColumnNumbers)
+}
+
+// freeCell is the index of the free cell, or -1 if cards do not have one.
+func (r Rules) freeCell() int {
+	if !r.FreeCell {
+		return -1
+	}
+	return r.FreeColumn*r.Height + r.FreeRow
+}
+
+// String encodes the rules into a short string, such as 5x5n15f22 for the classic rules.
+// The string has the width, height, numbers per column, and the column and row of the free cell, if any.
+func (r Rules) String() string {
+	s := fmt.Sprintf("%vx%vn%v", r.Width, r.Height, r.ColumnNumbers)
+	if r.FreeCell {
+		s += fmt.Sprintf("f%v%v", r.FreeColumn, r.FreeRow)
+	}
+	return s
+}
+
+// ParseRules converts the string form of rules back to Rules.
+// An error is returned if the string is malformed or the rules are not valid.
+func ParseRules(s string) (*Rules, error) {
+	var r Rules
+	n, err := fmt.Sscanf(s, "%dx%dn%d", &r.Width, &r.Height, &r.ColumnNumbers)
+	if err != nil || n != 3 {
+		return nil, fmt.Errorf("rules must be formatted as WIDTHxHEIGHTnCOLUMN_NUMBERS, such as 5x5n15, optionally followed by fCOLUMNROW for the free cell: %q", s)
+	}
+	if i := strings.Index(s, "f"); i >= 0 {
+		free := s[i+1:]
+		if len(free) != 2 || free[0] < '0' || free[0] > '9' || free[1] < '0' || free[1] > '9' {
+			return nil, fmt.Errorf("free cell must be a single digit column and row: %q", s)
+		}
+		r.FreeCell = true
+		r.FreeColumn, r.FreeRow = int(free[0]-'0'), int(free[1]-'0')
+	}
+	if r.String() != s {
+		return nil, fmt.Errorf("rules are not in the standard form: wanted %q, got %q", r.String(), s)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// NewGame creates a game of the numbers for the rules.
+// Games with classic rules are the same as the zero game.
+func (r Rules) NewGame() (*Game, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	if r == ClassicRules {
+		return new(Game), nil
+	}
+	g := Game{
+		numbers: make([]Number, r.Balls()),
+		rules:   &r,
+	}
+	for i := range g.numbers {
+		g.numbers[i] = Number(i + 1)
+	}
+	return &g, nil
+}
+
+// NewCard creates a card by drawing numbers from a game with the rules.
+// Each column of the card only contains numbers of the same column.
+func (r Rules) NewCard() (*Card, error) {
+	return defaultDealer.NewCard(r)
+}
+
+// newCard creates a card from the numbers of a game with the valid rules that is reset by the Resetter.
+func (r Rules) newCard(rs Resetter) *Card {
+	g, _ := r.NewGame()
+	rs.Reset(g)
+	g.numbersDrawn = g.NumbersLeft() // "draw" all the numbers
+	cols := make(map[int][]Number, r.Width)
+	for _, n := range g.DrawnNumbers() {
+		c := r.Column(n)
+		cols[c] = append(cols[c], n)
+	}
+	c := Card{
+		Rules:   r,
+		Numbers: make([]Number, r.Width*r.Height),
+	}
+	free := r.freeCell()
+	for i := range c.Numbers {
+		if i == free {
+			continue
+		}
+		col := i / r.Height
+		c.Numbers[i] = cols[col][0]
+		cols[col] = cols[col][1:]
+	}
+	return &c
+}
+
+// HasLine determines if the card has a full column, row, or diagonal of drawn numbers.
+// Cards that are not square do not have diagonals.  Cards do not have lines in games with other rules.
+func (c Card) HasLine(g Game) bool {
+	p := Pattern{
+		Masks: c.Rules.lineMasks(),
+	}
+	return p.matches(c.drawnMask(g))
+}
+
+// IsFilled determines if all the numbers on the card have been drawn in the game.
+// Cards are not filled in games with other rules.
+func (c Card) IsFilled(g Game) bool {
+	full := Mask(1)<<len(c.Numbers) - 1
+	return c.drawnMask(g) == full
+}
+
+// drawnMask is the set of cells on the card that have been drawn in the game, including the free cell.
+// It is empty if the card is not played in the game.
+func (c Card) drawnMask(g Game) Mask {
+	if !c.Rules.Plays(g) {
+		return 0
+	}
+	nums := numberSet(g)
+	var m Mask
+	for i, n := range c.Numbers {
+		if _, ok := nums[n]; ok {
+			m |= 1 << i
+		}
+	}
+	return m
+}
+
+// lineMasks creates the masks of the columns, rows, and, for square cards, the diagonals.
+func (r Rules) lineMasks() []Mask {
+	masks := make([]Mask, 0, r.Width+r.Height+2)
+	column := Mask(1)<<r.Height - 1
+	for c := range r.Width {
+		masks = append(masks, column<<(c*r.Height))
+	}
+	for row := range r.Height {
+		var m Mask
+		for c := range r.Width {
+			m |= 1 << (c*r.Height + row)
+		}
+		masks = append(masks, m)
+	}
+	if r.Width == r.Height {
+		var leading, trailing Mask
+		for j := range r.Width {
+			leading |= 1 << (j*r.Height + j)
+			trailing |= 1 << (j*r.Height + r.Height - 1 - j)
+		}
+		masks = append(masks, leading, trailing)
+	}
+	return masks
+}
+
+// ID encodes the card into a string.
+// Each number other than the free cell is stored as its offset from the first number of its column, using as few bits as the column range needs.
+// The bits are converted to base64.  Cards with rules other than the classic rules start with the rules, followed by a period.
+func (c Card) ID() (string, error) {
+	if !c.valid() {
+		return "", errors.New("card has duplicate/invalid numbers")
+	}
+	size := c.Rules.numberBits()
+	free := c.Rules.freeCell()
+	var w bitWriter
+	for i, n := range c.Numbers {
+		if i == free {
+			continue
+		}
+		first, _ := c.Rules.columnRange(i / c.Rules.Height)
+		w.write(int(n-first), size)
+	}
+	id := base64.RawURLEncoding.EncodeToString(w.data)
+	if c.Rules != ClassicRules {
+		id = c.Rules.String() + rulesSeparator + id
+	}
+	return id, nil
+}
+
+// CardFromID converts the card id to a Card.
+// Ids that do not start with rules are for cards with the classic rules, which are the same as board ids.
+// An error is returned if the id is for an invalid card.
+func CardFromID(id string) (*Card, error) {
+	r := ClassicRules
+	if i := strings.Index(id, rulesSeparator); i >= 0 {
+		idRules, err := ParseRules(id[:i])
+		if err != nil {
+			return nil, errors.New("parsing card rules: " + err.Error())
+		}
+		if *idRules == ClassicRules {
+			return nil, errors.New("ids of classic cards do not start with rules")
+		}
+		r, id = *idRules, id[i+1:]
+	}
+	size := r.numberBits()
+	cells := r.Width * r.Height
+	free := r.freeCell()
+	numberCount := cells
+	if free >= 0 {
+		numberCount--
+	}
+	idLength := base64.RawURLEncoding.EncodedLen((numberCount*size + 7) / 8)
+	if len(id) != idLength {
+		return nil, fmt.Errorf("id must be %v characters long", idLength)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return nil, errors.New("decoding card from id: " + err.Error())
+	}
+	c := Card{
+		Rules:   r,
+		Numbers: make([]Number, cells),
+	}
+	br := bitReader{data: data}
+	for i := range c.Numbers {
+		if i == free {
+			continue
+		}
+		first, _ := r.columnRange(i / r.Height)
+		c.Numbers[i] = first + Number(br.read(size))
+	}
+	if !c.valid() {
+		return nil, errors.New("card has duplicate/invalid numbers")
+	}
+	return &c, nil
+}
+
+// numberBits is how many bits are needed to store the offset of a number from the first number of its column.
+func (r Rules) numberBits() int {
+	return bits.Len(uint(r.ColumnNumbers - 1))
+}
+
+// valid determines if the card has a cell for each spot of its rules, the free cell is 0, and the other numbers are unique and in their columns.
+func (c Card) valid() bool {
+	r := c.Rules
+	if r.Validate() != nil || len(c.Numbers) != r.Width*r.Height {
+		return false
+	}
+	free := r.freeCell()
+	nums := make([]Number, 0, len(c.Numbers))
+	for i, n := range c.Numbers {
+		if i == free {
+			if n != 0 {
+				return false
+			}
+			continue
+		}
+		if first, last := r.columnRange(i / r.Height); n < first || n > last {
+			return false
+		}
+		nums = append(nums, n)
+	}
+	return validNumbers(nums, Number(r.Balls()))
+}
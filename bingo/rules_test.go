@@ -0,0 +1,299 @@
+package bingo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRulesValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		Rules
+		wantOk bool
+	}{
+		{"classic", ClassicRules, true},
+		{"4x4 1-100", Rules{Width: 4, Height: 4, ColumnNumbers: 25}, true},
+		{"6x6 free cell", Rules{Width: 6, Height: 6, FreeCell: true, FreeColumn: 2, FreeRow: 3, ColumnNumbers: 16}, true},
+		{"8x8 max balls", Rules{Width: 8, Height: 8, ColumnNumbers: 31}, true},
+		{"zero", Rules{}, false},
+		{"too wide", Rules{Width: 9, Height: 1, ColumnNumbers: 10}, false},
+		{"single cell", Rules{Width: 1, Height: 1, ColumnNumbers: 10}, false},
+		{"not enough column numbers", Rules{Width: 5, Height: 5, ColumnNumbers: 4}, false},
+		{"too many balls", Rules{Width: 8, Height: 8, ColumnNumbers: 32}, false},
+		{"free cell off card", Rules{Width: 4, Height: 4, FreeCell: true, FreeColumn: 4, ColumnNumbers: 20}, false},
+	}
+	for i, test := range tests {
+		err := test.Rules.Validate()
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("test %v (%v): wanted error", i, test.name)
+			}
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		want   *Rules
+		wantOk bool
+	}{
+		{"classic", "5x5n15f22", &ClassicRules, true},
+		{"no free cell", "4x4n25", &Rules{Width: 4, Height: 4, ColumnNumbers: 25}, true},
+		{"6x6", "6x6n16f23", &Rules{Width: 6, Height: 6, FreeCell: true, FreeColumn: 2, FreeRow: 3, ColumnNumbers: 16}, true},
+		{"empty", "", nil, false},
+		{"missing numbers", "5x5", nil, false},
+		{"bad free cell", "5x5n15f2", nil, false},
+		{"trailing text", "5x5n15x", nil, false},
+		{"leading zero", "5x5n015", nil, false},
+		{"invalid", "9x9n15", nil, false},
+	}
+	for i, test := range tests {
+		got, err := ParseRules(test.s)
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("test %v (%v): wanted error parsing %q", i, test.name, test.s)
+			}
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+		case !reflect.DeepEqual(test.want, got):
+			t.Errorf("test %v (%v): rules not equal:\nwanted: %v\ngot:    %v", i, test.name, test.want, got)
+		case test.s != got.String():
+			t.Errorf("test %v (%v): strings not equal: wanted %q, got %q", i, test.name, test.s, got.String())
+		}
+	}
+}
+
+func TestRulesNewCard(t *testing.T) {
+	tests := []struct {
+		name string
+		Rules
+		idLength int
+	}{
+		{"4x4 1-100", Rules{Width: 4, Height: 4, ColumnNumbers: 25}, 7 + 14},
+		{"6x6 free cell", Rules{Width: 6, Height: 6, FreeCell: true, FreeColumn: 0, FreeRow: 5, ColumnNumbers: 16}, 10 + 24},
+		{"3x5 not square", Rules{Width: 3, Height: 5, ColumnNumbers: 5}, 6 + 8},
+	}
+	d := NewDealer(1257894001)
+	for i, test := range tests {
+		c, err := d.NewCard(test.Rules)
+		if err != nil {
+			t.Fatalf("test %v (%v): unwanted error creating card: %v", i, test.name, err)
+		}
+		id, err := c.ID()
+		if err != nil {
+			t.Fatalf("test %v (%v): unwanted error getting id of %v: %v", i, test.name, c.Numbers, err)
+		}
+		got, err := CardFromID(id)
+		switch {
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error getting card from id %q: %v", i, test.name, id, err)
+		case len(id) != test.idLength:
+			t.Errorf("test %v (%v): wanted %v character id, got %q", i, test.name, test.idLength, id)
+		case !reflect.DeepEqual(c, got):
+			t.Errorf("test %v (%v): cards not equal:\nwanted: %v\ngot:    %v", i, test.name, *c, *got)
+		}
+	}
+	if _, err := (Rules{}).NewCard(); err == nil {
+		t.Errorf("wanted error creating card with invalid rules")
+	}
+}
+
+func TestClassicCard(t *testing.T) {
+	d := NewDealer(1257894001)
+	c, err := d.NewCard(ClassicRules)
+	if err != nil {
+		t.Fatalf("unwanted error creating card: %v", err)
+	}
+	if want, got := board1257894001[:], c.Numbers; !reflect.DeepEqual(want, got) {
+		t.Errorf("card numbers not equal to board:\nwanted: %v\ngot:    %v", want, got)
+	}
+	id, err := c.ID()
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error getting id: %v", err)
+	case board1257894001ID != id:
+		t.Errorf("ids not equal: wanted %q, got %q", board1257894001ID, id)
+	}
+}
+
+func TestCardFromIDInvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"bad rules", "5x5.AAAA"},
+		{"invalid rules", "9x9n15.AAAA"},
+		{"short id", "4x4n25.AAAA"},
+		{"bad base64", "3x3n10f11.AAAA!!"},
+		{"duplicate numbers", "3x3n10f11.AAAAAA"},
+		{"classic board id with rules", "5x5n15f22." + board1257894001ID},
+	}
+	for i, test := range tests {
+		if _, err := CardFromID(test.id); err == nil {
+			t.Errorf("test %v (%v): wanted error getting card from %q", i, test.name, test.id)
+		}
+	}
+}
+
+func TestCardHasLineIsFilled(t *testing.T) {
+	r := Rules{Width: 3, Height: 2, FreeCell: true, FreeColumn: 1, FreeRow: 0, ColumnNumbers: 10}
+	c := Card{
+		Rules:   r,
+		Numbers: []Number{1, 2, 0, 12, 21, 22},
+	}
+	tests := []struct {
+		name       string
+		nums       []Number
+		wantLine   bool
+		wantFilled bool
+	}{
+		{"none", nil, false, false},
+		{"column", []Number{1, 2}, true, false},
+		{"row with free cell", []Number{1, 21}, true, false},
+		{"no diagonals on rectangles", []Number{1, 22}, false, false},
+		{"all", []Number{1, 2, 12, 21, 22}, true, true},
+	}
+	for i, test := range tests {
+		g, err := r.NewGame()
+		if err != nil {
+			t.Fatalf("creating game: %v", err)
+		}
+		copy(g.numbers, test.nums)
+		g.numbersDrawn = len(test.nums)
+		switch {
+		case test.wantLine != c.HasLine(*g):
+			t.Errorf("test %v (%v): wanted HasLine to be %v", i, test.name, test.wantLine)
+		case test.wantFilled != c.IsFilled(*g):
+			t.Errorf("test %v (%v): wanted IsFilled to be %v", i, test.name, test.wantFilled)
+		}
+	}
+}
+
+func TestCardOtherRules(t *testing.T) {
+	r := Rules{Width: 3, Height: 2, ColumnNumbers: 10}
+	c := Card{
+		Rules:   r,
+		Numbers: []Number{1, 2, 11, 12, 21, 22},
+	}
+	nums := []Number{1, 2, 11, 12, 21, 22}
+	tests := []struct {
+		name  string
+		rules *Rules
+		balls int
+	}{
+		{"other width", &Rules{Width: 4, Height: 2, ColumnNumbers: 10}, 0},
+		{"other height", &Rules{Width: 3, Height: 3, ColumnNumbers: 10}, 0},
+		{"free cell", &Rules{Width: 3, Height: 2, FreeCell: true, ColumnNumbers: 10}, 0},
+		{"30-ball game", nil, Balls30},
+		{"classic game", nil, Balls75},
+	}
+	for i, test := range tests {
+		var g *Game
+		var err error
+		switch {
+		case test.rules != nil:
+			g, err = test.rules.NewGame()
+		default:
+			g, err = NewGame(test.balls)
+		}
+		if err != nil {
+			t.Fatalf("test %v (%v): creating game: %v", i, test.name, err)
+		}
+		copy(g.numbers, nums)
+		g.numbersDrawn = len(nums)
+		switch {
+		case r.Plays(*g):
+			t.Errorf("test %v (%v): wanted rules not to play game", i, test.name)
+		case c.HasLine(*g):
+			t.Errorf("test %v (%v): wanted card not to have line in game with other rules", i, test.name)
+		case c.IsFilled(*g):
+			t.Errorf("test %v (%v): wanted card not to be filled in game with other rules", i, test.name)
+		}
+	}
+}
+
+func TestRulesPlays(t *testing.T) {
+	r := Rules{Width: 4, Height: 4, ColumnNumbers: 25}
+	rulesGame, _ := r.NewGame()
+	tests := []struct {
+		name  string
+		rules Rules
+		g     Game
+		want  bool
+	}{
+		{"classic", ClassicRules, Game{}, true},
+		{"no free cell", NoFreeCellRules, Game{}, true},
+		{"same rules", r, *rulesGame, true},
+		{"classic card in rules game", ClassicRules, *rulesGame, false},
+		{"rules card in classic game", r, Game{}, false},
+	}
+	for i, test := range tests {
+		if want, got := test.want, test.rules.Plays(test.g); want != got {
+			t.Errorf("test %v (%v): wanted %v, got %v", i, test.name, want, got)
+		}
+	}
+}
+
+func TestRulesGame(t *testing.T) {
+	r := Rules{Width: 4, Height: 4, ColumnNumbers: 25}
+	g, err := r.NewGame()
+	if err != nil {
+		t.Fatalf("unwanted error creating game: %v", err)
+	}
+	switch {
+	case g.Balls() != 100:
+		t.Errorf("wanted 100 balls, got %v", g.Balls())
+	case g.Columns() != 4:
+		t.Errorf("wanted 4 columns, got %v", g.Columns())
+	case g.Column(100) != 3, g.ColumnName(3) != "76-100", g.Label(100) != "100":
+		t.Errorf("unwanted column/label for 100: %v, %q, %q", g.Column(100), g.ColumnName(3), g.Label(100))
+	case g.Classic():
+		t.Errorf("wanted game with rules not to be classic")
+	case !reflect.DeepEqual(&r, g.Rules()):
+		t.Errorf("rules not equal: wanted %v, got %v", r, g.Rules())
+	}
+	for j := 0; j < 2; j++ {
+		id, err := g.ID()
+		if err != nil {
+			t.Fatalf("unwanted error getting id: %v", err)
+		}
+		got, err := GameFromID(id)
+		switch {
+		case err != nil:
+			t.Errorf("unwanted error getting game from id %q: %v", id, err)
+		case !reflect.DeepEqual(g, got):
+			t.Errorf("games not equal:\nwanted: %v\ngot:    %v", g, got)
+		}
+		g.DrawNumber()
+	}
+	classic, err := ClassicRules.NewGame()
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error creating classic game: %v", err)
+	case !reflect.DeepEqual(new(Game), classic):
+		t.Errorf("wanted classic game to be the zero game, got %v", classic)
+	}
+}
+
+func TestGameFromIDInvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"bad rules", "4x4.0-AQID"},
+		{"undrawn rules game", "4x4n25.0"},
+		{"wrong ball count", "3x3n10.0-AQID"},
+		{"classic rules", "5x5n15f22.0"},
+	}
+	for i, test := range tests {
+		if _, err := GameFromID(test.id); err == nil {
+			t.Errorf("test %v (%v): wanted error getting game from %q", i, test.name, test.id)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package bingo
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// seededShuffler is a Resetter that shuffles with a SplitMix64 generator, so shuffles from a seed are the same with every version of Go.
+//
+// Each random value is made by adding 0x9e3779b97f4a7c15 to the 64-bit state and mixing the state with
+// z = (z ^ z>>30) * 0xbf58476d1ce4e5b9, z = (z ^ z>>27) * 0x94d049bb133111eb, z = z ^ z>>31.
+// Shuffles swap each index i, from the last down to 1, with an index j in [0,i].
+// The index j is the remainder of a random value divided by i+1, skipping values less than (2^64 - (i+1)) % (i+1) so each index is equally likely.
+type seededShuffler struct {
+	state uint64
+}
+
+// seedPrefix starts the ids of games that are created from a seed.
+const seedPrefix = "s"
+
+// NewSeededResetter creates a Resetter that shuffles from the seed using a documented algorithm that does not change.
+// It is not safe for concurrent use.
+func NewSeededResetter(seed uint64) Resetter {
+	return &seededShuffler{
+		state: seed,
+	}
+}
+
+// NewGameFromSeed creates a 75-ball game with numbers that are shuffled from the seed.
+// The first number drawn does not reset the game, so the draw order can be reproduced from the published seed.
+func NewGameFromSeed(seed uint64) *Game {
+	var g Game
+	NewSeededResetter(seed).Reset(&g)
+	g.seed = &seed
+	return &g
+}
+
+// NewBoardFromSeed creates a board with numbers that are shuffled from the seed.
+func NewBoardFromSeed(seed uint64) *Board {
+	c := ClassicRules.newCard(NewSeededResetter(seed))
+	var b Board
+	copy(b[:], c.Numbers)
+	return &b
+}
+
+// Seeded determines if the numbers of the game were shuffled from a seed.
+func (g Game) Seeded() bool {
+	return g.seed != nil
+}
+
+// Seed is the seed that the numbers of the game were shuffled from, or 0 if the game is not seeded.
+func (g Game) Seed() uint64 {
+	if g.seed == nil {
+		return 0
+	}
+	return *g.seed
+}
+
+// seededGameFromID creates a game from the id of a seeded game, which has the seed and how many numbers have been drawn.
+func seededGameFromID(id string) (*Game, error) {
+	seedStr, numbersDrawnStr, ok := strings.Cut(strings.TrimPrefix(id, seedPrefix), "-")
+	if !ok {
+		return nil, errors.New("could not split seeded id string into seed and numbersDrawn")
+	}
+	seed, err := strconv.ParseUint(seedStr, 10, 64)
+	if err != nil {
+		return nil, errors.New("parsing seed: " + err.Error())
+	}
+	numbersDrawn, err := strconv.Atoi(numbersDrawnStr)
+	if err != nil {
+		return nil, errors.New("parsing numbersDrawn: " + err.Error())
+	}
+	g := NewGameFromSeed(seed)
+	if numbersDrawn < 0 || numbersDrawn > len(g.numbers) {
+		return nil, errors.New("numbersDrawn must be between 0 and the number of balls")
+	}
+	g.numbersDrawn = numbersDrawn
+	return g, nil
+}
+
+// Reset shuffles all the numbers of the game from the state of the shuffler, clearing the drawn numbers.
+func (s *seededShuffler) Reset(g *Game) {
+	g.reset(s.Shuffle)
+}
+
+// Seed sets the state of the shuffler.
+func (s *seededShuffler) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+// Shuffle randomizes the order of n elements with the Fisher-Yates algorithm, using swap to exchange the elements at two indexes.
+func (s *seededShuffler) Shuffle(n int, swap func(i, j int)) {
+	shuffle(n, swap, s.next)
+}
+
+// shuffle randomizes the order of n elements with the Fisher-Yates algorithm, using the random values from next to pick the indexes to swap.
+func shuffle(n int, swap func(i, j int), next func() uint64) {
+	for i := n - 1; i > 0; i-- {
+		j := uintn(uint64(i+1), next)
+		swap(i, int(j))
+	}
+}
+
+// uintn is a random value in [0,n) from the random values of next.
+func uintn(n uint64, next func() uint64) uint64 {
+	threshold := -n % n // (2^64 - n) % n
+	for {
+		if v := next(); v >= threshold {
+			return v % n
+		}
+	}
+}
+
+// next advances the state of the shuffler and mixes it into a random value.
+func (s *seededShuffler) next() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
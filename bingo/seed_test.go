@@ -0,0 +1,109 @@
+package bingo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSeededShufflerNext(t *testing.T) {
+	// reference values of SplitMix64 with a zero seed
+	want := []uint64{0xe220a8397b1dcdaf, 0x6e789e6aa1b965f4, 0x06c45d188009454f}
+	var s seededShuffler
+	for i, w := range want {
+		if got := s.next(); w != got {
+			t.Errorf("value %v not equal: wanted %#x, got %#x", i, w, got)
+		}
+	}
+}
+
+func TestNewGameFromSeed(t *testing.T) {
+	// the draw order must not change, so published seeds can be audited
+	want := []Number{5, 46, 52, 12, 31, 3, 6, 53, 47, 22}
+	g := NewGameFromSeed(42)
+	for range want {
+		g.DrawNumber()
+	}
+	if got := g.DrawnNumbers(); !reflect.DeepEqual(want, got) {
+		t.Errorf("drawn numbers not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+	id, err := g.ID()
+	switch {
+	case err != nil:
+		t.Fatalf("unwanted error getting id: %v", err)
+	case id != "s42-10":
+		t.Errorf("wanted seeded id to have the seed and numbers drawn, got %q", id)
+	}
+	got, err := GameFromID(id)
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error getting game from id: %v", err)
+	case !reflect.DeepEqual(g, got):
+		t.Errorf("games not equal:\nwanted: %v\ngot:    %v", g, got)
+	case !got.Seeded(), got.Seed() != 42:
+		t.Errorf("wanted game from id to have seed 42, got %v", got.Seed())
+	}
+}
+
+func TestGameFromSeededIDInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"no numbers drawn", "s42"},
+		{"bad seed", "s-1-3"},
+		{"bad numbers drawn", "s42-x"},
+		{"negative numbers drawn", "s42--1"},
+		{"too many numbers drawn", "s42-76"},
+	}
+	for i, test := range tests {
+		if _, err := GameFromID(test.id); err == nil {
+			t.Errorf("test %v (%v): wanted error getting game from %q", i, test.name, test.id)
+		}
+	}
+}
+
+func TestSeededGameReset(t *testing.T) {
+	g := NewGameFromSeed(7)
+	NewDealer(1257894001).Reset(g)
+	if g.Seeded() {
+		t.Errorf("wanted game to not be seeded after being reset")
+	}
+}
+
+func TestNewBoardFromSeed(t *testing.T) {
+	b := NewBoardFromSeed(42)
+	id, err := b.ID()
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error getting id: %v", err)
+	case id != "SyUGB8UNeAZxikgX":
+		t.Errorf("board ids from seed not equal: got %q", id)
+	case !reflect.DeepEqual(b, NewBoardFromSeed(42)):
+		t.Errorf("wanted boards from the same seed to be equal")
+	case reflect.DeepEqual(b, NewBoardFromSeed(43)):
+		t.Errorf("wanted boards from different seeds to be different")
+	}
+}
+
+func TestSeededResetterShuffle(t *testing.T) {
+	r := NewSeededResetter(1)
+	r.Seed(1257894001)
+	var g Game
+	r.Reset(&g)
+	if !g.valid() {
+		t.Errorf("wanted valid game after reset: %v", g.numbers)
+	}
+	counts := make([]int, 3)
+	for range 3000 {
+		p := []int{0, 1, 2}
+		r.Shuffle(len(p), func(i, j int) {
+			p[i], p[j] = p[j], p[i]
+		})
+		counts[p[0]]++
+	}
+	for i, c := range counts {
+		if c < 900 || c > 1100 {
+			t.Errorf("wanted about 1000 shuffles to start with %v, got %v", i, c)
+		}
+	}
+}
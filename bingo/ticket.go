@@ -0,0 +1,379 @@
+package bingo
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+)
+
+type (
+	// Ticket represents a 90-ball bingo ticket of 3 rows and 9 columns.
+	// The cells are stored by column, so the cell in column c and row r is at index c*3+r.
+	// Each row has five numbers and four blank (0) cells.  Each column has at least one number, in increasing order from top to bottom.
+	// The first column has numbers 1-9, the last has 80-90, and the others have the ten numbers of their tens, such as 10-19 for the second column.
+	Ticket [TicketColumns * TicketRows]Number
+	// Strip is six tickets that use all 90 numbers exactly once.
+	Strip [StripTickets]Ticket
+)
+
+const (
+	// TicketRows is the number of rows on a 90-ball ticket.
+	TicketRows = 3
+	// TicketColumns is the number of columns on a 90-ball ticket.
+	TicketColumns = 9
+	// StripTickets is the number of tickets in a strip.
+	StripTickets = 6
+	// ticketRowNumbers is the number of non-blank cells in each row of a ticket.
+	ticketRowNumbers = 5
+	// ticketNumbers is the number of non-blank cells on a ticket.
+	ticketNumbers = ticketRowNumbers * TicketRows
+	// ticketIDLength is the length of a ticket id.
+	ticketIDLength = 15
+)
+
+// NewStrip creates six tickets that each use a different part of the 90 numbers.
+func NewStrip() *Strip {
+	return defaultDealer.NewStrip()
+}
+
+// newStrip creates a strip, shuffling the numbers and the shape of the tickets with the Resetter.
+func newStrip(rs Resetter) *Strip {
+	for {
+		counts, ok := newStripColumnCounts(rs)
+		if ok {
+			return newStripFromColumnCounts(rs, counts)
+		}
+	}
+}
+
+// newStripColumnCounts decides how many numbers each ticket of a strip has in each column.
+// Every column of each ticket gets one number and the rest are dealt to the tickets with the most space left.
+// Dealing can fail if the tickets that have space left already have three numbers in a column.
+func newStripColumnCounts(rs Resetter) (counts [StripTickets][TicketColumns]int, ok bool) {
+	var totals [StripTickets]int
+	for t := range counts {
+		for c := range counts[t] {
+			counts[t][c] = 1
+		}
+		totals[t] = TicketColumns
+	}
+	for _, c := range []int{8, 1, 2, 3, 4, 5, 6, 7, 0} { // columns with the most numbers first
+		first, last := ticketColumnRange(c)
+		extra := int(last-first+1) - StripTickets
+		tickets := permutation(rs, StripTickets)
+		sort.SliceStable(tickets, func(i, j int) bool {
+			return totals[tickets[i]] < totals[tickets[j]]
+		})
+		for _, t := range tickets {
+			if extra > 0 && counts[t][c] < TicketRows && totals[t] < ticketNumbers {
+				counts[t][c]++
+				totals[t]++
+				extra--
+			}
+		}
+		if extra > 0 {
+			return counts, false
+		}
+	}
+	return counts, true
+}
+
+// newStripFromColumnCounts places the shuffled numbers of each column on the tickets, arranging the cells so each row has five numbers.
+func newStripFromColumnCounts(rs Resetter, counts [StripTickets][TicketColumns]int) *Strip {
+	var s Strip
+	for c := range TicketColumns {
+		first, last := ticketColumnRange(c)
+		nums := make([]Number, 0, last-first+1)
+		for n := first; n <= last; n++ {
+			nums = append(nums, n)
+		}
+		rs.Shuffle(len(nums), func(i, j int) {
+			nums[i], nums[j] = nums[j], nums[i]
+		})
+		for t := range s {
+			k := counts[t][c]
+			tNums := nums[:k]
+			nums = nums[k:]
+			sort.Slice(tNums, func(i, j int) bool {
+				return tNums[i] < tNums[j]
+			})
+			for i, n := range tNums {
+				s[t][c*TicketRows+i] = n // moved to the correct rows when the ticket is arranged
+			}
+		}
+	}
+	for t := range s {
+		s[t].arrangeRows(rs, counts[t])
+	}
+	return &s
+}
+
+// arrangeRows moves the numbers at the top of each column down to rows so that each row has five numbers.
+// Columns are added in a random order to the rows with the fewest numbers, which keeps the rows within one number of each other.
+func (t *Ticket) arrangeRows(rs Resetter, counts [TicketColumns]int) {
+	var rowTotals [TicketRows]int
+	for _, c := range permutation(rs, TicketColumns) {
+		rows := permutation(rs, TicketRows)
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rowTotals[rows[i]] < rowTotals[rows[j]]
+		})
+		rows = rows[:counts[c]]
+		sort.Ints(rows)
+		var col [TicketRows]Number
+		for i, r := range rows {
+			col[r] = t[c*TicketRows+i]
+			rowTotals[r]++
+		}
+		copy(t[c*TicketRows:], col[:])
+	}
+}
+
+// permutation is a shuffled list of the integers in [0,n).
+func permutation(rs Resetter, n int) []int {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	rs.Shuffle(n, func(i, j int) {
+		p[i], p[j] = p[j], p[i]
+	})
+	return p
+}
+
+// ticketColumnRange is the first and last numbers that are in the column of a ticket.
+func ticketColumnRange(c int) (first, last Number) {
+	switch c {
+	case 0:
+		return 1, 9
+	case TicketColumns - 1:
+		return 80, Number(Balls90)
+	}
+	return Number(c * 10), Number(c*10 + 9)
+}
+
+// ticketColumn is the column of a ticket that the 90-ball number is located in.
+func ticketColumn(n Number) int {
+	if n == Number(Balls90) {
+		return TicketColumns - 1
+	}
+	return int(n) / 10
+}
+
+// OneLine determines if a row of the ticket has all of its numbers drawn in the 90-ball game.
+func (t Ticket) OneLine(g Game) bool {
+	return t.lines(g) >= 1
+}
+
+// TwoLines determines if two rows of the ticket have all of their numbers drawn in the game.
+func (t Ticket) TwoLines(g Game) bool {
+	return t.lines(g) >= 2
+}
+
+// FullHouse determines if all the numbers on the ticket have been drawn in the game.
+func (t Ticket) FullHouse(g Game) bool {
+	return t.lines(g) == TicketRows
+}
+
+// lines counts the rows of the ticket that have all of their numbers drawn in the game.
+// Tickets do not have lines in games that do not have 90 balls.
+func (t Ticket) lines(g Game) int {
+	if g.Balls() != Balls90 {
+		return 0
+	}
+	drawn := make(map[Number]struct{}, g.numbersDrawn+1)
+	drawn[0] = struct{}{} // blank cells
+	for _, n := range g.DrawnNumbers() {
+		drawn[n] = struct{}{}
+	}
+	lines := 0
+	for r := range TicketRows {
+		line := true
+		for c := range TicketColumns {
+			if _, ok := drawn[t[c*TicketRows+r]]; !ok {
+				line = false
+			}
+		}
+		if line {
+			lines++
+		}
+	}
+	return lines
+}
+
+// ID encodes the ticket into a base64 string.
+// The first 27 bits are set for the cells that have numbers.
+// Each of the 15 numbers is then stored in 4 bits as its offset from the first number of its column.
+// This is 27 + 15*4 = 87 bits, which are stored in 11 bytes.
+// Base 64 uses 6 bits for each character, so without padding the string will be 15 characters long.
+func (t Ticket) ID() (string, error) {
+	if !t.isValid() {
+		return "", errors.New("ticket has duplicate/invalid numbers")
+	}
+	var w bitWriter
+	for _, n := range t {
+		v := 0
+		if n != 0 {
+			v = 1
+		}
+		w.write(v, 1)
+	}
+	for i, n := range t {
+		if n != 0 {
+			first, _ := ticketColumnRange(i / TicketRows)
+			w.write(int(n-first), 4)
+		}
+	}
+	id := base64.RawURLEncoding.EncodeToString(w.data)
+	return id, nil
+}
+
+// TicketFromID converts the ticket id to a Ticket.
+// An error is returned if the id is for an invalid ticket or is not the id that the ticket encodes to, so each ticket has one id.
+func TicketFromID(id string) (*Ticket, error) {
+	if len(id) != ticketIDLength {
+		return nil, errors.New("id must be 15 characters long")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return nil, errors.New("decoding ticket from id: " + err.Error())
+	}
+	r := bitReader{data: data}
+	var t Ticket
+	for i := range t {
+		t[i] = Number(r.read(1)) // marks the cells with numbers
+	}
+	for i, n := range t {
+		if n != 0 {
+			first, _ := ticketColumnRange(i / TicketRows)
+			t[i] = first + Number(r.read(4))
+		}
+	}
+	if !t.isValid() {
+		return nil, errors.New("ticket has duplicate/invalid numbers")
+	}
+	if canonicalID, _ := t.ID(); canonicalID != id { // the ticket is valid, so it has an id
+		return nil, errors.New("ticket id has unused bits set, use " + canonicalID)
+	}
+	return &t, nil
+}
+
+// isValid determines if each row has five numbers and each column has at least one number.
+// The numbers must be unique, in the correct columns, and increase down each column.
+func (t Ticket) isValid() bool {
+	nums := make(numbers, 0, ticketNumbers)
+	var rowTotals [TicketRows]int
+	for c := range TicketColumns {
+		var prev Number
+		for r := range TicketRows {
+			n := t[c*TicketRows+r]
+			if n == 0 {
+				continue
+			}
+			if n <= prev || n < MinNumber || n > Number(Balls90) || ticketColumn(n) != c {
+				return false
+			}
+			nums = append(nums, n)
+			rowTotals[r]++
+			prev = n
+		}
+		if prev == 0 {
+			return false // empty column
+		}
+	}
+	for _, total := range rowTotals {
+		if total != ticketRowNumbers {
+			return false
+		}
+	}
+	return validNumbers(nums, Balls90)
+}
+
+// ID concatenates the ids of the tickets on the strip.
+func (s Strip) ID() (string, error) {
+	if !s.isValid() {
+		return "", errors.New("strip does not use each number once")
+	}
+	id := make([]byte, 0, ticketIDLength*len(s))
+	for i, t := range s {
+		tID, err := t.ID()
+		if err != nil {
+			return "", errors.New("ticket " + strconv.Itoa(i+1) + ": " + err.Error())
+		}
+		id = append(id, tID...)
+	}
+	return string(id), nil
+}
+
+// StripFromID converts the strip id to a Strip.
+// An error is returned if a ticket is invalid or the tickets do not use each number once.
+func StripFromID(id string) (*Strip, error) {
+	var s Strip
+	if len(id) != ticketIDLength*len(s) {
+		return nil, errors.New("id must be 90 characters long")
+	}
+	for i := range s {
+		tID := id[i*ticketIDLength : (i+1)*ticketIDLength]
+		t, err := TicketFromID(tID)
+		if err != nil {
+			return nil, errors.New("ticket " + strconv.Itoa(i+1) + ": " + err.Error())
+		}
+		s[i] = *t
+	}
+	if !s.isValid() {
+		return nil, errors.New("strip does not use each number once")
+	}
+	return &s, nil
+}
+
+// isValid determines if the tickets of the strip use each of the 90 numbers exactly once.
+func (s Strip) isValid() bool {
+	nums := make([]Number, 0, Balls90)
+	for _, t := range s {
+		for _, n := range t {
+			if n != 0 {
+				nums = append(nums, n)
+			}
+		}
+	}
+	return len(nums) == Balls90 && validNumbers(nums, Balls90)
+}
+
+type (
+	// bitWriter appends values to bytes, starting with the most significant bits.
+	bitWriter struct {
+		data []byte
+		n    int
+	}
+	// bitReader reads values from bytes, starting with the most significant bits.
+	bitReader struct {
+		data []byte
+		n    int
+	}
+)
+
+// write appends the lowest size bits of the value.
+func (w *bitWriter) write(v, size int) {
+	for i := size - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.data = append(w.data, 0)
+		}
+		if v>>i&1 == 1 {
+			w.data[w.n/8] |= 1 << (7 - w.n%8)
+		}
+		w.n++
+	}
+}
+
+// read reads the next size bits as a value.  Bits past the end of the data are zero.
+func (r *bitReader) read(size int) int {
+	v := 0
+	for range size {
+		v <<= 1
+		if r.n/8 < len(r.data) && r.data[r.n/8]&(1<<(7-r.n%8)) != 0 {
+			v |= 1
+		}
+		r.n++
+	}
+	return v
+}
@@ -0,0 +1,211 @@
+package bingo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var ticket1 = Ticket{
+	1, 0, 5,
+	0, 10, 0,
+	20, 0, 25,
+	0, 30, 0,
+	40, 0, 45,
+	0, 50, 0,
+	60, 0, 65,
+	0, 70, 75,
+	80, 85, 0,
+}
+
+func TestTicketLines(t *testing.T) {
+	tests := []struct {
+		name          string
+		nums          []Number
+		wantOneLine   bool
+		wantTwoLines  bool
+		wantFullHouse bool
+	}{
+		{
+			name: "no numbers",
+		},
+		{
+			name: "most of top row",
+			nums: []Number{1, 20, 40, 60, 10, 30, 90},
+		},
+		{
+			name:        "top row",
+			nums:        []Number{1, 20, 40, 60, 80},
+			wantOneLine: true,
+		},
+		{
+			name:         "middle and bottom rows",
+			nums:         []Number{10, 30, 50, 70, 85, 5, 25, 45, 65, 75, 2},
+			wantOneLine:  true,
+			wantTwoLines: true,
+		},
+		{
+			name:          "full house",
+			nums:          []Number{1, 20, 40, 60, 80, 10, 30, 50, 70, 85, 5, 25, 45, 65, 75},
+			wantOneLine:   true,
+			wantTwoLines:  true,
+			wantFullHouse: true,
+		},
+	}
+	for i, test := range tests {
+		g := newTestingGameBalls(t, Balls90, test.nums)
+		switch {
+		case test.wantOneLine != ticket1.OneLine(g):
+			t.Errorf("test %v (%v): wanted one line to be %v", i, test.name, test.wantOneLine)
+		case test.wantTwoLines != ticket1.TwoLines(g):
+			t.Errorf("test %v (%v): wanted two lines to be %v", i, test.name, test.wantTwoLines)
+		case test.wantFullHouse != ticket1.FullHouse(g):
+			t.Errorf("test %v (%v): wanted full house to be %v", i, test.name, test.wantFullHouse)
+		}
+	}
+}
+
+func TestTicketLinesOtherBalls(t *testing.T) {
+	nums := []Number{5, 25, 45, 65, 75} // bottom row
+	for i, balls := range []int{Balls75, Balls80} {
+		g := newTestingGameBalls(t, balls, nums)
+		if ticket1.OneLine(g) {
+			t.Errorf("test %v (%v balls): wanted ticket to not have lines in game with other ball count", i, balls)
+		}
+	}
+}
+
+func TestTicketID(t *testing.T) {
+	id, err := ticket1.ID()
+	switch {
+	case err != nil:
+		t.Fatalf("unwanted error: %v", err)
+	case len(id) != 15:
+		t.Errorf("wanted 15 character id, got %q", id)
+	}
+	got, err := TicketFromID(id)
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error getting ticket from id %q: %v", id, err)
+	case !reflect.DeepEqual(&ticket1, got):
+		t.Errorf("tickets not equal:\nwanted: %v\ngot:    %v", ticket1, *got)
+	}
+}
+
+func TestTicketFromIDNotCanonical(t *testing.T) {
+	id, err := ticket1.ID()
+	if err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	last := strings.IndexByte(alphabet, id[len(id)-1])
+	for unusedBits := 1; unusedBits < 8; unusedBits++ { // the last character has three bits that are not used by the 87 bits of the ticket
+		nonCanonicalID := id[:len(id)-1] + string(alphabet[last^unusedBits])
+		if _, err := TicketFromID(nonCanonicalID); err == nil {
+			t.Errorf("wanted error getting ticket from id %q with unused bits set, the id of the ticket is %q", nonCanonicalID, id)
+		}
+	}
+}
+
+func TestTicketIsValid(t *testing.T) {
+	swap := func(i, j int) Ticket {
+		t := ticket1
+		t[i], t[j] = t[j], t[i]
+		return t
+	}
+	set := func(i int, n Number) Ticket {
+		t := ticket1
+		t[i] = n
+		return t
+	}
+	tests := []struct {
+		Ticket
+		name string
+	}{
+		{Ticket{}, "empty"},
+		{set(0, 0), "row with four numbers"},
+		{set(1, 3), "row with six numbers"},
+		{set(0, 10), "number in wrong column"},
+		{swap(0, 2), "numbers not increasing in column"},
+		{set(2, 1), "duplicate number"},
+		{swap(3, 4), "empty column"},
+		{set(24, 91), "number too large"},
+	}
+	for i, test := range tests {
+		if test.isValid() {
+			t.Errorf("test %v (%v): wanted ticket to be invalid: %v", i, test.name, test.Ticket)
+		}
+		if _, err := test.ID(); err == nil {
+			t.Errorf("test %v (%v): wanted error getting id", i, test.name)
+		}
+	}
+}
+
+func TestTicketFromIDInvalid(t *testing.T) {
+	tests := []struct {
+		id   string
+		name string
+	}{
+		{"", "empty"},
+		{"AAAAAAAAAAAAAAAA", "too long (classic board id length)"},
+		{"AAAAAAAAAAAAA!@", "bad base64"},
+		{"AAAAAAAAAAAAAAA", "no numbers"},
+		{"_______________", "all cells set"},
+	}
+	for i, test := range tests {
+		if _, err := TicketFromID(test.id); err == nil {
+			t.Errorf("test %v (%v): wanted error getting ticket from %q", i, test.name, test.id)
+		}
+	}
+}
+
+func TestNewStrip(t *testing.T) {
+	d := NewDealer(1257894001)
+	for i := 0; i < 100; i++ {
+		s := d.NewStrip()
+		for j, ticket := range s {
+			if !ticket.isValid() {
+				t.Fatalf("strip %v: ticket %v is not valid: %v", i, j, ticket)
+			}
+		}
+		if !s.isValid() {
+			t.Fatalf("strip %v does not use each number once: %v", i, s)
+		}
+		id, err := s.ID()
+		if err != nil {
+			t.Fatalf("strip %v: unwanted error getting id: %v", i, err)
+		}
+		got, err := StripFromID(id)
+		switch {
+		case err != nil:
+			t.Fatalf("strip %v: unwanted error getting strip from id %q: %v", i, id, err)
+		case !reflect.DeepEqual(s, got):
+			t.Fatalf("strip %v: strips not equal:\nwanted: %v\ngot:    %v", i, *s, *got)
+		}
+	}
+}
+
+func TestStripFromIDInvalid(t *testing.T) {
+	ticketID, err := ticket1.ID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sameTickets string
+	for range StripTickets {
+		sameTickets += ticketID
+	}
+	tests := []struct {
+		id   string
+		name string
+	}{
+		{"", "empty"},
+		{ticketID, "one ticket"},
+		{sameTickets[:75] + "AAAAAAAAAAAAAAA", "invalid ticket"},
+		{sameTickets, "numbers used more than once"},
+	}
+	for i, test := range tests {
+		if _, err := StripFromID(test.id); err == nil {
+			t.Errorf("test %v (%v): wanted error getting strip from %q", i, test.name, test.id)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package bingo
+
+// Win is when a board first had a pattern as the numbers of a game were drawn.
+type Win struct {
+	// Pattern is the pattern that was checked.
+	Pattern Pattern
+	// Draw is how many numbers were drawn when the board first had the pattern, or 0 if it does not have it.
+	Draw int
+	// Number is the drawn number that first formed the pattern.
+	Number Number
+	// Sleeper is whether the board already had the pattern before the most recent number was drawn.
+	Sleeper bool
+}
+
+// Replay draws the numbers of the game again, one at a time, to find when the board first had each pattern.
+// A win is returned for each pattern, in the same order.
+func (b Board) Replay(g Game, patterns ...Pattern) []Win {
+	wins := make([]Win, len(patterns))
+	for i, p := range patterns {
+		wins[i].Pattern = p
+	}
+	cells := make(map[Number]int, len(b))
+	for i, n := range b {
+		cells[n] = i
+	}
+	var drawn Mask
+	if b.HasFreeCell() {
+		drawn = freeCellMask
+	}
+	drawnNumbers := g.DrawnNumbers()
+	for d, n := range drawnNumbers {
+		i, ok := cells[n]
+		if !ok || n == 0 {
+			continue
+		}
+		drawn |= 1 << i
+		for j := range wins {
+			if wins[j].Draw == 0 && wins[j].Pattern.matches(drawn) {
+				wins[j].Draw = d + 1
+				wins[j].Number = n
+				wins[j].Sleeper = d+1 < len(drawnNumbers)
+			}
+		}
+	}
+	return wins
+}
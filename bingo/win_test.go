@@ -0,0 +1,70 @@
+package bingo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBoardReplay(t *testing.T) {
+	b := board1257894001
+	fourCorners, _ := PatternByName("FourCorners")
+	tests := []struct {
+		name     string
+		nums     []Number
+		patterns []Pattern
+		want     []Win
+	}{
+		{
+			name: "no patterns",
+			nums: []Number{15, 8, 4, 12, 10},
+			want: []Win{},
+		},
+		{
+			name:     "no numbers",
+			patterns: []Pattern{linePattern},
+			want:     []Win{{Pattern: linePattern}},
+		},
+		{
+			name:     "line on last number",
+			nums:     []Number{1, 15, 8, 4, 12, 10},
+			patterns: []Pattern{linePattern, filledPattern},
+			want: []Win{
+				{Pattern: linePattern, Draw: 6, Number: 10},
+				{Pattern: filledPattern},
+			},
+		},
+		{
+			name:     "sleeper",
+			nums:     []Number{15, 8, 4, 12, 10, 64, 74, 1},
+			patterns: []Pattern{linePattern, fourCorners},
+			want: []Win{
+				{Pattern: linePattern, Draw: 5, Number: 10, Sleeper: true},
+				{Pattern: fourCorners, Draw: 7, Number: 74, Sleeper: true},
+			},
+		},
+		{
+			name:     "all numbers",
+			nums:     []Number{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44, 45, 46, 47, 48, 49, 50, 51, 52, 53, 54, 55, 56, 57, 58, 59, 60, 61, 62, 63, 64, 65, 66, 67, 68, 69, 70, 71, 72, 73, 74, 75},
+			patterns: []Pattern{filledPattern, linePattern},
+			want: []Win{
+				{Pattern: filledPattern, Draw: 74, Number: 74, Sleeper: true},
+				{Pattern: linePattern, Draw: 15, Number: 15, Sleeper: true},
+			},
+		},
+	}
+	for i, test := range tests {
+		g := newTestingGame(t, test.nums)
+		if want, got := test.want, b.Replay(g, test.patterns...); !reflect.DeepEqual(want, got) {
+			t.Errorf("test %v (%v): wins not equal:\nwanted: %+v\ngot:    %+v", i, test.name, want, got)
+		}
+	}
+}
+
+func TestBoardReplayNoFreeCell(t *testing.T) {
+	b := Board{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 33, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65}
+	g := newTestingGame(t, []Number{3, 18, 48, 63, 33})
+	want := []Win{{Pattern: linePattern, Draw: 5, Number: 33}}
+	if got := b.Replay(g, linePattern); !reflect.DeepEqual(want, got) {
+		t.Errorf("wins not equal, wanted line through center to be completed by the center number:\nwanted: %+v\ngot:    %+v", want, got)
+	}
+}
@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
+)
+
+type (
+	// jsonErrors writes problems to responses as JSON error objects.
+	jsonErrors struct{}
+	// apiError is the JSON body of an error response.
+	apiError struct {
+		Error apiErrorDetail `json:"error"`
+	}
+	// apiErrorDetail describes the problem of an error response.
+	apiErrorDetail struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	}
+	// apiGame is the JSON state of a game.
+	// The code is only set for stored games, the seed is only set for games shuffled from seeds, and the commitment is only set for committed games.
+	// The drawn numbers are grouped by the names of the card columns of the game.
+	apiGame struct {
+		ID             string           `json:"id"`
+		Code           string           `json:"code,omitempty"`
+		Seed           string           `json:"seed,omitempty"`
+		Commitment     string           `json:"commitment,omitempty"`
+		Balls          int              `json:"balls"`
+		DrawnNumbers   []int            `json:"drawnNumbers"`
+		Columns        map[string][]int `json:"columns"`
+		NumbersLeft    int              `json:"numbersLeft"`
+		PreviousNumber int              `json:"previousNumber,omitempty"`
+	}
+	// apiBoard is the JSON form of a board or card.
+	// The columns are listed from left to right, with the numbers from top to bottom.  The free cell is 0.
+	apiBoard struct {
+		ID      string  `json:"id"`
+		Columns [][]int `json:"columns"`
+	}
+	// apiBoards is the JSON list of ids of new boards.
+	apiBoards struct {
+		IDs []string `json:"ids"`
+	}
+	// apiCheck is the JSON result of checking a board for a pattern in a game.
+	apiCheck struct {
+		GameID  string `json:"gameID"`
+		BoardID string `json:"boardID"`
+		Type    string `json:"type"`
+		Bingo   bool   `json:"bingo"`
+		// Counterfeit is set if boards are signed and the board id does not have a valid authenticity code.
+		Counterfeit bool   `json:"counterfeit,omitempty"`
+		Line        string `json:"line,omitempty"`
+		Cells       []int  `json:"cells,omitempty"`
+		Number      int    `json:"number,omitempty"`
+	}
+)
+
+// apiGetGame writes the state of the game from the 'gameID' query parameter.
+func (h handler) apiGetGame(w http.ResponseWriter, r *http.Request) {
+	gameID := r.URL.Query().Get("gameID")
+	g, ok := h.apiParseGame(gameID, w)
+	if !ok {
+		return
+	}
+	h.writeGame(w, http.StatusOK, *g, gameID)
+}
+
+// apiCreateGame writes the state of a new game.
+// The optional 'balls' form parameter is the ball count of the game, 75 by default.
+// The optional 'rules' form parameter creates a game for cards with other rules instead.
+// The optional 'seed' form parameter creates a 75-ball game with a draw order that can be reproduced from the seed.
+// The optional 'commit' form parameter securely shuffles the game and publishes a commitment to the order of its numbers.  Games are only committed if they are stored.
+func (h handler) apiCreateGame(w http.ResponseWriter, r *http.Request) {
+	var e jsonErrors
+	g, ok := parseNewGame(r.FormValue("balls"), r.FormValue("rules"), r.FormValue("seed"), w, e)
+	if !ok {
+		return
+	}
+	commit := len(r.FormValue("commit")) != 0
+	if !commitNewGame(g, commit, h.games != nil, w, e) {
+		return
+	}
+	gameID, err := h.newGameID(*g)
+	if err != nil {
+		e.internalServerError(w, err)
+		return
+	}
+	h.writeGame(w, http.StatusCreated, *g, gameID)
+}
+
+// apiDrawNumber draws a new number for the game specified by the request's 'gameID' form parameter, writing the updated state.
+// It is a conflict to draw a number after all numbers have been drawn.
+func (h *handler) apiDrawNumber(w http.ResponseWriter, r *http.Request) {
+	var e jsonErrors
+	gameID := r.FormValue("gameID")
+	defer h.lockGame(gameID)()
+	g, ok := h.apiParseGame(gameID, w)
+	if !ok {
+		return
+	}
+	_, drawn, err := h.drawGameNumber(g, gameID)
+	switch {
+	case err != nil:
+		e.internalServerError(w, err)
+		return
+	case !drawn:
+		e.write(w, http.StatusConflict, "all numbers have been drawn")
+		return
+	}
+	h.writeGame(w, http.StatusOK, *g, gameID)
+}
+
+// apiGetBoard writes the board or card from the 'boardID' query parameter.
+// The authenticity code at the end of the id of signed boards is kept in the id that is written.
+func (h handler) apiGetBoard(w http.ResponseWriter, r *http.Request) {
+	boardID := r.URL.Query().Get("boardID")
+	unsignedID, _, _ := h.boardSigner.verify(boardID)
+	c, ok := parseCard(unsignedID, w, jsonErrors{})
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPIBoard(*c, boardID))
+}
+
+// apiCreateBoard writes a new board.
+// The optional 'rules' form parameter creates a card with other rules, such as 6x6n16f22 for 6x6 cards with a free cell.
+// The optional 'issue' form parameter is the batch or event number that is signed in the authenticity code at the end of the id, if boards are signed.
+func (h handler) apiCreateBoard(w http.ResponseWriter, r *http.Request) {
+	var e jsonErrors
+	rules, ok := parseRules(r.FormValue("rules"), w, e)
+	if !ok {
+		return
+	}
+	issue, ok := parseIssue(r.FormValue("issue"), w, e)
+	if !ok {
+		return
+	}
+	c, _ := h.dealer.NewCard(*rules) // the rules are valid
+	boardID, err := c.ID()
+	if err != nil {
+		err := fmt.Errorf("getting new card id: %v\ncard: %#v", err, c)
+		e.internalServerError(w, err)
+		return
+	}
+	signedID := h.boardSigner.sign(boardID, issue)
+	writeJSON(w, http.StatusCreated, newAPIBoard(*c, signedID))
+}
+
+// apiCreateBoards writes the ids of 'n' new boards as specified by the request's form parameter.
+// The optional 'minDistance' form parameter is the fewest numbers that each board must have that are not on each other board.
+// The optional 'issue' form parameter is the batch or event number that is signed in the authenticity codes at the end of the ids, if boards are signed.
+func (h handler) apiCreateBoards(w http.ResponseWriter, r *http.Request) {
+	var e jsonErrors
+	n, ok := parseBoardCount(r.FormValue("n"), w, e)
+	if !ok {
+		return
+	}
+	minDistance, ok := parseMinDistance(r.FormValue("minDistance"), w, e)
+	if !ok {
+		return
+	}
+	issue, ok := parseIssue(r.FormValue("issue"), w, e)
+	if !ok {
+		return
+	}
+	_, boardIDs, err := h.newBoards(n, false, nil, minDistance)
+	switch {
+	case errors.Is(err, bingo.ErrBatchNotUnique):
+		e.badRequest(w, err.Error())
+		return
+	case err != nil:
+		e.internalServerError(w, err)
+		return
+	}
+	for i, boardID := range boardIDs {
+		boardIDs[i] = h.boardSigner.sign(boardID, issue)
+	}
+	boards := apiBoards{
+		IDs: boardIDs,
+	}
+	writeJSON(w, http.StatusCreated, boards)
+}
+
+// apiCheckBoard checks the board on the game using the 'gameID', 'boardID', and 'type' query parameters, writing the result.
+// The board is a card with the rules of the game if the game has rules, which can only be checked for HasLine or IsFilled.
+// The type is the name of a pattern in the bingo pattern library or Custom to use the pattern from the 'pattern' query parameter.
+// The board is flagged as counterfeit if boards are signed and the id does not end with a valid authenticity code.
+func (h handler) apiCheckBoard(w http.ResponseWriter, r *http.Request) {
+	var e jsonErrors
+	gameID := r.URL.Query().Get("gameID")
+	g, ok := h.apiParseGame(gameID, w)
+	if !ok {
+		return
+	}
+	boardID := r.URL.Query().Get("boardID")
+	unsignedID, _, err := h.boardSigner.verify(boardID)
+	checkType := r.URL.Query().Get("type")
+	if g.Rules() != nil {
+		card, ok := parseCard(unsignedID, w, e)
+		if !ok {
+			return
+		}
+		result, ok := checkCard(*card, *g, checkType, w, e)
+		if !ok {
+			return
+		}
+		h.publishClaim(gameID, boardID, checkType, result, err != nil)
+		c := apiCheck{
+			GameID:      gameID,
+			BoardID:     boardID,
+			Type:        checkType,
+			Bingo:       result,
+			Counterfeit: err != nil,
+		}
+		writeJSON(w, http.StatusOK, c)
+		return
+	}
+	b, ok := parseBoard(unsignedID, w, e)
+	if !ok || !checkClassicGame(*g, w, e) {
+		return
+	}
+	patternID := r.URL.Query().Get("pattern")
+	p, ok := checkPattern(checkType, patternID, w, e)
+	if !ok {
+		return
+	}
+	result := b.Check(*g, *p)
+	h.publishClaim(gameID, boardID, checkType, result.Bingo, err != nil)
+	c := apiCheck{
+		GameID:      gameID,
+		BoardID:     boardID,
+		Type:        checkType,
+		Bingo:       result.Bingo,
+		Counterfeit: err != nil,
+		Line:        result.LineName(),
+		Number:      result.Number.Value(),
+	}
+	for i := range b {
+		if result.Cells.Has(i) {
+			c.Cells = append(c.Cells, i)
+		}
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+// writeGame writes the JSON state of the game that was requested with the id.
+func (h handler) writeGame(w http.ResponseWriter, statusCode int, g bingo.Game, requestID string) {
+	gameID, err := g.ID()
+	if err != nil {
+		err := fmt.Errorf("getting game id: %v\ngame: %#v", err, g)
+		jsonErrors{}.internalServerError(w, err)
+		return
+	}
+	code, _ := h.gameCode(requestID)
+	writeJSON(w, statusCode, newAPIGame(g, h.signer.sign(gameID), code))
+}
+
+// newAPIGame creates the JSON state of the game.
+func newAPIGame(g bingo.Game, gameID, code string) apiGame {
+	drawnNumbers := g.DrawnNumbers()
+	a := apiGame{
+		ID:             gameID,
+		Code:           code,
+		Commitment:     g.Commitment(),
+		Balls:          g.Balls(),
+		DrawnNumbers:   make([]int, len(drawnNumbers)),
+		Columns:        make(map[string][]int, g.Columns()),
+		NumbersLeft:    g.NumbersLeft(),
+		PreviousNumber: g.PreviousNumberDrawn().Value(),
+	}
+	if g.Seeded() {
+		a.Seed = strconv.FormatUint(g.Seed(), 10) // a string so large seeds are not rounded by JavaScript
+	}
+	for i, n := range drawnNumbers {
+		a.DrawnNumbers[i] = n.Value()
+	}
+	for c, nums := range g.DrawnNumberColumns() {
+		name := g.ColumnName(c)
+		for _, n := range nums {
+			a.Columns[name] = append(a.Columns[name], n.Value())
+		}
+	}
+	return a
+}
+
+// newAPIBoard creates the JSON form of the card.
+func newAPIBoard(card bingo.Card, boardID string) apiBoard {
+	a := apiBoard{
+		ID:      boardID,
+		Columns: make([][]int, card.Rules.Width),
+	}
+	for c := range a.Columns {
+		a.Columns[c] = make([]int, card.Rules.Height)
+		for r := range a.Columns[c] {
+			a.Columns[c][r] = card.Numbers[c*card.Rules.Height+r].Value()
+		}
+	}
+	return a
+}
+
+// writeJSON writes the value to the response as JSON with the status code.
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(v)
+}
+
+// apiParseGame gets the game with the id for the JSON api, writing problems to the response.
+func (h handler) apiParseGame(id string, w http.ResponseWriter) (g *bingo.Game, ok bool) {
+	g, err := h.findGame(id)
+	if err != nil {
+		message := fmt.Sprintf("getting game from query parameter: %v", err)
+		jsonErrors{}.badRequest(w, message)
+		return nil, false
+	}
+	return g, true
+}
+
+// badRequest tells the response that a bad request was made.
+func (e jsonErrors) badRequest(w http.ResponseWriter, message string) {
+	e.write(w, http.StatusBadRequest, message)
+}
+
+// internalServerError tells the response that an unexpected error occurred.
+func (e jsonErrors) internalServerError(w http.ResponseWriter, err error) {
+	message := fmt.Sprintf("unexpected problem: %v", err)
+	e.write(w, http.StatusInternalServerError, message)
+}
+
+// write writes the message as a JSON error with the status code.
+func (jsonErrors) write(w http.ResponseWriter, statusCode int, message string) {
+	err := apiError{
+		Error: apiErrorDetail{
+			Status:  statusCode,
+			Message: message,
+		},
+	}
+	writeJSON(w, statusCode, err)
+}
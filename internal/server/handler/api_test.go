@@ -0,0 +1,375 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAPI(t *testing.T) {
+	const contentTypeJSON = "application/json"
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		wantStatusCode int
+		want           string
+	}{
+		{
+			name:           "create game",
+			method:         methodPost,
+			target:         urlPathAPIGame,
+			wantStatusCode: 201,
+			want:           `{"id":"0","balls":75,"drawnNumbers":[],"columns":{},"numbersLeft":75}`,
+		},
+		{
+			name:           "create 30-ball game",
+			method:         methodPost,
+			target:         urlPathAPIGame,
+			body:           "balls=30",
+			wantStatusCode: 201,
+			want:           `{"id":"v2-0-AAAAAAAAAAAAAAAAAAA","balls":30,"drawnNumbers":[],"columns":{},"numbersLeft":30}`,
+		},
+		{
+			name:           "create game - bad balls",
+			method:         methodPost,
+			target:         urlPathAPIGame,
+			body:           "balls=76",
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"games must have 30, 75, 80, or 90 balls"}}`,
+		},
+		{
+			name:           "create rules game",
+			method:         methodPost,
+			target:         urlPathAPIGame,
+			body:           "rules=3x3n10",
+			wantStatusCode: 201,
+			want:           `{"id":"3x3n10.v2-0-AAAAAAAAAAAAAAAAAAA","balls":30,"drawnNumbers":[],"columns":{},"numbersLeft":30}`,
+		},
+		{
+			name:           "create game - bad rules",
+			method:         methodPost,
+			target:         urlPathAPIGame,
+			body:           "rules=9x9n10",
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"parsing rules: cards must have between 1 and 8 rows and columns"}}`,
+		},
+		{
+			name:           "create game - balls and rules",
+			method:         methodPost,
+			target:         urlPathAPIGame,
+			body:           "balls=30&rules=3x3n10",
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"games can have balls or rules, not both"}}`,
+		},
+		{
+			name:           "create seeded game",
+			method:         methodPost,
+			target:         urlPathAPIGame,
+			body:           "seed=42&balls=75",
+			wantStatusCode: 201,
+			want:           `{"id":"s42-0","seed":"42","balls":75,"drawnNumbers":[],"columns":{},"numbersLeft":75}`,
+		},
+		{
+			name:           "create game - seed with rules",
+			method:         methodPost,
+			target:         urlPathAPIGame,
+			body:           "seed=42&rules=4x4n25",
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"games from seeds are 75-ball games without rules"}}`,
+		},
+		{
+			name:           "create game - bad seed",
+			method:         methodPost,
+			target:         urlPathAPIGame,
+			body:           "seed=-1",
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"parsing seed: `,
+		},
+		{
+			name:           "create committed game - not stored",
+			method:         methodPost,
+			target:         urlPathAPIGame,
+			body:           "commit=true",
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"games can only be committed when games are stored, so the order of their numbers is kept secret"}}`,
+		},
+		{
+			name:           "create game - seed with commit",
+			method:         methodPost,
+			target:         urlPathAPIGame,
+			body:           "seed=42&commit=true",
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"games from seeds cannot be committed"}}`,
+		},
+		{
+			name:           "draw number in seeded game",
+			method:         methodPost,
+			target:         urlPathAPIGameDrawNumber,
+			body:           qpGameID + "=s42-1",
+			wantStatusCode: 200,
+			want:           `{"id":"s42-2","seed":"42","balls":75,"drawnNumbers":[5,46],"columns":{"B":[5],"G":[46]},"numbersLeft":73,"previousNumber":46}`,
+		},
+		{
+			name:           "get game",
+			method:         methodGet,
+			target:         urlPathAPIGame + "?" + qpGameID + "=v2-3-" + board1257894001IDNumbers,
+			wantStatusCode: 200,
+			want:           `{"id":"v2-3-` + board1257894001IDNumbers + `","balls":75,"drawnNumbers":[15,8,4],"columns":{"B":[15,8,4]},"numbersLeft":72,"previousNumber":4}`,
+		},
+		{
+			name:           "get game (legacy id)",
+			method:         methodGet,
+			target:         urlPathAPIGame + "?" + qpGameID + "=3-" + board1257894001LegacyIDNumbers,
+			wantStatusCode: 200,
+			want:           `{"id":"v2-3-` + board1257894001IDNumbers + `","balls":75,"drawnNumbers":[15,8,4],"columns":{"B":[15,8,4]},"numbersLeft":72,"previousNumber":4}`,
+		},
+		{
+			name:           "get game - bad id",
+			method:         methodGet,
+			target:         urlPathAPIGame + "?" + qpGameID + "=" + badID,
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"getting game from query parameter: `,
+		},
+		{
+			name:           "draw number",
+			method:         methodPost,
+			target:         urlPathAPIGameDrawNumber,
+			body:           qpGameID + "=v2-4-" + board1257894001IDNumbers,
+			wantStatusCode: 200,
+			want:           `{"id":"v2-5-` + board1257894001IDNumbers + `","balls":75,"drawnNumbers":[15,8,4,12,10],"columns":{"B":[15,8,4,12,10]},"numbersLeft":70,"previousNumber":10}`,
+		},
+		{
+			name:           "draw number - all drawn",
+			method:         methodPost,
+			target:         urlPathAPIGameDrawNumber,
+			body:           qpGameID + "=v2-75-" + board1257894001IDNumbers,
+			wantStatusCode: 409,
+			want:           `{"error":{"status":409,"message":"all numbers have been drawn"}}`,
+		},
+		{
+			name:           "get board",
+			method:         methodGet,
+			target:         urlPathAPIBoard + "?" + qpBoardID + "=" + board1257894001ID,
+			wantStatusCode: 200,
+			want:           `{"id":"` + board1257894001ID + `","columns":[[15,8,4,12,10],[19,27,16,28,25],[42,41,0,31,40],[49,52,50,46,57],[64,72,67,70,74]]}`,
+		},
+		{
+			name:           "get card",
+			method:         methodGet,
+			target:         urlPathAPIBoard + "?" + qpBoardID + "=3x3n10f11.ASAQEg",
+			wantStatusCode: 200,
+			want:           `{"id":"3x3n10f11.ASAQEg","columns":[[1,2,3],[11,0,12],[21,22,23]]}`,
+		},
+		{
+			name:           "create board - bad rules",
+			method:         methodPost,
+			target:         urlPathAPIBoard,
+			body:           "rules=5x5",
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"parsing rules: `,
+		},
+		{
+			name:           "create boards - bad n",
+			method:         methodPost,
+			target:         urlPathAPIBoards,
+			body:           "n=1001",
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"n must be be between 1 and 1000"}}`,
+		},
+		{
+			name:           "create boards - bad minDistance",
+			method:         methodPost,
+			target:         urlPathAPIBoards,
+			body:           "n=2&minDistance=26",
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"minDistance must be a number between 0 and 25"}}`,
+		},
+		{
+			name:           "create boards - not unique enough",
+			method:         methodPost,
+			target:         urlPathAPIBoards,
+			body:           "n=5&minDistance=24",
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"creating boards: board #`,
+		},
+		{
+			name:           "check board",
+			method:         methodGet,
+			target:         urlPathAPIBoardCheck + "?" + qpGameID + "=v2-5-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + typeHasLine,
+			wantStatusCode: 200,
+			want:           `{"gameID":"v2-5-` + board1257894001IDNumbers + `","boardID":"` + board1257894001ID + `","type":"HasLine","bingo":true,"line":"B column","cells":[0,1,2,3,4],"number":10}`,
+		},
+		{
+			name:           "check board - no bingo",
+			method:         methodGet,
+			target:         urlPathAPIBoardCheck + "?" + qpGameID + "=v2-4-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + typeCustom + "&" + qpPattern + "=" + patternBColumnID,
+			wantStatusCode: 200,
+			want:           `{"gameID":"v2-4-` + board1257894001IDNumbers + `","boardID":"` + board1257894001ID + `","type":"Custom","bingo":false}`,
+		},
+		{
+			name:           "check card in rules game",
+			method:         methodGet,
+			target:         urlPathAPIBoardCheck + "?" + qpGameID + "=" + rulesGame3ID + "&" + qpBoardID + "=" + card3x3n10ID + "&" + qpType + "=" + typeHasLine,
+			wantStatusCode: 200,
+			want:           `{"gameID":"` + rulesGame3ID + `","boardID":"` + card3x3n10ID + `","type":"HasLine","bingo":true}`,
+		},
+		{
+			name:           "check card in rules game - other rules",
+			method:         methodGet,
+			target:         urlPathAPIBoardCheck + "?" + qpGameID + "=" + rulesGame3ID + "&" + qpBoardID + "=3x3n11.ASASASA&" + qpType + "=" + typeHasLine,
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"card with rules 3x3n11 is not played in the game"}}`,
+		},
+		{
+			name:           "check card in rules game - bad check type",
+			method:         methodGet,
+			target:         urlPathAPIBoardCheck + "?" + qpGameID + "=" + rulesGame3ID + "&" + qpBoardID + "=" + card3x3n10ID + "&" + qpType + "=" + typeFourCorners,
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"cards can only be checked for HasLine or IsFilled, got \"FourCorners\""}}`,
+		},
+		{
+			name:           "check board - 90-ball game",
+			method:         methodGet,
+			target:         urlPathAPIBoardCheck + "?" + qpGameID + "=" + game90ID + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + typeHasLine,
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"boards can only be checked in 75-ball games without rules"}}`,
+		},
+		{
+			name:           "check board - bad check type",
+			method:         methodGet,
+			target:         urlPathAPIBoardCheck + "?" + qpGameID + "=v2-5-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + badID,
+			wantStatusCode: 400,
+			want:           `{"error":{"status":400,"message":"unknown checkType \"BAD-ID\""}}`,
+		},
+	}
+	for i, test := range tests {
+		r := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+		r.Header = formContentTypeHeader
+		w := httptest.NewRecorder()
+		var h handler
+		h.ServeHTTP(w, r)
+		switch {
+		case test.wantStatusCode != w.Code:
+			t.Errorf("test %v (%v): status codes not equal: wanted %v, got %v", i, test.name, test.wantStatusCode, w.Code)
+		case w.Header().Get(headerContentType) != contentTypeJSON:
+			t.Errorf("test %v (%v): wanted %v content type, got %q", i, test.name, contentTypeJSON, w.Header().Get(headerContentType))
+		case !strings.HasPrefix(w.Body.String(), test.want):
+			t.Errorf("test %v (%v): bodies not equal:\nwanted: %v\ngot:    %v", i, test.name, test.want, w.Body.String())
+		}
+	}
+}
+
+func TestAPICreateBoards(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		wantN  int
+	}{
+		{"one board", urlPathAPIBoard, "", 1},
+		{"one card", urlPathAPIBoard, "rules=6x6n16f22", 1},
+		{"many boards", urlPathAPIBoards, "n=3", 3},
+		{"many boards with minimum distance", urlPathAPIBoards, "n=3&minDistance=12", 3},
+	}
+	for i, test := range tests {
+		r := httptest.NewRequest(methodPost, test.target, strings.NewReader(test.body))
+		r.Header = formContentTypeHeader
+		w := httptest.NewRecorder()
+		var h handler
+		h.ServeHTTP(w, r)
+		var boards apiBoards
+		var board apiBoard
+		var err error
+		if test.wantN == 1 {
+			err = json.NewDecoder(w.Body).Decode(&board)
+			boards.IDs = []string{board.ID}
+		} else {
+			err = json.NewDecoder(w.Body).Decode(&boards)
+		}
+		switch {
+		case err != nil:
+			t.Errorf("test %v (%v): decoding response: %v", i, test.name, err)
+		case w.Code != 201:
+			t.Errorf("test %v (%v): wanted created status code, got %v", i, test.name, w.Code)
+		case len(boards.IDs) != test.wantN:
+			t.Errorf("test %v (%v): wanted %v boards, got %v", i, test.name, test.wantN, boards.IDs)
+		}
+		for j, boardID := range boards.IDs {
+			r := httptest.NewRequest(methodGet, urlPathAPIBoard+"?"+qpBoardID+"="+boardID, nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			var got apiBoard
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil || got.ID != boardID {
+				t.Errorf("test %v (%v): board %v: wanted valid board id %q, got %v (%v)", i, test.name, j, boardID, got, err)
+			}
+		}
+	}
+}
+
+func TestAPISignedBoardsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		body   string
+	}{
+		{"one board", urlPathAPIBoard, "issue=7"},
+		{"many boards", urlPathAPIBoards, "n=3&issue=7"},
+	}
+	for i, test := range tests {
+		h := handler{
+			boardSigner: boardSigner("secret-key"),
+		}
+		r := httptest.NewRequest(methodPost, test.target, strings.NewReader(test.body))
+		r.Header = formContentTypeHeader
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		var boards apiBoards
+		var board apiBoard
+		var err error
+		if test.target == urlPathAPIBoard {
+			err = json.NewDecoder(w.Body).Decode(&board)
+			boards.IDs = []string{board.ID}
+		} else {
+			err = json.NewDecoder(w.Body).Decode(&boards)
+		}
+		if err != nil {
+			t.Errorf("test %v (%v): decoding created boards: %v", i, test.name, err)
+			continue
+		}
+		for j, boardID := range boards.IDs {
+			r := httptest.NewRequest(methodGet, urlPathAPIBoard+"?"+qpBoardID+"="+boardID, nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			var got apiBoard
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil || got.ID != boardID {
+				t.Errorf("test %v (%v): board %v: wanted signed board %q, got %v (%v): %v", i, test.name, j, boardID, got, w.Code, err)
+			}
+			r = httptest.NewRequest(methodGet, urlPathAPIBoardCheck+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+boardID+"&"+qpType+"="+typeHasLine, nil)
+			w = httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			var check apiCheck
+			switch err := json.NewDecoder(w.Body).Decode(&check); {
+			case err != nil:
+				t.Errorf("test %v (%v): board %v: decoding check: %v", i, test.name, j, err)
+			case check.Counterfeit:
+				t.Errorf("test %v (%v): board %v: wanted created board %q to not be counterfeit", i, test.name, j, boardID)
+			}
+		}
+	}
+}
+
+func TestAPIDrawNumberAddsGameInfo(t *testing.T) {
+	var h handler
+	r := httptest.NewRequest(methodPost, urlPathAPIGameDrawNumber, strings.NewReader(qpGameID+"=v2-8-"+board1257894001IDNumbers))
+	r.Header = formContentTypeHeader
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	want := []gameInfo{{ID: "v2-9-" + board1257894001IDNumbers, NumbersLeft: 66}}
+	if got := h.gameInfos.infos; !reflect.DeepEqual(want, got) {
+		t.Errorf("game infos not equal:\nwanted: %v\ngot:    %v", want, got)
+	}
+}
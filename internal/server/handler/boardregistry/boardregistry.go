@@ -0,0 +1,87 @@
+// Package boardregistry records the ids of issued boards in a file so batches of boards are never repeated.
+package boardregistry
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
+)
+
+// File records the ids of boards in a text file, one per line.
+// The ids of each new batch are appended to the file so boards are remembered when the server restarts.
+type File struct {
+	name     string
+	mu       sync.Mutex
+	boardIDs map[string]struct{}
+}
+
+// Open loads the board ids in the file with the name.  The file is created when the first batch is registered if it does not exist.
+func Open(name string) (*File, error) {
+	f := File{
+		name:     name,
+		boardIDs: make(map[string]struct{}),
+	}
+	r, err := os.Open(name)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return &f, nil
+	case err != nil:
+		return nil, fmt.Errorf("opening board registry file: %v", err)
+	}
+	defer r.Close()
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if id := strings.TrimSpace(s.Text()); len(id) != 0 {
+			f.boardIDs[id] = struct{}{}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("reading board registry file: %v", err)
+	}
+	return &f, nil
+}
+
+// Issued determines if the board id has been registered.
+func (f *File) Issued(boardID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.boardIDs[boardID]
+	return ok, nil
+}
+
+// RegisterNew appends the board ids to the file.  The ids are only remembered if they are written.
+// The ids are checked while the file is locked, so bingo.ErrBoardIssued is returned and nothing is written if any board has already been registered.
+func (f *File) RegisterNew(boardIDs []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range boardIDs {
+		if _, ok := f.boardIDs[id]; ok {
+			return fmt.Errorf("%w: %v", bingo.ErrBoardIssued, id)
+		}
+	}
+	var sb strings.Builder
+	for _, id := range boardIDs {
+		sb.WriteString(id)
+		sb.WriteString("\n")
+	}
+	w, err := os.OpenFile(f.name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening board registry file: %v", err)
+	}
+	if _, err := w.WriteString(sb.String()); err != nil {
+		w.Close()
+		return fmt.Errorf("writing board registry file: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("closing board registry file: %v", err)
+	}
+	for _, id := range boardIDs {
+		f.boardIDs[id] = struct{}{}
+	}
+	return nil
+}
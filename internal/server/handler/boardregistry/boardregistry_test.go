@@ -0,0 +1,116 @@
+package boardregistry
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
+)
+
+func TestFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "boards.txt")
+	f, err := Open(name)
+	if err != nil {
+		t.Fatalf("opening new registry: %v", err)
+	}
+	if err := f.RegisterNew([]string{"board-1", "board-2"}); err != nil {
+		t.Fatalf("registering first batch: %v", err)
+	}
+	if err := f.RegisterNew([]string{"board-3"}); err != nil {
+		t.Fatalf("registering second batch: %v", err)
+	}
+	f2, err := Open(name)
+	if err != nil {
+		t.Fatalf("reopening registry: %v", err)
+	}
+	tests := []struct {
+		boardID string
+		want    bool
+	}{
+		{"board-1", true},
+		{"board-2", true},
+		{"board-3", true},
+		{"board-4", false},
+	}
+	for i, test := range tests {
+		got, err := f2.Issued(test.boardID)
+		switch {
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.boardID, err)
+		case test.want != got:
+			t.Errorf("test %v (%v): wanted issued to be %v", i, test.boardID, test.want)
+		}
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	if _, err := Open(t.TempDir()); err == nil {
+		t.Errorf("wanted error opening directory")
+	}
+}
+
+func TestFileRegisterError(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "missing-dir", "boards.txt")
+	f, err := Open(name)
+	if err != nil {
+		t.Fatalf("opening new registry: %v", err)
+	}
+	if err := f.RegisterNew([]string{"board-1"}); err == nil {
+		t.Errorf("wanted error registering boards when folder of file does not exist")
+	}
+	if issued, _ := f.Issued("board-1"); issued {
+		t.Errorf("wanted board to not be kept after write error")
+	}
+	if _, err := os.Stat(name); err == nil {
+		t.Errorf("wanted file to not be created")
+	}
+}
+
+func TestFileRegisterNewIssued(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "boards.txt")
+	f, err := Open(name)
+	if err != nil {
+		t.Fatalf("opening new registry: %v", err)
+	}
+	if err := f.RegisterNew([]string{"board-1"}); err != nil {
+		t.Fatalf("registering first batch: %v", err)
+	}
+	if err := f.RegisterNew([]string{"board-2", "board-1"}); !errors.Is(err, bingo.ErrBoardIssued) {
+		t.Errorf("wanted %v registering batch with issued board, got: %v", bingo.ErrBoardIssued, err)
+	}
+	if issued, _ := f.Issued("board-2"); issued {
+		t.Errorf("wanted other boards of batch to not be registered when a board is already issued")
+	}
+	f2, err := Open(name)
+	if err != nil {
+		t.Fatalf("reopening registry: %v", err)
+	}
+	if want, got := 1, len(f2.boardIDs); want != got {
+		t.Errorf("wanted %v board ids written to file, got %v", want, got)
+	}
+}
+
+func TestFileRegisterNewConcurrent(t *testing.T) {
+	f, err := Open(filepath.Join(t.TempDir(), "boards.txt"))
+	if err != nil {
+		t.Fatalf("opening new registry: %v", err)
+	}
+	const n = 10
+	errs := make(chan error, n)
+	for range n {
+		go func() {
+			errs <- f.RegisterNew([]string{"board-1"})
+		}()
+	}
+	registered := 0
+	for range n {
+		if err := <-errs; err == nil {
+			registered++
+		}
+	}
+	if registered != 1 {
+		t.Errorf("wanted board to only be registered by one batch, got %v", registered)
+	}
+}
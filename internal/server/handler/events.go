@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
+)
+
+type (
+	// gameChannels sends the events of stored games to subscribers, keyed by game code.
+	gameChannels struct {
+		mu          sync.Mutex
+		subscribers map[string]map[chan gameEvent]struct{}
+	}
+	// gameEvent is something that happened in a game.
+	gameEvent struct {
+		// Type is the name of the event: draw, undo, claim, or end.
+		Type string
+		// Data is the JSON value of the event.
+		Data interface{}
+	}
+	// drawEvent is sent when a number is drawn in a game.
+	drawEvent struct {
+		GameID      string `json:"gameID"`
+		Number      int    `json:"number"`
+		Label       string `json:"label"`
+		Column      int    `json:"column"`
+		NumbersLeft int    `json:"numbersLeft"`
+	}
+	// undoEvent is sent when the last draw of a game is undone.
+	undoEvent struct {
+		GameID      string `json:"gameID"`
+		Number      int    `json:"number"`
+		Label       string `json:"label"`
+		NumbersLeft int    `json:"numbersLeft"`
+		Reason      string `json:"reason"`
+	}
+	// claimEvent is sent when a board is checked for a BINGO in a game.
+	claimEvent struct {
+		BoardID string `json:"boardID"`
+		Type    string `json:"type"`
+		Bingo   bool   `json:"bingo"`
+		// Counterfeit is set if boards are signed and the board id does not have a valid authenticity code.
+		Counterfeit bool `json:"counterfeit,omitempty"`
+	}
+	// endEvent is sent when the last number in a game is drawn.
+	endEvent struct {
+		GameID string `json:"gameID"`
+	}
+)
+
+const (
+	// eventBufferSize is the number of events a subscriber can fall behind by before events are dropped.
+	eventBufferSize = 16
+	// eventsKeepAlive is how often a comment is sent to subscribers to keep their connections open.
+	eventsKeepAlive = 30 * time.Second
+)
+
+// subscribe creates a channel that receives the events of the game with the code.
+// The unsubscribe function must be called when the events are no longer read.
+func (gc *gameChannels) subscribe(code string) (events <-chan gameEvent, unsubscribe func()) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	if gc.subscribers == nil {
+		gc.subscribers = make(map[string]map[chan gameEvent]struct{})
+	}
+	if gc.subscribers[code] == nil {
+		gc.subscribers[code] = make(map[chan gameEvent]struct{})
+	}
+	ch := make(chan gameEvent, eventBufferSize)
+	gc.subscribers[code][ch] = struct{}{}
+	unsubscribe = func() {
+		gc.mu.Lock()
+		defer gc.mu.Unlock()
+		delete(gc.subscribers[code], ch)
+		if len(gc.subscribers[code]) == 0 {
+			delete(gc.subscribers, code)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// publish sends the event to the subscribers of the game with the code.
+// Subscribers that have fallen behind do not receive the event.
+func (gc *gameChannels) publish(code string, e gameEvent) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+	for ch := range gc.subscribers[code] {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// publishDraw sends the last drawn number of the stored game to subscribers, also ending the game if no numbers are left.
+func (h handler) publishDraw(code string, g bingo.Game, gameID string) {
+	n := g.PreviousNumberDrawn()
+	draw := drawEvent{
+		GameID:      gameID,
+		Number:      n.Value(),
+		Label:       g.Label(n),
+		Column:      g.Column(n),
+		NumbersLeft: g.NumbersLeft(),
+	}
+	h.events.publish(code, gameEvent{"draw", draw})
+	if g.NumbersLeft() == 0 {
+		end := endEvent{
+			GameID: gameID,
+		}
+		h.events.publish(code, gameEvent{"end", end})
+	}
+}
+
+// publishUndo sends the number that was put back in the stored game to subscribers, with the reason the draw was undone.
+func (h handler) publishUndo(code string, g bingo.Game, n bingo.Number, gameID, reason string) {
+	undo := undoEvent{
+		GameID:      gameID,
+		Number:      n.Value(),
+		Label:       g.Label(n),
+		NumbersLeft: g.NumbersLeft(),
+		Reason:      reason,
+	}
+	h.events.publish(code, gameEvent{"undo", undo})
+}
+
+// publishClaim sends the result of a board check to the subscribers of the game, if the game is stored.
+func (h handler) publishClaim(gameID, boardID, checkType string, hasBingo, counterfeit bool) {
+	code, ok := h.gameCode(gameID)
+	if !ok {
+		return
+	}
+	claim := claimEvent{
+		BoardID:     boardID,
+		Type:        checkType,
+		Bingo:       hasBingo,
+		Counterfeit: counterfeit,
+	}
+	h.events.publish(code, gameEvent{"claim", claim})
+}
+
+// getGameEvents streams the events of the stored game with the 'gameID' query parameter code as server-sent events.
+// The stream is written until the request is done.
+func (h handler) getGameEvents(w http.ResponseWriter, r *http.Request) {
+	gameID := r.URL.Query().Get("gameID")
+	code, ok := h.gameCode(gameID)
+	if !ok {
+		message := fmt.Sprintf("game %q does not have a code to send events for", gameID)
+		h.badRequest(w, message)
+		return
+	}
+	events, unsubscribe := h.events.subscribe(code)
+	defer unsubscribe()
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{}) // the server write timeout does not apply to streams
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	rc.Flush()
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			io.WriteString(w, ": keep-alive\n\n")
+		case e := <-events:
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes the event in the server-sent event format.
+func writeEvent(w io.Writer, e gameEvent) error {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		return fmt.Errorf("encoding %v event: %v", e.Type, err)
+	}
+	if _, err := fmt.Fprintf(w, "event: %v\ndata: %s\n\n", e.Type, data); err != nil {
+		return fmt.Errorf("writing %v event: %v", e.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGameChannels(t *testing.T) {
+	var gc gameChannels
+	a, unsubscribeA := gc.subscribe("K7QF")
+	b, unsubscribeB := gc.subscribe("K7QF")
+	other, unsubscribeOther := gc.subscribe("AB2C")
+	defer unsubscribeOther()
+	want := gameEvent{"end", endEvent{GameID: "75-x"}}
+	gc.publish("K7QF", want)
+	for i, events := range []<-chan gameEvent{a, b} {
+		select {
+		case got := <-events:
+			if !reflect.DeepEqual(want, got) {
+				t.Errorf("subscriber %v: events not equal:\nwanted: %v\ngot:    %v", i, want, got)
+			}
+		default:
+			t.Errorf("subscriber %v: wanted event", i)
+		}
+	}
+	select {
+	case e := <-other:
+		t.Errorf("wanted subscriber of other game to not receive event, got %v", e)
+	default:
+	}
+	unsubscribeA()
+	unsubscribeB()
+	if _, ok := gc.subscribers["K7QF"]; ok {
+		t.Errorf("wanted game to be removed when it has no subscribers")
+	}
+}
+
+func TestGameChannelsPublishFull(t *testing.T) {
+	var gc gameChannels
+	events, unsubscribe := gc.subscribe("K7QF")
+	defer unsubscribe()
+	for i := 0; i <= eventBufferSize; i++ {
+		gc.publish("K7QF", gameEvent{Type: "draw"}) // should not block
+	}
+	if want, got := eventBufferSize, len(events); want != got {
+		t.Errorf("wanted %v buffered events, got %v", want, got)
+	}
+}
+
+func TestWriteEvent(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		var w bytes.Buffer
+		e := gameEvent{"claim", claimEvent{BoardID: "board-id", Type: "HasLine", Bingo: true}}
+		want := "event: claim\ndata: {\"boardID\":\"board-id\",\"type\":\"HasLine\",\"bingo\":true}\n\n"
+		err := writeEvent(&w, e)
+		switch {
+		case err != nil:
+			t.Errorf("unwanted error: %v", err)
+		case want != w.String():
+			t.Errorf("events not equal:\nwanted: %q\ngot:    %q", want, w.String())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name string
+			w    errWriter
+			gameEvent
+		}{
+			{"unencodable data", errWriter{}, gameEvent{"draw", make(chan int)}},
+			{"write error", errWriter{errors.New("mock write error")}, gameEvent{"draw", drawEvent{}}},
+		}
+		for i, test := range tests {
+			if err := writeEvent(test.w, test.gameEvent); err == nil {
+				t.Errorf("test %v (%v): wanted error", i, test.name)
+			}
+		}
+	})
+}
+
+func TestHandlerGameEvents(t *testing.T) {
+	store := &mockGameStore{
+		games: map[string]string{
+			"K7QF": "v2-74-" + board1257894001IDNumbers,
+		},
+	}
+	h := &handler{
+		games: store,
+	}
+	s := httptest.NewServer(h)
+	defer s.Close()
+	t.Run("not a code", func(t *testing.T) {
+		res, err := http.Get(s.URL + urlPathGameEvents + "?" + qpGameID + "=v2-5-" + board1257894001IDNumbers)
+		if err != nil {
+			t.Fatalf("requesting events: %v", err)
+		}
+		res.Body.Close()
+		if want, got := 400, res.StatusCode; want != got {
+			t.Errorf("status codes not equal: wanted %v, got %v", want, got)
+		}
+	})
+	res, err := http.Get(s.URL + urlPathGameEvents + "?" + qpGameID + "=K7QF")
+	if err != nil {
+		t.Fatalf("requesting events: %v", err)
+	}
+	defer res.Body.Close()
+	if want, got := "text/event-stream", res.Header.Get(headerContentType); want != got {
+		t.Fatalf("content types not equal: wanted %q, got %q", want, got)
+	}
+	form := url.Values{qpGameID: {"K7QF"}}
+	if _, err := http.PostForm(s.URL+urlPathGameDrawNumber, form); err != nil {
+		t.Fatalf("drawing number: %v", err)
+	}
+	check := url.Values{qpGameID: {"K7QF"}, qpBoardID: {board1257894001ID}, qpType: {typeHasLine}}
+	if _, err := http.Get(s.URL + urlPathGameCheckBoard + "?" + check.Encode()); err != nil {
+		t.Fatalf("checking board: %v", err)
+	}
+	undo := url.Values{qpGameID: {"K7QF"}, "reason": {"called too soon"}}
+	if _, err := http.PostForm(s.URL+urlPathGameUndoDraw, undo); err != nil {
+		t.Fatalf("undoing draw: %v", err)
+	}
+	want := []string{
+		"event: draw",
+		`data: {"gameID":"v2-75-` + board1257894001IDNumbers + `","number":75,"label":"O 75","column":4,"numbersLeft":0}`,
+		"",
+		"event: end",
+		`data: {"gameID":"v2-75-` + board1257894001IDNumbers + `"}`,
+		"",
+		"event: claim",
+		`data: {"boardID":"` + board1257894001ID + `","type":"HasLine","bingo":true}`,
+		"",
+		"event: undo",
+		`data: {"gameID":"v2-74-` + board1257894001IDNumbers + `","number":75,"label":"O 75","numbersLeft":1,"reason":"called too soon"}`,
+		"",
+	}
+	lines := make(chan string)
+	go func() {
+		sc := bufio.NewScanner(res.Body)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+		close(lines)
+	}()
+	var got []string
+	timeout := time.After(5 * time.Second)
+	for len(got) < len(want) {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatalf("stream closed early, got %q", got)
+			}
+			got = append(got, line)
+		case <-timeout:
+			t.Fatalf("timed out waiting for events, got %q", got)
+		}
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("events not equal:\nwanted: %q\ngot:    %q", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
@@ -0,0 +1,137 @@
+// Package gamestore saves the states of games in a file by short codes.
+package gamestore
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// File stores the ids of games in a JSON file, keyed by game codes.
+// The file is rewritten after every change so games are kept when the server restarts.
+type File struct {
+	name  string
+	mu    sync.Mutex
+	games map[string]string
+}
+
+const (
+	// codeLength is the number of characters in a game code.
+	codeLength = 4
+	// codeAlphabet is the characters used in game codes.
+	// Characters that look alike, such as 0 and O or 1 and I, are excluded.
+	codeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+	// maxCodeAttempts is the number of random codes tried before giving up on finding an unused one.
+	maxCodeAttempts = 100
+)
+
+// Open loads the games in the file with the name.  The file is created when the first game is saved if it does not exist.
+func Open(name string) (*File, error) {
+	f := File{
+		name:  name,
+		games: make(map[string]string),
+	}
+	data, err := os.ReadFile(name)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return &f, nil
+	case err != nil:
+		return nil, fmt.Errorf("reading game store file: %v", err)
+	}
+	if err := json.Unmarshal(data, &f.games); err != nil {
+		return nil, fmt.Errorf("parsing game store file: %v", err)
+	}
+	return &f, nil
+}
+
+// Create saves the game id under a new, unused code.
+func (f *File) Create(gameID string) (code string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := 0; i < maxCodeAttempts; i++ {
+		code, err := newCode()
+		if err != nil {
+			return "", err
+		}
+		if _, ok := f.games[code]; ok {
+			continue
+		}
+		f.games[code] = gameID
+		if err := f.save(); err != nil {
+			delete(f.games, code)
+			return "", err
+		}
+		return code, nil
+	}
+	return "", errors.New("no unused game codes found")
+}
+
+// Get finds the id of the game saved with the code.  Codes are not case sensitive.
+func (f *File) Get(code string) (gameID string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	gameID, ok := f.games[strings.ToUpper(code)]
+	if !ok {
+		return "", fmt.Errorf("no game with code %q", code)
+	}
+	return gameID, nil
+}
+
+// Update changes the id of the game saved with the code.
+func (f *File) Update(code, gameID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	code = strings.ToUpper(code)
+	prevID, ok := f.games[code]
+	if !ok {
+		return fmt.Errorf("no game with code %q", code)
+	}
+	f.games[code] = gameID
+	if err := f.save(); err != nil {
+		f.games[code] = prevID
+		return err
+	}
+	return nil
+}
+
+// save writes the games to the file.  A temporary file is renamed over the file so it is never partially written.
+func (f *File) save() error {
+	data, err := json.MarshalIndent(f.games, "", "\t")
+	if err != nil {
+		return fmt.Errorf("encoding games: %v", err)
+	}
+	dir, base := filepath.Split(f.name)
+	tmp, err := os.CreateTemp(dir, base+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temporary game store file: %v", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op after rename
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing temporary game store file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing temporary game store file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), f.name); err != nil {
+		return fmt.Errorf("replacing game store file: %v", err)
+	}
+	return nil
+}
+
+// newCode creates a random game code.
+func newCode() (string, error) {
+	b := make([]byte, codeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("creating random game code: %v", err)
+	}
+	for i := range b {
+		b[i] = codeAlphabet[int(b[i])%len(codeAlphabet)]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,93 @@
+package gamestore
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "games.json")
+	f, err := Open(name)
+	if err != nil {
+		t.Fatalf("opening new store: %v", err)
+	}
+	code, err := f.Create("0")
+	if err != nil {
+		t.Fatalf("creating game: %v", err)
+	}
+	if !regexp.MustCompile("^[2-9A-HJ-NP-Z]{4}$").MatchString(code) {
+		t.Errorf("wanted short code without ambiguous characters, got %q", code)
+	}
+	if err := f.Update(code, "1-game"); err != nil {
+		t.Fatalf("updating game: %v", err)
+	}
+	f2, err := Open(name)
+	if err != nil {
+		t.Fatalf("reopening store: %v", err)
+	}
+	tests := []struct {
+		name string
+		code string
+	}{
+		{"same code", code},
+		{"lowercase code", string([]byte{code[0] | 0x20, code[1] | 0x20, code[2] | 0x20, code[3] | 0x20})},
+	}
+	for i, test := range tests {
+		gameID, err := f2.Get(test.code)
+		switch {
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+		case gameID != "1-game":
+			t.Errorf("test %v (%v): wanted updated game id to be loaded, got %q", i, test.name, gameID)
+		}
+	}
+}
+
+func TestFileUnknownCode(t *testing.T) {
+	f, err := Open(filepath.Join(t.TempDir(), "games.json"))
+	if err != nil {
+		t.Fatalf("opening new store: %v", err)
+	}
+	if _, err := f.Get("K7QF"); err == nil {
+		t.Errorf("wanted error getting unknown code")
+	}
+	if err := f.Update("K7QF", "0"); err == nil {
+		t.Errorf("wanted error updating unknown code")
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	dir := t.TempDir()
+	badJSON := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(badJSON, []byte("[not a map"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		fileName string
+	}{
+		{"bad json", badJSON},
+		{"directory", dir},
+	}
+	for i, test := range tests {
+		if _, err := Open(test.fileName); err == nil {
+			t.Errorf("test %v (%v): wanted error", i, test.name)
+		}
+	}
+}
+
+func TestFileCreateSaveError(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "missing-dir", "games.json")
+	f, err := Open(name)
+	if err != nil {
+		t.Fatalf("opening new store: %v", err)
+	}
+	if _, err := f.Create("0"); err == nil {
+		t.Errorf("wanted error saving game when folder of file does not exist")
+	}
+	if len(f.games) != 0 {
+		t.Errorf("wanted game to not be kept after save error: %v", f.games)
+	}
+}
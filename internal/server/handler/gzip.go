@@ -25,8 +25,12 @@ func WithGzip(h http.Handler) http.Handler {
 }
 
 // ServeHTTP writes all output with gzip encoding if the request allows it.
+// Event streams are not compressed so each event is sent when it is flushed.  WebSocket upgrades are also not compressed so the connection can be hijacked.
 func (h *gzipHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+	switch {
+	case !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip"),
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream"),
+		strings.EqualFold(r.Header.Get("Upgrade"), "websocket"):
 		h.Handler.ServeHTTP(w, r)
 		return
 	}
@@ -16,6 +16,8 @@ func TestWithGzip(t *testing.T) {
 		w := httptest.NewRecorder()
 		r := httptest.NewRequest(methodGet, "/", nil)
 		r.Header.Add("Accept-Encoding", test.acceptEncoding)
+		r.Header.Add("Accept", test.accept)
+		r.Header.Add("Upgrade", test.upgrade)
 		WithGzip(h).ServeHTTP(w, r)
 		contentEncoding := w.Header().Get("Content-Encoding")
 		gotGzip := contentEncoding == "gzip"
@@ -34,6 +36,8 @@ const gzipTestWriteBody = "abc123"
 var withGzipTests = []struct {
 	name           string
 	acceptEncoding string
+	accept         string
+	upgrade        string
 	wantGzip       bool
 	wantBodyStart  string
 	wantBody       string
@@ -48,4 +52,16 @@ var withGzipTests = []struct {
 		wantGzip:       true,
 		wantBodyStart:  "\x1f\x8b\x08", // magic number (1f8b) and compression method for deflate (08)
 	},
+	{
+		name:           "event stream",
+		acceptEncoding: "gzip, deflate, br",
+		accept:         "text/event-stream",
+		wantBodyStart:  gzipTestWriteBody,
+	},
+	{
+		name:           "websocket upgrade",
+		acceptEncoding: "gzip, deflate, br",
+		upgrade:        "websocket",
+		wantBodyStart:  gzipTestWriteBody,
+	},
 }
@@ -5,31 +5,85 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"image"
 	"image/png"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/jacobpatterson1549/bitty-bingo/bingo"
 )
 
 type (
+	// GameStore saves the states of games by short codes so they are kept when the server restarts.
+	GameStore interface {
+		// Create saves the game id under a new code.
+		Create(gameID string) (code string, err error)
+		// Get finds the id of the game saved with the code.
+		Get(code string) (gameID string, err error)
+		// Update changes the id of the game saved with the code.
+		Update(code, gameID string) error
+	}
 	// Barcoder generates image of a bar code of the board, possibly with an external library.
 	Barcoder interface {
 		// Barcode encodes the board id to a bar code image with a width and height.
 		// Barcode.Image is not called directly to avoid test dependencies on external libraries
 		Barcode(format string, boardID string, width, height int) (image.Image, error)
 	}
+	// Config is used to create the handler.
+	Config struct {
+		// GameCount is the number of recent games that are listed.
+		GameCount int
+		// Time is used to display when games are modified.
+		Time func() string
+		// Barcoder creates the bar codes of boards.
+		Barcoder Barcoder
+		// Games can be nil to not save games.
+		Games GameStore
+		// Dealer can be nil to shuffle with a dealer that is seeded to the time the handler is created.
+		Dealer *bingo.Dealer
+		// Boards can be nil to only keep boards unique within each batch.
+		Boards bingo.BoardRegistry
+		// GameSigningKey can be empty to not sign game ids.  When it is set, ids of games that are not signed with it are rejected.
+		GameSigningKey []byte
+		// BoardSigningKey can be empty to not add authenticity codes to boards.  When it is set, checked boards without valid codes are flagged as counterfeit.
+		BoardSigningKey []byte
+	}
 	// handler tracks servers HTTP requests and stores recent game infos.
 	// The time function is used to create game infos
+	// The game store is optional, when it is provided, new games are saved with short codes.
+	// The dealer shuffles new games and boards.
+	// The board registry is optional, when it is provided, batches of boards are not repeated.
+	// The signer signs the ids of games that are sent to clients, when it has a key.
+	// The board signer adds authenticity codes to the ids of new boards, when it has a key.
+	// The game locks keep stored games from being changed by concurrent requests.
 	handler struct {
 		http.Handler
 		Barcoder
-		gameInfos []gameInfo
-		time      func() string
-		favicon   string
+		dealer      *bingo.Dealer
+		games       GameStore
+		boards      bingo.BoardRegistry
+		signer      gameSigner
+		boardSigner boardSigner
+		events      *gameChannels
+		gameLocks   *gameLocks
+		gameInfos   *gameHistory
+		time        func() string
+		favicon     string
+	}
+	// errorWriter writes problems to responses in a format, such as plain text or JSON.
+	errorWriter interface {
+		// badRequest tells the response that a bad request was made.
+		badRequest(w http.ResponseWriter, message string)
+		// internalServerError tells the response that an unexpected error occurred.
+		internalServerError(w http.ResponseWriter, err error)
 	}
 	// gameInfo is the display value of the sate of a game at a specific time.
 	gameInfo struct {
@@ -39,28 +93,51 @@ type (
 		ModTime string
 		// NumbersLeft is the amount of Numbers that can still be drawn in the game.
 		NumbersLeft int
+		// Undone is the label of the number that was put back when the last draw of the game was undone, if the game was modified by an undo.
+		Undone string
+		// Reason is why the last draw was undone.
+		Reason string
 	}
 )
 
-// New creates a HTTP handler to serve the site.
+// New creates a HTTP handler to serve the site from the config.
 // The gameCount and time function are validated used from the config in the handler.
 // Responses are returned gzip compression when allowed.
-func New(gameCount int, time func() string, barcoder Barcoder) http.Handler {
+func New(cfg Config) http.Handler {
 	var faviconW bytes.Buffer
 	executeFaviconTemplate(&faviconW)
 	faviconB := faviconW.Bytes()
 	favicon := base64.StdEncoding.EncodeToString([]byte(faviconB))
 	h := handler{
-		gameInfos: make([]gameInfo, 0, gameCount),
-		time:      time,
-		Barcoder:  barcoder,
-		favicon:   favicon,
+		gameInfos:   newGameHistory(cfg.GameCount),
+		time:        cfg.Time,
+		Barcoder:    cfg.Barcoder,
+		dealer:      cfg.Dealer,
+		games:       cfg.Games,
+		boards:      cfg.Boards,
+		signer:      cfg.GameSigningKey,
+		boardSigner: cfg.BoardSigningKey,
+		events:      new(gameChannels),
+		gameLocks:   new(gameLocks),
+		favicon:     favicon,
 	}
 	return &h
 }
 
 // ServeHTTP serves requests for GET and POST methods, not allowing others.
 func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.events == nil {
+		h.events = new(gameChannels)
+	}
+	if h.gameInfos == nil {
+		h.gameInfos = new(gameHistory)
+	}
+	if h.gameLocks == nil {
+		h.gameLocks = new(gameLocks)
+	}
+	if h.dealer == nil {
+		h.dealer = bingo.NewDealer(time.Now().UnixNano())
+	}
 	if h.Handler == nil {
 		h.Handler = newMux(h)
 	}
@@ -74,43 +151,116 @@ func newMux(h *handler) *Mux {
 			"/":                 h.getGames,
 			"/game":             h.getGame,
 			"/game/board/check": h.checkBoard,
+			"/game/pattern":     h.setPattern,
+			"/game/events":      h.getGameEvents,
+			"/game/play":        h.playGame,
 			"/game/board":       h.getBoard,
+			"/game/strip":       h.getStrip,
+			"/game/verify":      h.getVerify,
 			"/help":             h.getHelp,
 			"/about":            h.getAbout,
+			// JSON api
+			"/api/v1/game":        h.apiGetGame,
+			"/api/v1/board":       h.apiGetBoard,
+			"/api/v1/board/check": h.apiCheckBoard,
 		},
 		"POST": {
-			"/game":             h.createGame,
-			"/game/draw_number": h.drawNumber,
-			"/game/board":       h.createBoard,
-			"/game/boards":      h.createBoards,
+			"/game":              h.createGame,
+			"/game/draw_number":  h.drawNumber,
+			"/game/undo_draw":    h.undoDraw,
+			"/game/end":          h.endGame,
+			"/game/board":        h.createBoard,
+			"/game/boards":       h.createBoards,
+			"/game/boards/check": h.checkBoards,
+			"/game/strip":        h.createStrip,
+			// JSON api
+			"/api/v1/game":             h.apiCreateGame,
+			"/api/v1/game/draw_number": h.apiDrawNumber,
+			"/api/v1/board":            h.apiCreateBoard,
+			"/api/v1/boards":           h.apiCreateBoards,
 		},
 	}
 }
 
 // getGames renders the games page onto the response with the game infos.
 func (h *handler) getGames(w http.ResponseWriter, r *http.Request) {
-	executeGamesTemplate(w, h.favicon, h.gameInfos)
+	executeGamesTemplate(w, h.favicon, h.gameInfos.list())
 }
 
 // getGame renders the game page onto the response with the game of the 'gameID' query parameter.
 // The 'boardID' and 'bingo' query parameters are also used to forward the results of a BINGO check.
+// When the 'type' query parameter of the check is also present, the checked board is shown with its winning cells.
+// The optional 'pattern' query parameter is the id of a custom pattern to check boards with.
 func (h handler) getGame(w http.ResponseWriter, r *http.Request) {
 	gameID := r.URL.Query().Get("gameID")
 	boardID := r.URL.Query().Get("boardID")
+	checkType := r.URL.Query().Get("type")
+	patternID := r.URL.Query().Get("pattern")
 	hasBingo := r.URL.Query().Has("bingo")
 	g, ok := h.parseGame(gameID, w)
 	if !ok {
 		return
 	}
-	executeGameTemplate(w, h.favicon, *g, gameID, boardID, hasBingo)
+	var custom *bingo.Pattern
+	if len(patternID) != 0 {
+		if custom, ok = parsePattern(patternID, w, h); !ok {
+			return
+		}
+	}
+	check := gameCheck{
+		BoardID:  boardID,
+		HasBingo: hasBingo,
+	}
+	switch {
+	case len(boardID) == 0 || len(checkType) == 0:
+	case g.Rules() != nil:
+		unsignedID, _, err := h.boardSigner.verify(boardID)
+		c, ok := parseCard(unsignedID, w, h)
+		if !ok {
+			return
+		}
+		if check.HasBingo, ok = checkCard(*c, *g, checkType, w, h); !ok {
+			return
+		}
+		check.Counterfeit = err != nil
+	default:
+		unsignedID, _, err := h.boardSigner.verify(boardID)
+		b, ok := parseBoard(unsignedID, w, h)
+		if !ok || !checkClassicGame(*g, w, h) {
+			return
+		}
+		p, ok := checkPattern(checkType, patternID, w, h)
+		if !ok {
+			return
+		}
+		result := b.Check(*g, *p)
+		wins := b.Replay(*g, *p)
+		check.HasBingo = result.Bingo
+		check.Counterfeit = err != nil
+		check.Board = newCheckedBoardPage(*b, unsignedID, result)
+		check.Win = &wins[0]
+	}
+	gameCode, _ := h.gameCode(gameID)
+	executeGameTemplate(w, h.favicon, *g, gameID, gameCode, check, custom, patternID)
 }
 
 // createGame renders an empty game.
+// The optional 'balls' form parameter is the ball count of the game, 75 by default.
+// The optional 'rules' form parameter creates a game for cards with other rules instead, such as 4x4n25 for 4x4 cards with numbers 1-100.
+// The optional 'seed' form parameter creates a 75-ball game with a draw order that can be reproduced from the seed.
+// The optional 'commit' form parameter securely shuffles the game and shows a commitment to the order of its numbers.
+// When games are stored, the game is saved and its code is used instead of its id.
 func (h handler) createGame(w http.ResponseWriter, r *http.Request) {
-	var g bingo.Game
-	gameID, err := g.ID()
+	g, ok := parseNewGame(r.FormValue("balls"), r.FormValue("rules"), r.FormValue("seed"), w, h)
+	if !ok {
+		return
+	}
+	commit := len(r.FormValue("commit")) != 0
+	if !commitNewGame(g, commit, h.games != nil, w, h) {
+		return
+	}
+	gameID, err := h.newGameID(*g)
 	if err != nil {
-		err := fmt.Errorf("getting new game id: %v=\ngame: %#v", err, g)
 		h.internalServerError(w, err)
 		return
 	}
@@ -118,35 +268,172 @@ func (h handler) createGame(w http.ResponseWriter, r *http.Request) {
 }
 
 // getBoard renders the board page (by 'boardID') onto the response or create a new board and redirects to it.
+// The authenticity code at the end of the board id is shown on the board and put in the barcode if it is valid.
 // The 'barcodeFormat' query parameter specifies the type of barcode to create in the center cell.
+// The optional 'freeSpace' query parameter is text to show in the free space instead of the barcode.
+// The optional 'seed' query parameter is shown on the board if the board was created from it.
+// The optional 'gameID' query parameter is used to daub the drawn numbers of the game and show when the board first had each pattern in it.
 func (h handler) getBoard(w http.ResponseWriter, r *http.Request) {
-	boardID := r.URL.Query().Get("boardID")
+	boardID, code, _ := h.boardSigner.verify(r.URL.Query().Get("boardID"))
 	barcodeFormat := r.URL.Query().Get("barcodeFormat")
-	b, ok := h.parseBoard(boardID, w)
+	freeSpace := r.URL.Query().Get("freeSpace")
+	seedParam := r.URL.Query().Get("seed")
+	gameID := r.URL.Query().Get("gameID")
+	b, ok := parseBoard(boardID, w, h)
 	if !ok {
 		return
 	}
-	barcode, err := h.boardBarcode(boardID, barcodeFormat)
+	if !parseFreeSpace(freeSpace, w, h) {
+		return
+	}
+	seed, ok := parseSeed(seedParam, w, h)
+	if !ok {
+		return
+	}
+	if seed != nil && *bingo.NewBoardFromSeed(*seed) != *b {
+		h.badRequest(w, "board was not created from the seed")
+		return
+	}
+	var g *bingo.Game
+	var wins []bingo.Win
+	if len(gameID) != 0 {
+		g, ok = h.parseGame(gameID, w)
+		if !ok || !checkClassicGame(*g, w, h) {
+			return
+		}
+		wins = b.Replay(*g, bingo.Patterns()...)
+	}
+	barcode, err := h.boardBarcode(signedBoardID(boardID, code), barcodeFormat)
 	if err != nil {
 		err := fmt.Errorf("creating board bar code: %v", err)
 		h.internalServerError(w, err)
 		return
 	}
-	executeBoardTemplate(w, h.favicon, *b, boardID, barcode)
+	gameCode, _ := h.gameCode(gameID)
+	p := boardPage{
+		Board:     *b,
+		BoardID:   boardID,
+		Code:      code,
+		Barcode:   barcode,
+		FreeSpace: freeSpace,
+		Seed:      seedParam,
+		GameID:    gameID,
+		GameCode:  gameCode,
+		Game:      g,
+		Wins:      wins,
+	}
+	executeBoardTemplate(w, h.favicon, p)
 }
 
 // createBoard redirects to a new board.
 // The 'barcodeFormat' form parameter specifies the type of barcode to create in the center cell.
+// The optional 'freeSpace' form parameter is text to show in the free space instead of the barcode.
+// The board has a number in the center cell instead of the free space if the 'noFreeCell' form parameter is set.
+// The optional 'seed' form parameter creates a board that can be reproduced from the seed.
+// The optional 'issue' form parameter is the batch or event number that is signed in the authenticity code of the board, if boards are signed.
 func (h handler) createBoard(w http.ResponseWriter, r *http.Request) {
-	b := bingo.NewBoard()
-	boardID, err := b.ID()
+	freeSpace := r.FormValue("freeSpace")
+	if !parseFreeSpace(freeSpace, w, h) {
+		return
+	}
+	noFreeCell := len(r.FormValue("noFreeCell")) != 0
+	seed, ok := parseNewBoardSeed(r.FormValue("seed"), noFreeCell, w, h)
+	if !ok {
+		return
+	}
+	issue, ok := parseIssue(r.FormValue("issue"), w, h)
+	if !ok {
+		return
+	}
+	_, boardID, err := h.newBoard(noFreeCell, seed)
 	if err != nil {
-		err := fmt.Errorf("getting new board id: %v\nboard: %#v", err, b)
 		h.internalServerError(w, err)
 		return
 	}
 	barcodeFormat := r.FormValue("barcodeFormat")
-	h.redirect(w, r, "/game/board?boardID="+boardID+"&barcodeFormat="+barcodeFormat)
+	target := "/game/board?boardID=" + h.boardSigner.sign(boardID, issue) + "&barcodeFormat=" + barcodeFormat
+	if len(freeSpace) != 0 {
+		target += "&freeSpace=" + url.QueryEscape(freeSpace)
+	}
+	if seed != nil {
+		target += "&seed=" + strconv.FormatUint(*seed, 10)
+	}
+	h.redirect(w, r, target)
+}
+
+// getStrip renders the strip of 90-ball tickets (by 'stripID') as an svg image.
+func (h handler) getStrip(w http.ResponseWriter, r *http.Request) {
+	stripID := r.URL.Query().Get("stripID")
+	s, err := bingo.StripFromID(stripID)
+	if err != nil {
+		message := fmt.Sprintf("getting strip from query parameter: %v", err)
+		h.badRequest(w, message)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	if err := executeStripExportTemplate(w, *s); err != nil {
+		err = fmt.Errorf("rendering strip: %v", err)
+		h.internalServerError(w, err)
+	}
+}
+
+// createStrip redirects to a new strip of six 90-ball tickets.
+func (h handler) createStrip(w http.ResponseWriter, r *http.Request) {
+	s := h.dealer.NewStrip()
+	stripID, err := s.ID()
+	if err != nil {
+		err = fmt.Errorf("getting strip id: %v\nstrip: %#v", err, s)
+		h.internalServerError(w, err)
+		return
+	}
+	h.redirect(w, r, "/game/strip?stripID="+stripID)
+}
+
+// getVerify renders the page to verify the commitment of a game with its revealed salt and order of numbers.
+// The 'gameID' query parameter reveals the salt and order of a committed game, which is only done when the game is over and all numbers are drawn.
+// Otherwise, the 'commitment', 'salt', 'order', and optional 'drawn' query parameters are verified when the commitment is set.
+// The numbers of the order and drawn numbers are separated by spaces or commas.
+func (h handler) getVerify(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	check := commitmentCheck{
+		Commitment: q.Get("commitment"),
+		Salt:       q.Get("salt"),
+		Order:      q.Get("order"),
+		Drawn:      q.Get("drawn"),
+	}
+	if gameID := q.Get("gameID"); len(gameID) != 0 {
+		g, ok := h.parseGame(gameID, w)
+		if !ok {
+			return
+		}
+		switch {
+		case !g.Committed():
+			h.badRequest(w, "game is not committed")
+			return
+		case g.NumbersLeft() != 0:
+			h.badRequest(w, "the order of the numbers of committed games is only revealed when the game is over")
+			return
+		}
+		check.Commitment = g.Commitment()
+		check.Salt = g.Salt()
+		check.Order = formatNumbers(g.Order())
+		check.Drawn = formatNumbers(g.DrawnNumbers())
+	}
+	if len(check.Commitment) != 0 {
+		order, ok := parseNumbers("order", check.Order, w, h)
+		if !ok {
+			return
+		}
+		drawn, ok := parseNumbers("drawn", check.Drawn, w, h)
+		if !ok {
+			return
+		}
+		if err := bingo.VerifyCommitment(check.Commitment, check.Salt, order, drawn); err != nil {
+			check.Problem = err.Error()
+		}
+		check.Checked = true
+	}
+	executeVerifyTemplate(w, h.favicon, check)
 }
 
 // getHelp renders the help page onto the response.
@@ -160,7 +447,11 @@ func (h handler) getAbout(w http.ResponseWriter, r *http.Request) {
 }
 
 // checkBoard checks the board on the game with a checkType using the 'gameID', 'boardID', and 'type' query parameters.
+// The type is the name of a pattern in the bingo pattern library or Custom to use the pattern from the 'pattern' query parameter.
+// The board is a card with the rules of the game if the game has rules, which can only be checked for HasLine or IsFilled.
 // The results of the check are included as query parameters onto a redirect to the game page.
+// The check is sent to subscribers of the game if it is stored.
+// The board is flagged as counterfeit if boards are signed and the id does not end with a valid authenticity code.
 func (h handler) checkBoard(w http.ResponseWriter, r *http.Request) {
 	gameID := r.URL.Query().Get("gameID")
 	g, ok := h.parseGame(gameID, w)
@@ -168,91 +459,310 @@ func (h handler) checkBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	boardID := r.URL.Query().Get("boardID")
-	b, ok := h.parseBoard(boardID, w)
-	if !ok {
+	unsignedID, _, err := h.boardSigner.verify(boardID)
+	checkType := r.URL.Query().Get("type")
+	patternID := r.URL.Query().Get("pattern")
+	if !parsePatternID(patternID, w, h) {
 		return
 	}
-	checkType := r.URL.Query().Get("type")
 	var result bool
-	switch checkType {
-	case "HasLine":
-		result = b.HasLine(*g)
-	case "IsFilled":
-		result = b.IsFilled(*g)
-	default:
-		message := fmt.Sprintf("unknown checkType %q", checkType)
-		h.badRequest(w, message)
-		return
+	if g.Rules() != nil {
+		c, ok := parseCard(unsignedID, w, h)
+		if !ok {
+			return
+		}
+		if result, ok = checkCard(*c, *g, checkType, w, h); !ok {
+			return
+		}
+	} else {
+		b, ok := parseBoard(unsignedID, w, h)
+		if !ok || !checkClassicGame(*g, w, h) {
+			return
+		}
+		p, ok := checkPattern(checkType, patternID, w, h)
+		if !ok {
+			return
+		}
+		result = b.Matches(*g, *p)
 	}
-	url := fmt.Sprintf("/game?gameID=%v&boardID=%v", gameID, boardID)
+	h.publishClaim(gameID, boardID, checkType, result, err != nil)
+	escapedBoardID := url.QueryEscape(boardID) // readable ids can be typed with spaces
+	url := fmt.Sprintf("/game?gameID=%v&boardID=%v&type=%v", gameID, escapedBoardID, checkType)
+	url += patternQuery(patternID)
 	if result {
 		url += "&bingo"
 	}
 	h.redirect(w, r, url)
 }
 
+// setPattern creates a custom pattern from the 'cell' query parameters and redirects to the game from the 'gameID' query parameter with it.
+// The cells are indexes on a board.
+func (h handler) setPattern(w http.ResponseWriter, r *http.Request) {
+	gameID := r.URL.Query().Get("gameID")
+	if _, ok := h.parseGame(gameID, w); !ok {
+		return
+	}
+	cells := r.URL.Query()["cell"]
+	indexes := make([]int, len(cells))
+	for i, c := range cells {
+		index, err := strconv.Atoi(c)
+		if err != nil {
+			message := fmt.Sprintf("parsing pattern cell: %v", err)
+			h.badRequest(w, message)
+			return
+		}
+		indexes[i] = index
+	}
+	p, err := bingo.CustomPattern(indexes...)
+	if err != nil {
+		message := fmt.Sprintf("creating pattern: %v", err)
+		h.badRequest(w, message)
+		return
+	}
+	patternID, err := p.ID()
+	if err != nil {
+		err := fmt.Errorf("getting id of new pattern: %v\npattern: %#v", err, p)
+		h.internalServerError(w, err)
+		return
+	}
+	h.redirect(w, r, "/game?gameID="+gameID+patternQuery(patternID))
+}
+
 // drawNumber draws a new number for the game specified by the request's 'gameID' form parameter.
-// The response is redirected to the updated game.  It's updated state is stored in the game infos slice.
+// The response is redirected to the updated game, keeping the 'pattern' form parameter.  It's updated state is stored in the game infos slice.
 func (h *handler) drawNumber(w http.ResponseWriter, r *http.Request) {
 	gameID := r.FormValue("gameID")
+	patternID := r.FormValue("pattern")
+	if !parsePatternID(patternID, w, h) {
+		return
+	}
+	defer h.lockGame(gameID)()
 	g, ok := h.parseGame(gameID, w)
 	if !ok {
 		return
 	}
-	beforeNumsLeft := g.NumbersLeft()
-	g.DrawNumber()
-	afterNumsLeft := g.NumbersLeft()
-	if beforeNumsLeft == afterNumsLeft {
+	afterID, drawn, err := h.drawGameNumber(g, gameID)
+	switch {
+	case err != nil:
+		h.internalServerError(w, err)
+		return
+	case !drawn:
 		w.WriteHeader(http.StatusNotModified)
 		return
 	}
-	afterID, err := g.ID()
+	if code, ok := h.gameCode(gameID); ok {
+		afterID = code
+	}
+	h.redirect(w, r, "/game?gameID="+afterID+patternQuery(patternID))
+}
+
+// drawGameNumber draws a new number in the game, storing the updated state in the game infos.
+// If the gameID is a code of a stored game, the stored game is also updated and the number is sent to its subscribers.
+// The signed id of the game after the number is drawn is returned.  The game is not changed if all numbers have been drawn.
+func (h *handler) drawGameNumber(g *bingo.Game, gameID string) (afterID string, drawn bool, err error) {
+	beforeNumsLeft := g.NumbersLeft()
+	h.dealer.DrawNumber(g)
+	if beforeNumsLeft == g.NumbersLeft() {
+		return gameID, false, nil
+	}
+	afterID, err = h.saveDraws(g, gameID)
 	if err != nil {
-		err := fmt.Errorf("getting id after drawing number from game with a VALID id %q: %v", gameID, err)
+		return "", false, err
+	}
+	return afterID, true, nil
+}
+
+// saveDraws stores the updated state of the game in the game infos after numbers are drawn.
+// If the gameID is a code of a stored game, the stored game is also updated and the last number is sent to its subscribers.
+// The signed id of the game is returned.
+func (h *handler) saveDraws(g *bingo.Game, gameID string) (afterID string, err error) {
+	afterID, err = g.ID()
+	if err != nil {
+		return "", fmt.Errorf("getting id after drawing number from game with a VALID id %q: %v", gameID, err)
+	}
+	signedID := h.signer.sign(afterID)
+	listedID := signedID
+	if code, ok := h.gameCode(gameID); ok {
+		if err := h.saveGame(code, *g); err != nil {
+			return "", err
+		}
+		h.publishDraw(code, *g, signedID)
+		if g.Committed() {
+			listedID = code // the ids of committed games do not have the order of their numbers
+		}
+	}
+	gi := gameInfo{
+		ID:          listedID,
+		NumbersLeft: g.NumbersLeft(),
+	}
+	h.addGame(gi)
+	return signedID, nil
+}
+
+// endGame draws the rest of the numbers of the committed game specified by the request's 'gameID' form parameter so the game is over.
+// The response is redirected to the page that reveals the salt and order of the game to verify its commitment.
+func (h *handler) endGame(w http.ResponseWriter, r *http.Request) {
+	gameID := r.FormValue("gameID")
+	defer h.lockGame(gameID)()
+	g, ok := h.parseGame(gameID, w)
+	if !ok {
+		return
+	}
+	if !g.Committed() {
+		h.badRequest(w, "only committed games can be ended")
+		return
+	}
+	if g.NumbersLeft() != 0 {
+		for g.NumbersLeft() != 0 {
+			h.dealer.DrawNumber(g)
+		}
+		if _, err := h.saveDraws(g, gameID); err != nil {
+			h.internalServerError(w, err)
+			return
+		}
+	}
+	h.redirect(w, r, "/game/verify?gameID="+gameID)
+}
+
+// maxUndoReasonLength is the most characters that the reason for undoing a draw can have, so it fits in the games list.
+const maxUndoReasonLength = 100
+
+// undoDraw moves the last drawn number of the game specified by the request's 'gameID' form parameter back to the numbers that can be drawn.
+// The 'reason' form parameter is required so the correction can be explained to players; it is shown with the undone number in the game infos.
+// The response is redirected to the updated game, keeping the 'pattern' form parameter.
+func (h *handler) undoDraw(w http.ResponseWriter, r *http.Request) {
+	gameID := r.FormValue("gameID")
+	patternID := r.FormValue("pattern")
+	reason := strings.TrimSpace(r.FormValue("reason"))
+	switch {
+	case len(reason) == 0:
+		h.badRequest(w, "missing reason for undoing draw")
+		return
+	case utf8.RuneCountInString(reason) > maxUndoReasonLength:
+		message := fmt.Sprintf("reason for undoing draw must be at most %v characters long", maxUndoReasonLength)
+		h.badRequest(w, message)
+		return
+	}
+	if !parsePatternID(patternID, w, h) {
+		return
+	}
+	defer h.lockGame(gameID)()
+	g, ok := h.parseGame(gameID, w)
+	if !ok {
+		return
+	}
+	if g.Committed() && g.NumbersLeft() == 0 {
+		h.badRequest(w, "draws cannot be undone after the order of a committed game is revealed")
+		return
+	}
+	afterID, undone, err := h.undoGameDraw(g, gameID, reason)
+	switch {
+	case err != nil:
 		h.internalServerError(w, err)
 		return
+	case !undone:
+		w.WriteHeader(http.StatusNotModified)
+		return
 	}
-	h.addGame(afterID, afterNumsLeft)
-	h.redirect(w, r, "/game?gameID="+afterID)
+	if code, ok := h.gameCode(gameID); ok {
+		afterID = code
+	}
+	h.redirect(w, r, "/game?gameID="+afterID+patternQuery(patternID))
 }
 
-// addGame creates a new gameInfo and adds it to the gameInfos stack.  If the stack is full, the last item is discarded.
-func (h *handler) addGame(gameID string, numbersLeft int) {
-	if cap(h.gameInfos) < 1 && len(h.gameInfos) == 0 {
-		h.gameInfos = make([]gameInfo, 1)
+// undoGameDraw puts the last drawn number of the game back, storing the updated state and the reason in the game infos.
+// If the gameID is a code of a stored game, the stored game is also updated and the undo is sent to its subscribers.
+// The signed id of the game after the draw is undone is returned.  The game is not changed if no numbers have been drawn.
+func (h *handler) undoGameDraw(g *bingo.Game, gameID, reason string) (afterID string, undone bool, err error) {
+	n := g.UndoDraw()
+	if n == 0 {
+		return gameID, false, nil
+	}
+	afterID, err = g.ID()
+	if err != nil {
+		return "", false, fmt.Errorf("getting id after undoing draw from game with a VALID id %q: %v", gameID, err)
 	}
-	if cap(h.gameInfos) > len(h.gameInfos) {
-		h.gameInfos = append(h.gameInfos, gameInfo{}) // increase length
+	signedID := h.signer.sign(afterID)
+	listedID := signedID
+	if code, ok := h.gameCode(gameID); ok {
+		if err := h.saveGame(code, *g); err != nil {
+			return "", false, err
+		}
+		h.publishUndo(code, *g, n, signedID, reason)
+		if g.Committed() {
+			listedID = code // the ids of committed games do not have the order of their numbers
+		}
 	}
-	copy(h.gameInfos[1:], h.gameInfos) // shift right, overwriting last
 	gi := gameInfo{
-		ID:          gameID,
-		NumbersLeft: numbersLeft,
+		ID:          listedID,
+		NumbersLeft: g.NumbersLeft(),
+		Undone:      g.Label(n),
+		Reason:      reason,
 	}
+	h.addGame(gi)
+	return signedID, true, nil
+}
+
+// saveGame updates the stored game with the code.
+// The secret id of the game is saved so the order of the numbers of committed games is kept on the server.
+func (h handler) saveGame(code string, g bingo.Game) error {
+	gameID, err := g.SecretID()
+	if err != nil {
+		return fmt.Errorf("getting secret id of game %q: %v", code, err)
+	}
+	if err := h.games.Update(code, gameID); err != nil {
+		return fmt.Errorf("saving game %q: %v", code, err)
+	}
+	return nil
+}
+
+// addGame adds the gameInfo to the top of the gameInfos stack, setting its modification time.  If the stack is full, the last item is discarded.
+func (h *handler) addGame(gi gameInfo) {
 	if h.time != nil {
 		gi.ModTime = h.time()
 	}
-	h.gameInfos[0] = gi // set first
+	h.gameInfos.add(gi)
 }
 
 // createBoards creates 'n' boards as specified by the request's form parameter, attaching the boards in a zip file.
 // The 'barcodeFormat' form parameter specifies the type of barcode to create in the center cell.
+// The optional 'freeSpace' and 'noFreeCell' form parameters change the center cell, as when creating a single board.
+// The optional 'seed' form parameter is the seed of the first board.  The seed of each other board is one more than the seed of the board before it.
+// The optional 'minDistance' form parameter is the fewest numbers that each board must have that are not on each other board.
 func (h handler) createBoards(w http.ResponseWriter, r *http.Request) {
-	nQueryParam := r.FormValue("n")
 	barcodeFormat := r.FormValue("barcodeFormat")
-	n, err := strconv.Atoi(nQueryParam)
-	if err != nil {
-		message := fmt.Sprintf("%v: example: /game/boards?n=5 creates 5 unique boards", err)
-		h.badRequest(w, message)
+	freeSpace := r.FormValue("freeSpace")
+	noFreeCell := len(r.FormValue("noFreeCell")) != 0
+	n, ok := parseBoardCount(r.FormValue("n"), w, h)
+	if !ok {
 		return
 	}
-	if n < 1 || n > 1000 {
-		message := "n must be be between 1 and 1000"
-		h.badRequest(w, message)
+	if !parseFreeSpace(freeSpace, w, h) {
+		return
+	}
+	seed, ok := parseNewBoardSeed(r.FormValue("seed"), noFreeCell, w, h)
+	if !ok {
+		return
+	}
+	minDistance, ok := parseMinDistance(r.FormValue("minDistance"), w, h)
+	if !ok {
+		return
+	}
+	issue, ok := parseIssue(r.FormValue("issue"), w, h)
+	if !ok {
+		return
+	}
+	boards, boardIDs, err := h.newBoards(n, noFreeCell, seed, minDistance)
+	switch {
+	case errors.Is(err, bingo.ErrBatchNotUnique):
+		h.badRequest(w, err.Error())
+		return
+	case err != nil:
+		h.internalServerError(w, err)
 		return
 	}
 	var buf bytes.Buffer
-	if err := h.zipNewBoards(&buf, n, barcodeFormat); err != nil {
+	if err := h.zipBoards(&buf, boards, boardIDs, barcodeFormat, freeSpace, seed, issue); err != nil {
 		err := fmt.Errorf("creating zip file: %v", err)
 		h.internalServerError(w, err)
 		return
@@ -261,37 +771,280 @@ func (h handler) createBoards(w http.ResponseWriter, r *http.Request) {
 	buf.WriteTo(w)
 }
 
-// zipNewBoards writes n new boards to a zip file.
-func (h handler) zipNewBoards(w io.Writer, n int, barcodeFormat string) error {
-	z := zip.NewWriter(w)
-	for i := 1; i <= n; i++ {
-		fileName := fmt.Sprintf("bingo_%v.svg", i)
-		f, err := z.Create(fileName)
+// newBoards creates a batch of n boards that have unique ids and are at least the minimum distance apart.
+// Boards are created from consecutive seeds if the seed is set.  Seeded boards are not registered, since the same seed always creates them.
+// An error is returned if the batch cannot be made unique enough.
+func (h handler) newBoards(n int, noFreeCell bool, seed *uint64, minDistance int) ([]bingo.Board, []string, error) {
+	var boards []bingo.Board
+	switch {
+	case seed != nil:
+		boards = make([]bingo.Board, n)
+		for i := range boards {
+			boards[i] = *bingo.NewBoardFromSeed(*seed + uint64(i))
+		}
+		if err := bingo.CheckBatch(boards, minDistance); err != nil {
+			return nil, nil, fmt.Errorf("seeded boards, try another seed: %w", err)
+		}
+	default:
+		opts := bingo.BatchOptions{
+			NoFreeCell:  noFreeCell,
+			MinDistance: minDistance,
+			Registry:    h.boards,
+		}
+		var err error
+		boards, err = h.dealer.NewBoards(n, opts)
 		if err != nil {
-			return fmt.Errorf("creating file #%v: %v", i, fileName)
+			return nil, nil, fmt.Errorf("creating boards: %w", err)
 		}
-		b := bingo.NewBoard()
+	}
+	boardIDs := make([]string, len(boards))
+	for i, b := range boards {
 		boardID, err := b.ID()
 		if err != nil {
-			return fmt.Errorf("getting id of board #%v: %v\nboard: %#v", i, err, b)
+			return nil, nil, fmt.Errorf("getting board #%v id: %v", i+1, err)
+		}
+		boardIDs[i] = boardID
+	}
+	return boards, boardIDs, nil
+}
+
+// zipBoards writes the boards to a zip file.
+// The ids of the boards are listed in a manifest file in the zip so the batch can be checked later.
+// Boards created from seeds have their seeds shown on them.
+// If boards are signed, each board has an authenticity code for the issue number in its free space and barcode.  The manifest has the ids without codes.
+func (h handler) zipBoards(w io.Writer, boards []bingo.Board, boardIDs []string, barcodeFormat, freeSpace string, seed *uint64, issue uint64) error {
+	z := zip.NewWriter(w)
+	for i, b := range boards {
+		fileName := fmt.Sprintf("bingo_%v.svg", i+1)
+		f, err := z.Create(fileName)
+		if err != nil {
+			return fmt.Errorf("creating file #%v: %v", i+1, fileName)
+		}
+		var seedText string
+		if seed != nil {
+			seedText = strconv.FormatUint(*seed+uint64(i), 10)
 		}
-		barcode, err := h.boardBarcode(boardID, barcodeFormat)
+		code := h.boardSigner.code(boardIDs[i], issue)
+		barcode, err := h.boardBarcode(signedBoardID(boardIDs[i], code), barcodeFormat)
 		if err != nil {
-			return fmt.Errorf("creating board #%v bar code: %v", i, err)
+			return fmt.Errorf("creating board #%v bar code: %v", i+1, err)
 		}
-		if err := executeBoardExportTemplate(f, *b, boardID, barcode); err != nil {
-			return fmt.Errorf("adding board #%v to zip file: %v", i, err)
+		if err := executeBoardExportTemplate(f, b, boardIDs[i], code, barcode, freeSpace, seedText); err != nil {
+			return fmt.Errorf("adding board #%v to zip file: %v", i+1, err)
 		}
 	}
+	f, err := z.Create(manifestFileName)
+	if err != nil {
+		return fmt.Errorf("creating manifest file: %v", err)
+	}
+	if err := writeManifest(f, boardIDs); err != nil {
+		return fmt.Errorf("adding manifest to zip file: %v", err)
+	}
 	if err := z.Close(); err != nil {
 		return fmt.Errorf("writing/closing zip file: %v", err)
 	}
 	return nil
 }
 
-// parseGame parses the game, writing parse errors to the response.
+// checkBoards checks each board in an uploaded batch manifest for the patterns in the library, rendering the boards that have any.
+// The 'gameID' form parameter is the game to check the boards in.  The 'manifest' form file lists the board ids, one per line.
+func (h handler) checkBoards(w http.ResponseWriter, r *http.Request) {
+	gameID := r.FormValue("gameID")
+	g, ok := h.parseGame(gameID, w)
+	if !ok || !checkClassicGame(*g, w, h) {
+		return
+	}
+	f, _, err := r.FormFile("manifest")
+	if err != nil {
+		message := fmt.Sprintf("getting manifest file: %v", err)
+		h.badRequest(w, message)
+		return
+	}
+	defer f.Close()
+	boardIDs, err := readManifest(f)
+	if err != nil {
+		h.badRequest(w, err.Error())
+		return
+	}
+	patterns := bingo.Patterns()
+	var winners []boardWinner
+	for i, boardID := range boardIDs {
+		b, err := bingo.BoardFromID(boardID)
+		if err != nil {
+			message := fmt.Sprintf("getting board #%v from manifest: %v", i+1, err)
+			h.badRequest(w, message)
+			return
+		}
+		var matches []bingo.Pattern
+		for _, p := range patterns {
+			if b.Matches(*g, p) {
+				matches = append(matches, p)
+			}
+		}
+		if len(matches) != 0 {
+			bw := boardWinner{
+				BoardID:  boardID,
+				Patterns: matches,
+			}
+			winners = append(winners, bw)
+		}
+	}
+	executeWinnersTemplate(w, h.favicon, gameID, len(boardIDs), winners)
+}
+
+// newGameID is the signed id of the new game, or the code of the game if games are stored.
+// Stored games are saved with secret ids that are not signed, which have the order of the numbers of committed games.
+func (h handler) newGameID(g bingo.Game) (string, error) {
+	gameID, err := g.SecretID()
+	if err != nil {
+		return "", fmt.Errorf("getting new game id: %v\ngame: %#v", err, g)
+	}
+	if h.games == nil {
+		return h.signer.sign(gameID), nil
+	}
+	code, err := h.games.Create(gameID)
+	if err != nil {
+		return "", fmt.Errorf("saving new game: %v", err)
+	}
+	return code, nil
+}
+
+// gameCode determines if the id is the code of a stored game rather than the id of a game.
+func (h handler) gameCode(id string) (code string, ok bool) {
+	if h.games == nil {
+		return "", false
+	}
+	if _, err := bingo.GameFromID(id); err == nil || strings.Contains(id, gameSignatureSeparator) {
+		return "", false
+	}
+	if _, err := h.games.Get(id); err != nil {
+		return "", false
+	}
+	return id, true
+}
+
+// newBoard creates a new board and its id.
+// Boards without a free cell have a number in the center cell.  Boards are created from the seed if it is set.
+func (h handler) newBoard(noFreeCell bool, seed *uint64) (*bingo.Board, string, error) {
+	var b *bingo.Board
+	switch {
+	case seed != nil:
+		b = bingo.NewBoardFromSeed(*seed)
+	case noFreeCell:
+		b = h.dealer.NewNoFreeCellBoard()
+	default:
+		b = h.dealer.NewBoard()
+	}
+	boardID, err := b.ID()
+	if err != nil {
+		return nil, "", fmt.Errorf("getting new board id: %v\nboard: %#v", err, b)
+	}
+	return b, boardID, nil
+}
+
+// parseBoardCount parses the number of boards to create, writing parse errors to the response.
+// Between 1 and 1000 boards can be created at a time.
+func parseBoardCount(nParam string, w http.ResponseWriter, ew errorWriter) (n int, ok bool) {
+	n, err := strconv.Atoi(nParam)
+	if err != nil {
+		message := fmt.Sprintf("%v: example: /game/boards?n=5 creates 5 unique boards", err)
+		ew.badRequest(w, message)
+		return 0, false
+	}
+	if n < 1 || n > 1000 {
+		message := "n must be be between 1 and 1000"
+		ew.badRequest(w, message)
+		return 0, false
+	}
+	return n, true
+}
+
+// parseNewGame creates a new game with the ball count, rules, or seed, writing parse errors to the response.
+// Classic 75-ball games are created if the ball count, rules, and seed are empty.
+func parseNewGame(ballsParam, rulesParam, seedParam string, w http.ResponseWriter, ew errorWriter) (g *bingo.Game, ok bool) {
+	switch {
+	case len(rulesParam) != 0 && len(ballsParam) != 0:
+		ew.badRequest(w, "games can have balls or rules, not both")
+		return nil, false
+	case len(seedParam) != 0 && (len(rulesParam) != 0 || len(ballsParam) != 0 && ballsParam != strconv.Itoa(bingo.Balls75)):
+		ew.badRequest(w, "games from seeds are 75-ball games without rules")
+		return nil, false
+	case len(seedParam) != 0:
+		seed, ok := parseSeed(seedParam, w, ew)
+		if !ok {
+			return nil, false
+		}
+		return bingo.NewGameFromSeed(*seed), true
+	case len(rulesParam) != 0:
+		rules, ok := parseRules(rulesParam, w, ew)
+		if !ok {
+			return nil, false
+		}
+		g, _ := rules.NewGame() // the rules are valid
+		return g, true
+	case len(ballsParam) == 0:
+		return new(bingo.Game), true
+	}
+	balls, err := strconv.Atoi(ballsParam)
+	if err != nil {
+		message := fmt.Sprintf("parsing balls: %v", err)
+		ew.badRequest(w, message)
+		return nil, false
+	}
+	g, err = bingo.NewGame(balls)
+	if err != nil {
+		ew.badRequest(w, err.Error())
+		return nil, false
+	}
+	return g, true
+}
+
+// commitNewGame commits to the order of the numbers of the new game if commit is true, writing problems to the response.
+// Games from seeds cannot be committed because their order is already known.
+// Games can only be committed if they are stored, so the salt and order of their numbers are kept on the server until the game is over.
+func commitNewGame(g *bingo.Game, commit, stored bool, w http.ResponseWriter, ew errorWriter) (ok bool) {
+	switch {
+	case !commit:
+		return true
+	case g.Seeded():
+		ew.badRequest(w, "games from seeds cannot be committed")
+		return false
+	case !stored:
+		ew.badRequest(w, "games can only be committed when games are stored, so the order of their numbers is kept secret")
+		return false
+	}
+	if err := g.Commit(); err != nil {
+		ew.internalServerError(w, err)
+		return false
+	}
+	return true
+}
+
+// findGame gets the game with the id, which can also be the code of a stored game.
+// When game ids are signed, ids that are not signed or have signatures for other ids are rejected.
+// Committed games are only found by their codes because their ids do not have the order of their numbers.
+func (h handler) findGame(id string) (*bingo.Game, error) {
+	gameID, err := h.signer.verify(id)
+	if err == nil {
+		var g *bingo.Game
+		switch g, err = bingo.GameFromID(gameID); {
+		case err == nil && g.Committed():
+			err = errors.New("committed games can only be found by their codes")
+		case err == nil:
+			return g, nil
+		}
+	}
+	if h.games != nil {
+		if gameID, err2 := h.games.Get(id); err2 == nil {
+			return bingo.GameFromID(gameID)
+		}
+	}
+	return nil, err
+}
+
+// parseGame gets the game with the id for pages, writing problems to the response.
 func (h handler) parseGame(id string, w http.ResponseWriter) (g *bingo.Game, ok bool) {
-	g, err := bingo.GameFromID(id)
+	g, err := h.findGame(id)
 	if err != nil {
 		message := fmt.Sprintf("getting game from query parameter: %v", err)
 		h.badRequest(w, message)
@@ -300,17 +1053,239 @@ func (h handler) parseGame(id string, w http.ResponseWriter) (g *bingo.Game, ok
 	return g, true
 }
 
+// parseNumbers parses the numbers that are separated by spaces or commas, writing parse errors to the response.
+func parseNumbers(name, numbersParam string, w http.ResponseWriter, ew errorWriter) (nums []bingo.Number, ok bool) {
+	fields := strings.FieldsFunc(numbersParam, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	nums = make([]bingo.Number, len(fields))
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 1 || n > 255 {
+			message := fmt.Sprintf("parsing %v: %q is not a number from 1 to 255", name, f)
+			ew.badRequest(w, message)
+			return nil, false
+		}
+		nums[i] = bingo.Number(n)
+	}
+	return nums, true
+}
+
+// formatNumbers joins the values of the numbers with spaces.
+func formatNumbers(nums []bingo.Number) string {
+	values := make([]string, len(nums))
+	for i, n := range nums {
+		values[i] = strconv.Itoa(n.Value())
+	}
+	return strings.Join(values, " ")
+}
+
 // parseBoard parses the board, writing parse errors to the response.
-func (h handler) parseBoard(id string, w http.ResponseWriter) (b *bingo.Board, ok bool) {
+func parseBoard(id string, w http.ResponseWriter, ew errorWriter) (b *bingo.Board, ok bool) {
 	b, err := bingo.BoardFromID(id)
 	if err != nil {
 		message := fmt.Sprintf("getting board from query parameter: %v", err)
-		h.badRequest(w, message)
+		ew.badRequest(w, message)
 		return nil, false
 	}
 	return b, true
 }
 
+// parseSeed parses the seed of a game or board, writing parse errors to the response.
+// The seed is nil if the parameter is empty.
+func parseSeed(seedParam string, w http.ResponseWriter, ew errorWriter) (seed *uint64, ok bool) {
+	if len(seedParam) == 0 {
+		return nil, true
+	}
+	s, err := strconv.ParseUint(seedParam, 10, 64)
+	if err != nil {
+		message := fmt.Sprintf("parsing seed: %v", err)
+		ew.badRequest(w, message)
+		return nil, false
+	}
+	return &s, true
+}
+
+// parseNewBoardSeed parses the seed of new boards, writing problems to the response.
+// Boards from seeds always have a free cell.
+func parseNewBoardSeed(seedParam string, noFreeCell bool, w http.ResponseWriter, ew errorWriter) (seed *uint64, ok bool) {
+	if len(seedParam) != 0 && noFreeCell {
+		ew.badRequest(w, "boards from seeds have a free cell")
+		return nil, false
+	}
+	return parseSeed(seedParam, w, ew)
+}
+
+// maxMinDistance is the most numbers that boards can be required to not share, the number of cells on a board.
+const maxMinDistance = len(bingo.Board{})
+
+// parseMinDistance parses the fewest numbers that each board of a batch must have that are not on each other board, writing parse errors to the response.
+// The minimum distance is zero if the parameter is empty.
+func parseMinDistance(minDistanceParam string, w http.ResponseWriter, ew errorWriter) (minDistance int, ok bool) {
+	if len(minDistanceParam) == 0 {
+		return 0, true
+	}
+	minDistance, err := strconv.Atoi(minDistanceParam)
+	if err != nil || minDistance < 0 || minDistance > maxMinDistance {
+		message := fmt.Sprintf("minDistance must be a number between 0 and %v", maxMinDistance)
+		ew.badRequest(w, message)
+		return 0, false
+	}
+	return minDistance, true
+}
+
+// parseIssue parses the batch or event number that is signed in the authenticity codes of new boards, writing parse errors to the response.
+// The issue number is zero if the parameter is empty.
+func parseIssue(issueParam string, w http.ResponseWriter, ew errorWriter) (issue uint64, ok bool) {
+	if len(issueParam) == 0 {
+		return 0, true
+	}
+	issue, err := strconv.ParseUint(issueParam, 10, 64)
+	if err != nil {
+		message := fmt.Sprintf("parsing issue number: %v", err)
+		ew.badRequest(w, message)
+		return 0, false
+	}
+	return issue, true
+}
+
+// parseFreeSpace checks that the text to show in the free space is short enough to fit, writing problems to the response.
+func parseFreeSpace(freeSpace string, w http.ResponseWriter, ew errorWriter) (ok bool) {
+	if utf8.RuneCountInString(freeSpace) > maxFreeSpaceLength {
+		message := fmt.Sprintf("free space text must be at most %v characters long", maxFreeSpaceLength)
+		ew.badRequest(w, message)
+		return false
+	}
+	return true
+}
+
+// parseRules parses the rules of cards, writing parse errors to the response.
+// The classic rules are used if the rules are empty.
+func parseRules(rulesParam string, w http.ResponseWriter, ew errorWriter) (r *bingo.Rules, ok bool) {
+	if len(rulesParam) == 0 {
+		r := bingo.ClassicRules
+		return &r, true
+	}
+	r, err := bingo.ParseRules(rulesParam)
+	if err != nil {
+		message := fmt.Sprintf("parsing rules: %v", err)
+		ew.badRequest(w, message)
+		return nil, false
+	}
+	return r, true
+}
+
+// parseCard parses the card, writing parse errors to the response.
+// Ids of classic boards are parsed as cards with the classic rules.
+func parseCard(id string, w http.ResponseWriter, ew errorWriter) (c *bingo.Card, ok bool) {
+	c, err := bingo.CardFromID(id)
+	if err != nil {
+		message := fmt.Sprintf("getting card from query parameter: %v", err)
+		ew.badRequest(w, message)
+		return nil, false
+	}
+	return c, true
+}
+
+// cardLinePattern and cardFilledPattern are the names of the only library patterns that cards with rules can be checked for.
+const (
+	cardLinePattern   = "HasLine"
+	cardFilledPattern = "IsFilled"
+)
+
+// checkCard determines if the card has the library pattern with the checkType name in the game, writing problems to the response.
+// Cards with rules other than the classic rules can only be checked for lines and being filled, and only in games with their rules.
+func checkCard(c bingo.Card, g bingo.Game, checkType string, w http.ResponseWriter, ew errorWriter) (result, ok bool) {
+	if !c.Rules.Plays(g) {
+		message := fmt.Sprintf("card with rules %v is not played in the game", c.Rules)
+		ew.badRequest(w, message)
+		return false, false
+	}
+	switch checkType {
+	case cardLinePattern:
+		return c.HasLine(g), true
+	case cardFilledPattern:
+		return c.IsFilled(g), true
+	}
+	message := fmt.Sprintf("cards can only be checked for %v or %v, got %q", cardLinePattern, cardFilledPattern, checkType)
+	ew.badRequest(w, message)
+	return false, false
+}
+
+// errNotClassicGame is the problem with checking classic 5x5 boards in games that are not played with them.
+var errNotClassicGame = fmt.Errorf("boards can only be checked in %v-ball games without rules", bingo.Balls75)
+
+// checkClassicGame ensures the game is played with classic 5x5 boards, writing a bad request to the response if it is not.
+// Boards would otherwise match drawn numbers of games with other ball counts, which have other numbers in their columns.
+func checkClassicGame(g bingo.Game, w http.ResponseWriter, ew errorWriter) bool {
+	if !g.Classic() {
+		ew.badRequest(w, errNotClassicGame.Error())
+		return false
+	}
+	return true
+}
+
+// parsePattern parses the custom pattern, writing parse errors to the response.
+func parsePattern(id string, w http.ResponseWriter, ew errorWriter) (p *bingo.Pattern, ok bool) {
+	p, err := bingo.PatternFromID(id)
+	if err != nil {
+		message := fmt.Sprintf("getting pattern from query parameter: %v", err)
+		ew.badRequest(w, message)
+		return nil, false
+	}
+	return p, true
+}
+
+// checkPattern gets the library pattern with the checkType name or the custom pattern if the checkType is Custom.
+// Unknown check types and invalid custom patterns are written as errors to the response.
+func checkPattern(checkType, patternID string, w http.ResponseWriter, ew errorWriter) (p *bingo.Pattern, ok bool) {
+	p, err := findPattern(checkType, patternID)
+	if err != nil {
+		ew.badRequest(w, err.Error())
+		return nil, false
+	}
+	return p, true
+}
+
+// findPattern gets the library pattern with the checkType name or the custom pattern if the checkType is Custom.
+func findPattern(checkType, patternID string) (*bingo.Pattern, error) {
+	if checkType == bingo.CustomPatternName {
+		p, err := bingo.PatternFromID(patternID)
+		if err != nil {
+			return nil, fmt.Errorf("getting pattern from query parameter: %v", err)
+		}
+		return p, nil
+	}
+	libraryPattern, ok := bingo.PatternByName(checkType)
+	if !ok {
+		return nil, fmt.Errorf("unknown checkType %q", checkType)
+	}
+	return &libraryPattern, nil
+}
+
+// parsePatternID checks that the optional custom pattern id is the id of a pattern, writing problems to the response.
+// Pattern ids are checked before they are added to redirect urls.
+func parsePatternID(patternID string, w http.ResponseWriter, ew errorWriter) (ok bool) {
+	if len(patternID) == 0 {
+		return true
+	}
+	if _, err := bingo.PatternFromID(patternID); err != nil {
+		message := fmt.Sprintf("getting pattern from query parameter: %v", err)
+		ew.badRequest(w, message)
+		return false
+	}
+	return true
+}
+
+// patternQuery is the query parameter to add to a game url for the custom pattern id, if it is set.
+// The id is escaped so it cannot add other query parameters to the url.
+func patternQuery(patternID string) string {
+	if len(patternID) == 0 {
+		return ""
+	}
+	return "&pattern=" + url.QueryEscape(patternID)
+}
+
 // boardBarcode uses the Barcoder to encode the bar code image as a base64-encode png image with transparency.
 func (h handler) boardBarcode(boardID string, format string) (string, error) {
 	if h.Barcoder == nil {
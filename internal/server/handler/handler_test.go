@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"archive/zip"
+	"bytes"
 	"errors"
 	"image"
 	"image/color"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"regexp"
 	"strings"
@@ -20,7 +24,13 @@ func TestNewHandler(t *testing.T) {
 		timeF := func() string { return "any-time" }
 		for i, test := range handlerTests {
 			w := httptest.NewRecorder()
-			h := New(gameCount, timeF, okMockBarcoder)
+			cfg := Config{
+				GameCount: gameCount,
+				Time:      timeF,
+				Barcoder:  okMockBarcoder,
+				Dealer:    bingo.NewDealer(1257894001),
+			}
+			h := New(cfg)
 			test.r.Header = test.header
 			h.ServeHTTP(w, test.r)
 			gotStatusCode := w.Code
@@ -38,7 +48,7 @@ func TestNewHandler(t *testing.T) {
 	})
 	t.Run("zero configs", func(t *testing.T) {
 		for i, test := range handlerTests {
-			h := New(0, nil, nil)
+			h := New(Config{})
 			w := httptest.NewRecorder()
 			test.r.Header = test.header
 			h.ServeHTTP(w, test.r)
@@ -58,29 +68,29 @@ func TestHandlerServeHTTP(t *testing.T) {
 		copy(wantGameInfos, test.wantGameInfos)
 		h := handler{
 			time:      test.time,
-			gameInfos: gameInfos,
+			gameInfos: &gameHistory{infos: gameInfos},
 			Barcoder:  test.Barcoder,
+			dealer:    bingo.NewDealer(1257894001), // make board creation deterministic
 		}
 		test.r.Header = test.header
-		bingo.GameResetter.Seed(1257894001) // make board creation deterministic
 		h.ServeHTTP(w, test.r)
 		switch {
 		case w.Code != test.wantStatusCode:
 			t.Errorf("test %v (%v): HTTPS response status codes not equal: wanted %v, got %v: %v", i, test.name, test.wantStatusCode, w.Code, w.Body.String())
 		case !reflect.DeepEqual(test.wantHeader, w.Header()):
 			t.Errorf("test %v (%v): HTTPS response headers not equal:\nwanted: %v\ngot:    %v", i, test.name, test.wantHeader, w.Header())
-		case !reflect.DeepEqual(wantGameInfos, h.gameInfos):
-			t.Errorf("test %v (%v): game infos not equal:\nwanted: %v\ngot:    %v", i, test.name, wantGameInfos, h.gameInfos)
+		case !reflect.DeepEqual(wantGameInfos, h.gameInfos.infos):
+			t.Errorf("test %v (%v): game infos not equal:\nwanted: %v\ngot:    %v", i, test.name, wantGameInfos, h.gameInfos.infos)
 		}
 	}
 }
 
 func TestHandlerDrawNumberModifiesGames(t *testing.T) {
 	w1 := httptest.NewRecorder()
-	r1 := httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=8-"+board1257894001IDNumbers))
+	r1 := httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=v2-8-"+board1257894001IDNumbers))
 	r1.Header = formContentTypeHeader
 	h := handler{
-		gameInfos: make([]gameInfo, 0, 1),
+		gameInfos: newGameHistory(1),
 	}
 	h.ServeHTTP(w1, r1)
 	if want, got := 303, w1.Result().StatusCode; want != got {
@@ -89,7 +99,7 @@ func TestHandlerDrawNumberModifiesGames(t *testing.T) {
 	w2 := httptest.NewRecorder()
 	r2 := httptest.NewRequest(methodGet, urlPathGames, nil)
 	h.ServeHTTP(w2, r2)
-	if want, got := "9-"+board1257894001IDNumbers, w2.Body.String(); !strings.Contains(got, want) {
+	if want, got := "v2-9-"+board1257894001IDNumbers, w2.Body.String(); !strings.Contains(got, want) {
 		t.Errorf("wanted modified game history to be displayed on the games page (%v):\n%+v", want, w2)
 	}
 }
@@ -155,6 +165,379 @@ func TestHandlerCreateBoards(t *testing.T) {
 	})
 }
 
+func TestHandlerCreateBoardsManifest(t *testing.T) {
+	w := httptest.NewRecorder()
+	h := handler{
+		Barcoder: okMockBarcoder,
+	}
+	r := httptest.NewRequest(methodPost, urlPathGameBoards, strings.NewReader("n=3"))
+	r.Header = formContentTypeHeader
+	h.ServeHTTP(w, r)
+	body := w.Body.Bytes()
+	z, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("reading zip file: %v", err)
+	}
+	f, err := z.Open(manifestFileName)
+	if err != nil {
+		t.Fatalf("opening manifest: %v", err)
+	}
+	defer f.Close()
+	boardIDs, err := readManifest(f)
+	switch {
+	case err != nil:
+		t.Errorf("reading manifest: %v", err)
+	case len(boardIDs) != 3:
+		t.Errorf("wanted 3 board ids in manifest, got %v", boardIDs)
+	}
+}
+
+func TestHandlerCreateBoardsRegistry(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		registry       *mockBoardRegistry
+		wantStatusCode int
+		wantRegistered int
+	}{
+		{"registered", "n=3", &mockBoardRegistry{boardIDs: map[string]struct{}{}}, 200, 3},
+		{"seeded boards are not registered", "n=3&seed=42", &mockBoardRegistry{boardIDs: map[string]struct{}{}}, 200, 0},
+		{"register error", "n=3", &mockBoardRegistry{registerErr: errors.New("mock register error")}, 500, 0},
+	}
+	for i, test := range tests {
+		h := handler{
+			Barcoder: okMockBarcoder,
+			boards:   test.registry,
+		}
+		r := httptest.NewRequest(methodPost, urlPathGameBoards, strings.NewReader(test.body))
+		r.Header = formContentTypeHeader
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		switch {
+		case test.wantStatusCode != w.Code:
+			t.Errorf("test %v (%v): status codes not equal: wanted %v, got %v: %v", i, test.name, test.wantStatusCode, w.Code, w.Body.String())
+		case test.wantRegistered != len(test.registry.boardIDs):
+			t.Errorf("test %v (%v): wanted %v registered boards, got %v", i, test.name, test.wantRegistered, test.registry.boardIDs)
+		}
+	}
+}
+
+func TestHandlerCheckBoards(t *testing.T) {
+	multipartRequest := func(gameID, manifest string, addFile bool) *http.Request {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		mw.WriteField(qpGameID, gameID)
+		if addFile {
+			fw, _ := mw.CreateFormFile("manifest", manifestFileName)
+			fw.Write([]byte(manifest))
+		}
+		mw.Close()
+		r := httptest.NewRequest(methodPost, urlPathGameBoardsCheck, &body)
+		r.Header.Set(headerContentType, mw.FormDataContentType())
+		return r
+	}
+	t.Run("ok", func(t *testing.T) {
+		manifest := "4kXCOHldCpztBiBe\n" + board1257894001ID + "\n"
+		r := multipartRequest("v2-5-"+board1257894001IDNumbers, manifest, true)
+		w := httptest.NewRecorder()
+		var h handler
+		h.ServeHTTP(w, r)
+		got := w.Body.String()
+		switch {
+		case w.Code != 200:
+			t.Errorf("wanted ok status code, got %v: %v", w.Code, got)
+		case !strings.Contains(got, "Checked 2 boards"):
+			t.Errorf("board count missing: %v", got)
+		case !strings.Contains(got, board1257894001ID):
+			t.Errorf("winning board missing: %v", got)
+		case strings.Contains(got, "4kXCOHldCpztBiBe"):
+			t.Errorf("wanted only boards with BINGO to be listed: %v", got)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name string
+			r    *http.Request
+		}{
+			{"bad game id", multipartRequest(badID, board1257894001ID, true)},
+			{"90-ball game", multipartRequest(game90ID, board1257894001ID, true)},
+			{"missing manifest", multipartRequest("v2-5-"+board1257894001IDNumbers, "", false)},
+			{"empty manifest", multipartRequest("v2-5-"+board1257894001IDNumbers, "", true)},
+			{"bad board id", multipartRequest("v2-5-"+board1257894001IDNumbers, board1257894001ID+"\n"+badID, true)},
+		}
+		for i, test := range tests {
+			w := httptest.NewRecorder()
+			var h handler
+			h.ServeHTTP(w, test.r)
+			if want, got := 400, w.Code; want != got {
+				t.Errorf("test %v (%v): status codes not equal: wanted %v, got %v", i, test.name, want, got)
+			}
+		}
+	})
+}
+
+func TestHandlerStrip(t *testing.T) {
+	var h handler
+	r := httptest.NewRequest(methodPost, urlPathGameStrip, nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	location := w.Header().Get(headerLocation)
+	wantPrefix := urlPathGameStrip + "?stripID="
+	switch {
+	case w.Code != 303:
+		t.Fatalf("creating strip: wanted redirect, got status %v: %v", w.Code, w.Body.String())
+	case !strings.HasPrefix(location, wantPrefix):
+		t.Fatalf("creating strip: wanted redirect location to start with %q, got %q", wantPrefix, location)
+	}
+	tests := []struct {
+		name            string
+		target          string
+		wantStatusCode  int
+		wantContentType string
+	}{
+		{"new strip", location, 200, "image/svg+xml"},
+		{"bad strip id", urlPathGameStrip + "?stripID=" + badID, 400, "text/plain; charset=utf-8"},
+	}
+	for i, test := range tests {
+		r := httptest.NewRequest(methodGet, test.target, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		switch {
+		case w.Code != test.wantStatusCode:
+			t.Errorf("test %v (%v): status codes not equal: wanted %v, got %v: %v", i, test.name, test.wantStatusCode, w.Code, w.Body.String())
+		case w.Header().Get(headerContentType) != test.wantContentType:
+			t.Errorf("test %v (%v): content types not equal: wanted %q, got %q", i, test.name, test.wantContentType, w.Header().Get(headerContentType))
+		}
+	}
+}
+
+func TestHandlerVerify(t *testing.T) {
+	store := &mockGameStore{
+		games:    map[string]string{},
+		nextCode: "AB2C",
+	}
+	h := handler{
+		games: store,
+	}
+	r := httptest.NewRequest(methodPost, urlPathGame, strings.NewReader("balls=30&commit=on"))
+	r.Header = formContentTypeHeader
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	location := w.Header().Get(headerLocation)
+	wantLocation := urlPathGame + "?" + qpGameID + "=AB2C"
+	switch {
+	case w.Code != 303:
+		t.Fatalf("creating committed game: wanted redirect, got status %v: %v", w.Code, w.Body.String())
+	case wantLocation != location:
+		t.Fatalf("creating committed game: wanted redirect to code of stored game %q, got %q", wantLocation, location)
+	}
+	secretID := store.games["AB2C"]
+	salt, _, _ := strings.Cut(strings.TrimPrefix(secretID, "c"), ".")
+	if len(salt) != 32 {
+		t.Fatalf("wanted secret id of stored game to have salt: %q", secretID)
+	}
+	// sha256 of the bytes 00-0f, 03, 01, 02
+	const commitment = "5a1a2b939f446e9b5b4666ac9c7c508bd87727a65e96ed3f339a4bdb152e3fa2"
+	const revealedSalt = "000102030405060708090a0b0c0d0e0f"
+	tests := []struct {
+		name           string
+		r              *http.Request
+		wantStatusCode int
+		wantBody       string
+		// secret is whether the salt of the committed game must not be in the body because the game is not over
+		secret bool
+	}{
+		{"empty", httptest.NewRequest(methodGet, urlPathGameVerify, nil), 200, `name="commitment" value=""`, false},
+		{"committed game in progress", httptest.NewRequest(methodGet, urlPathGameVerify+"?"+qpGameID+"=AB2C", nil), 400, "only revealed when the game is over", true},
+		{"committed game page", httptest.NewRequest(methodGet, location, nil), 200, "Commitment: <span>", true},
+		{"committed game page end form", httptest.NewRequest(methodGet, location, nil), 200, `action="/game/end"`, true},
+		{"committed game api", httptest.NewRequest(methodGet, urlPathAPIGame+"?"+qpGameID+"=AB2C", nil), 200, `"commitment":"`, true},
+		{"draw number", httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=AB2C")), 303, "", true},
+		{"committed game listed by code", httptest.NewRequest(methodGet, urlPathGames, nil), 200, `href="/game?gameID=AB2C"`, true},
+		{"secret id of committed game", httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"="+secretID, nil), 400, "committed games can only be found by their codes", false},
+		{"end game", httptest.NewRequest(methodPost, urlPathGameEnd, strings.NewReader(qpGameID+"=AB2C")), 303, "", false},
+		{"committed game", httptest.NewRequest(methodGet, urlPathGameVerify+"?"+qpGameID+"=AB2C", nil), 200, "Verified:", false},
+		{"revealed salt", httptest.NewRequest(methodGet, urlPathGameVerify+"?"+qpGameID+"=AB2C", nil), 200, salt, false},
+		{"undo draw of revealed game", httptest.NewRequest(methodPost, urlPathGameUndoDraw, strings.NewReader(qpGameID+"=AB2C&reason=oops")), 400, "cannot be undone", false},
+		{"revealed numbers", httptest.NewRequest(methodGet, urlPathGameVerify+"?commitment="+commitment+"&salt="+revealedSalt+"&order=3,1,2&drawn=3", nil), 200, "Verified:", false},
+		{"reordered numbers", httptest.NewRequest(methodGet, urlPathGameVerify+"?commitment="+commitment+"&salt="+revealedSalt+"&order=1+3+2", nil), 200, "Not verified:", false},
+		{"bad order", httptest.NewRequest(methodGet, urlPathGameVerify+"?commitment="+commitment+"&salt="+revealedSalt+"&order=3,1,x", nil), 400, "parsing order", false},
+		{"bad drawn numbers", httptest.NewRequest(methodGet, urlPathGameVerify+"?commitment="+commitment+"&salt="+revealedSalt+"&order=3,1,2&drawn=0", nil), 400, "parsing drawn", false},
+		{"game not committed", httptest.NewRequest(methodGet, urlPathGameVerify+"?"+qpGameID+"=0", nil), 400, "game is not committed", false},
+		{"end game not committed", httptest.NewRequest(methodPost, urlPathGameEnd, strings.NewReader(qpGameID+"=0")), 400, "only committed games can be ended", false},
+		{"bad game", httptest.NewRequest(methodGet, urlPathGameVerify+"?"+qpGameID+"="+badID, nil), 400, "getting game", false},
+	}
+	for i, test := range tests {
+		test.r.Header = formContentTypeHeader
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, test.r)
+		switch {
+		case w.Code != test.wantStatusCode:
+			t.Errorf("test %v (%v): status codes not equal: wanted %v, got %v: %v", i, test.name, test.wantStatusCode, w.Code, w.Body.String())
+		case !strings.Contains(w.Body.String(), test.wantBody):
+			t.Errorf("test %v (%v): wanted body to contain %q, got: %v", i, test.name, test.wantBody, w.Body.String())
+		case test.secret && strings.Contains(w.Body.String(), salt):
+			t.Errorf("test %v (%v): wanted salt to be secret before the game is over: %v", i, test.name, w.Body.String())
+		}
+	}
+}
+
+func TestHandlerGameStore(t *testing.T) {
+	newStore := func() *mockGameStore {
+		return &mockGameStore{
+			games: map[string]string{
+				"K7QF": "v2-8-" + board1257894001IDNumbers,
+			},
+			nextCode: "AB2C",
+		}
+	}
+	tests := []struct {
+		name           string
+		r              *http.Request
+		store          *mockGameStore
+		wantStatusCode int
+		wantLocation   string
+		wantBodyPart   string
+		wantGames      map[string]string
+	}{
+		{
+			name:           "create game",
+			r:              httptest.NewRequest(methodPost, urlPathGame, nil),
+			store:          newStore(),
+			wantStatusCode: 303,
+			wantLocation:   urlPathGame + "?" + qpGameID + "=AB2C",
+			wantGames: map[string]string{
+				"K7QF": "v2-8-" + board1257894001IDNumbers,
+				"AB2C": "0",
+			},
+		},
+		{
+			name:           "create game - store error",
+			r:              httptest.NewRequest(methodPost, urlPathGame, nil),
+			store:          &mockGameStore{createErr: errors.New("mock create error")},
+			wantStatusCode: 500,
+		},
+		{
+			name:           "get game by code",
+			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"=K7QF", nil),
+			store:          newStore(),
+			wantStatusCode: 200,
+			wantBodyPart:   "Game code: <span>K7QF</span>",
+		},
+		{
+			name:           "get board in game by code",
+			r:              httptest.NewRequest(methodGet, urlPathGameBoard+"?"+qpBoardID+"="+board1257894001ID+"&"+qpGameID+"=K7QF", nil),
+			store:          newStore(),
+			wantStatusCode: 200,
+			wantBodyPart:   `data-game-code="K7QF"`,
+		},
+		{
+			name:           "get game by legacy id",
+			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"=5-"+board1257894001LegacyIDNumbers, nil),
+			store:          newStore(),
+			wantStatusCode: 200,
+			wantBodyPart:   "Numbers left: 70",
+		},
+		{
+			name:           "get game by v2 id",
+			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers, nil),
+			store:          newStore(),
+			wantStatusCode: 200,
+			wantBodyPart:   "Numbers left: 70",
+		},
+		{
+			name:           "get game with card check in rules game",
+			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"="+rulesGame3ID+"&"+qpBoardID+"="+card3x3n10ID+"&"+qpType+"="+typeHasLine, nil),
+			store:          newStore(),
+			wantStatusCode: 200,
+			wantBodyPart:   `<span class="has-bingo">BINGO !!!</span>`,
+		},
+		{
+			name:           "get game - unknown code",
+			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"=ZZZZ", nil),
+			store:          newStore(),
+			wantStatusCode: 400,
+		},
+		{
+			name:           "draw number by code",
+			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=K7QF")),
+			store:          newStore(),
+			wantStatusCode: 303,
+			wantLocation:   urlPathGame + "?" + qpGameID + "=K7QF",
+			wantGames: map[string]string{
+				"K7QF": "v2-9-" + board1257894001IDNumbers,
+			},
+		},
+		{
+			name: "draw number by code - legacy stored id",
+			r:    httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=K7QF")),
+			store: &mockGameStore{
+				games: map[string]string{
+					"K7QF": "8-" + board1257894001LegacyIDNumbers,
+				},
+			},
+			wantStatusCode: 303,
+			wantLocation:   urlPathGame + "?" + qpGameID + "=K7QF",
+			wantGames: map[string]string{
+				"K7QF": "v2-9-" + board1257894001IDNumbers,
+			},
+		},
+		{
+			name: "draw number by code - store error",
+			r:    httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=K7QF")),
+			store: &mockGameStore{
+				games: map[string]string{
+					"K7QF": "v2-8-" + board1257894001IDNumbers,
+				},
+				updateErr: errors.New("mock update error"),
+			},
+			wantStatusCode: 500,
+		},
+		{
+			name:           "undo draw by code",
+			r:              httptest.NewRequest(methodPost, urlPathGameUndoDraw, strings.NewReader(qpGameID+"=K7QF&reason=oops")),
+			store:          newStore(),
+			wantStatusCode: 303,
+			wantLocation:   urlPathGame + "?" + qpGameID + "=K7QF",
+			wantGames: map[string]string{
+				"K7QF": "v2-7-" + board1257894001IDNumbers,
+			},
+		},
+		{
+			name:           "api get game by code",
+			r:              httptest.NewRequest(methodGet, urlPathAPIGame+"?"+qpGameID+"=K7QF", nil),
+			store:          newStore(),
+			wantStatusCode: 200,
+			wantBodyPart:   `"id":"v2-8-` + board1257894001IDNumbers + `","code":"K7QF"`,
+		},
+		{
+			name:           "api create game",
+			r:              httptest.NewRequest(methodPost, urlPathAPIGame, nil),
+			store:          newStore(),
+			wantStatusCode: 201,
+			wantBodyPart:   `"id":"0","code":"AB2C"`,
+		},
+	}
+	for i, test := range tests {
+		test.r.Header = formContentTypeHeader
+		w := httptest.NewRecorder()
+		h := handler{
+			games: test.store,
+		}
+		h.ServeHTTP(w, test.r)
+		switch {
+		case test.wantStatusCode != w.Code:
+			t.Errorf("test %v (%v): status codes not equal: wanted %v, got %v: %v", i, test.name, test.wantStatusCode, w.Code, w.Body.String())
+		case test.wantLocation != w.Header().Get(headerLocation):
+			t.Errorf("test %v (%v): locations not equal:\nwanted: %v\ngot:    %v", i, test.name, test.wantLocation, w.Header().Get(headerLocation))
+		case !strings.Contains(w.Body.String(), test.wantBodyPart):
+			t.Errorf("test %v (%v): wanted body to contain %q, got:\n%v", i, test.name, test.wantBodyPart, w.Body.String())
+		case test.wantGames != nil && !reflect.DeepEqual(test.wantGames, test.store.games):
+			t.Errorf("test %v (%v): stored games not equal:\nwanted: %v\ngot:    %v", i, test.name, test.wantGames, test.store.games)
+		}
+	}
+}
+
 const (
 	methodGet                = "GET"
 	methodPost               = "POST"
@@ -162,25 +545,54 @@ const (
 	headerContentDisposition = "Content-Disposition"
 	headerLocation           = "Location"
 	contentTypeHTML          = "text/html; charset=utf-8"
-	board1257894001IDNumbers = "DwgEDAoTGxAcGSopHygxNDIuOUBIQ0ZKAQIDBQYHCQsNDhESFBUWFxgaHR4gISIjJCUmJyssLS8wMzU2Nzg6Ozw9Pj9BQkRFR0lL"
+	board1257894001IDNumbers = "Afw7gy6ui4e5qrGSKpge9xWBhNDH0BBROdLpifV1KxyCnScOQDDuBQAAAAAAAA"
 	board1257894001ID        = "5zuTsMm6CTZAs7ad"
-	badID                    = "BAD-ID"
-	urlPathGames             = "/"
-	urlPathGame              = "/game"
-	urlPathGameCheckBoard    = "/game/board/check"
-	urlPathGameBoard         = "/game/board"
-	urlPathGameDrawNumber    = "/game/draw_number"
-	urlPathGameBoards        = "/game/boards"
-	urlPathHelp              = "/help"
-	urlPathAbout             = "/about"
-	urlPathUnknown           = "/UNKNOWN"
-	qpGameID                 = "gameID"
-	qpBoardID                = "boardID"
-	qpType                   = "type"
-	qpBingo                  = "bingo"
-	qpBarcodeFormat          = "barcodeFormat"
-	typeHasLine              = "HasLine"
-	typeIsFilled             = "IsFilled"
+	// noFreeCellBoardID is a board without a free cell that has the numbers 1-5, 16-20, 31-35, 46-50, and 61-65 in order.
+	noFreeCellBoardID = "5x5n15.ASNAEjQBI0ASNAEjQA"
+	// middleRowGameIDNumbers are the numbers of a game that draws the middle row of noFreeCellBoardID first, with the center number (33) fifth.
+	middleRowGameIDNumbers = "AFA3Z4WdW1RL4Jq4MQNbokIcGrtY1wVqZPk1iiDBYoz-SS-GY2gAAAAAAAAAAA"
+	// game90ID is a 90-ball game with its numbers in order that has drawn 1-15, which are the numbers in the B column of board1257894001ID.
+	game90ID = "v2-15-AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	// rulesGame3ID is a game of 3x3 cards with numbers 1-30 that has drawn 1, 2, and 3.
+	rulesGame3ID = "3x3n10.v2-3-AAAAAAAAAAAAAAAAAAA"
+	// card3x3n10ID is a 3x3 card with numbers 1-30 that has 1, 2, and 3 in its first column.
+	card3x3n10ID = "3x3n10.ASASASA"
+	// board1257894001LegacyIDNumbers are the numbers of board1257894001IDNumbers, with each number stored in a byte.
+	board1257894001LegacyIDNumbers = "DwgEDAoTGxAcGSopHygxNDIuOUBIQ0ZKAQIDBQYHCQsNDhESFBUWFxgaHR4gISIjJCUmJyssLS8wMzU2Nzg6Ozw9Pj9BQkRFR0lL"
+	badID                          = "BAD-ID"
+	urlPathGames                   = "/"
+	urlPathGame                    = "/game"
+	urlPathGameCheckBoard          = "/game/board/check"
+	urlPathGameBoard               = "/game/board"
+	urlPathGameStrip               = "/game/strip"
+	urlPathGameVerify              = "/game/verify"
+	urlPathGameDrawNumber          = "/game/draw_number"
+	urlPathGameUndoDraw            = "/game/undo_draw"
+	urlPathGameEnd                 = "/game/end"
+	urlPathGamePattern             = "/game/pattern"
+	urlPathGameEvents              = "/game/events"
+	urlPathGamePlay                = "/game/play"
+	urlPathGameBoards              = "/game/boards"
+	urlPathGameBoardsCheck         = "/game/boards/check"
+	urlPathHelp                    = "/help"
+	urlPathAPIGame                 = "/api/v1/game"
+	urlPathAPIGameDrawNumber       = "/api/v1/game/draw_number"
+	urlPathAPIBoard                = "/api/v1/board"
+	urlPathAPIBoards               = "/api/v1/boards"
+	urlPathAPIBoardCheck           = "/api/v1/board/check"
+	urlPathAbout                   = "/about"
+	urlPathUnknown                 = "/UNKNOWN"
+	qpGameID                       = "gameID"
+	qpBoardID                      = "boardID"
+	qpType                         = "type"
+	qpBingo                        = "bingo"
+	qpPattern                      = "pattern"
+	qpBarcodeFormat                = "barcodeFormat"
+	typeHasLine                    = "HasLine"
+	typeIsFilled                   = "IsFilled"
+	typeFourCorners                = "FourCorners"
+	typeCustom                     = "Custom"
+	patternBColumnID               = "AAAf"
 )
 
 var (
@@ -219,11 +631,11 @@ var (
 		},
 		{
 			name:           "draw number",
-			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader("gameID=8-"+board1257894001IDNumbers)),
+			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader("gameID=v2-8-"+board1257894001IDNumbers)),
 			header:         formContentTypeHeader,
 			wantStatusCode: 303,
 			wantHeader: http.Header{
-				headerLocation: {urlPathGame + "?" + qpGameID + "=9-" + board1257894001IDNumbers},
+				headerLocation: {urlPathGame + "?" + qpGameID + "=v2-9-" + board1257894001IDNumbers},
 			},
 		},
 		{
@@ -252,44 +664,185 @@ var (
 		},
 		{
 			name:           "get game",
-			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"=5-"+board1257894001IDNumbers, nil),
+			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers, nil),
+			wantStatusCode: 200,
+			wantHeader:     htmlContentTypeHeader,
+		},
+		{
+			name:           "get game (legacy game id)",
+			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"=5-"+board1257894001LegacyIDNumbers, nil),
 			wantStatusCode: 200,
 			wantHeader:     htmlContentTypeHeader,
 		},
 		{
 			name:           "check board - HasLine",
-			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeHasLine, nil),
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeHasLine, nil),
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerContentType: {contentTypeHTML},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=v2-5-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + typeHasLine + "&" + qpBingo},
+			},
+		},
+		{
+			name:           "check board - HasLine (legacy game id)",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=5-"+board1257894001LegacyIDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeHasLine, nil),
 			wantStatusCode: 303,
 			wantHeader: http.Header{
 				headerContentType: {contentTypeHTML},
-				headerLocation:    {urlPathGame + "?" + qpGameID + "=5-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpBingo},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=5-" + board1257894001LegacyIDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + typeHasLine + "&" + qpBingo},
+			},
+		},
+		{
+			name:           "check board - readable id",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpBoardID+"=dtc2h+8t21s+mna24+8pjz&"+qpType+"="+typeHasLine, nil),
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerContentType: {contentTypeHTML},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=v2-5-" + board1257894001IDNumbers + "&" + qpBoardID + "=dtc2h+8t21s+mna24+8pjz&" + qpType + "=" + typeHasLine + "&" + qpBingo},
 			},
 		},
 		{
 			name:           "check board - HasLine (false)",
-			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=3-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeHasLine, nil),
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-3-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeHasLine, nil),
 			wantStatusCode: 303,
 			wantHeader: http.Header{
 				headerContentType: {contentTypeHTML},
-				headerLocation:    {urlPathGame + "?" + qpGameID + "=3-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=v2-3-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + typeHasLine},
 			},
 		},
 		{
 			name:           "check board - IsFilled",
-			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=24-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeIsFilled, nil),
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-24-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeIsFilled, nil),
 			wantStatusCode: 303,
 			wantHeader: http.Header{
 				headerContentType: {contentTypeHTML},
-				headerLocation:    {urlPathGame + "?" + qpGameID + "=24-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpBingo},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=v2-24-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + typeIsFilled + "&" + qpBingo},
 			},
 		},
 		{
 			name:           "check board - IsFilled (false)",
-			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=1-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeIsFilled, nil),
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-1-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeIsFilled, nil),
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerContentType: {contentTypeHTML},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=v2-1-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + typeIsFilled},
+			},
+		},
+		{
+			name:           "check board - FourCorners",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-24-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeFourCorners, nil),
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerContentType: {contentTypeHTML},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=v2-24-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + typeFourCorners + "&" + qpBingo},
+			},
+		},
+		{
+			name:           "check board - FourCorners (false)",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeFourCorners, nil),
 			wantStatusCode: 303,
 			wantHeader: http.Header{
 				headerContentType: {contentTypeHTML},
-				headerLocation:    {urlPathGame + "?" + qpGameID + "=1-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=v2-5-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + typeFourCorners},
+			},
+		},
+		{
+			name:           "check board - Custom",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeCustom+"&"+qpPattern+"="+patternBColumnID, nil),
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerContentType: {contentTypeHTML},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=v2-5-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + typeCustom + "&" + qpPattern + "=" + patternBColumnID + "&" + qpBingo},
+			},
+		},
+		{
+			name:           "check board - no free cell",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-5-"+middleRowGameIDNumbers+"&"+qpBoardID+"="+noFreeCellBoardID+"&"+qpType+"="+typeHasLine, nil),
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerContentType: {contentTypeHTML},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=v2-5-" + middleRowGameIDNumbers + "&" + qpBoardID + "=" + noFreeCellBoardID + "&" + qpType + "=" + typeHasLine + "&" + qpBingo},
+			},
+		},
+		{
+			name:           "check board - no free cell (center not drawn)",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-4-"+middleRowGameIDNumbers+"&"+qpBoardID+"="+noFreeCellBoardID+"&"+qpType+"="+typeHasLine, nil),
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerContentType: {contentTypeHTML},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=v2-4-" + middleRowGameIDNumbers + "&" + qpBoardID + "=" + noFreeCellBoardID + "&" + qpType + "=" + typeHasLine},
+			},
+		},
+		{
+			name:           "check board - card in rules game",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"="+rulesGame3ID+"&"+qpBoardID+"="+card3x3n10ID+"&"+qpType+"="+typeHasLine, nil),
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerContentType: {contentTypeHTML},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=" + rulesGame3ID + "&" + qpBoardID + "=" + card3x3n10ID + "&" + qpType + "=" + typeHasLine + "&" + qpBingo},
+			},
+		},
+		{
+			name:           "check board - card in rules game (not filled)",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"="+rulesGame3ID+"&"+qpBoardID+"="+card3x3n10ID+"&"+qpType+"="+typeIsFilled, nil),
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerContentType: {contentTypeHTML},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=" + rulesGame3ID + "&" + qpBoardID + "=" + card3x3n10ID + "&" + qpType + "=" + typeIsFilled},
+			},
+		},
+		{
+			name:           "check board - card with other rules",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"="+rulesGame3ID+"&"+qpBoardID+"=3x3n11.ASASASA&"+qpType+"="+typeHasLine, nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "check board - classic board in rules game",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"="+rulesGame3ID+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeHasLine, nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "check board - classic board in 90-ball game",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"="+game90ID+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeHasLine, nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "check board - card for FourCorners",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"="+rulesGame3ID+"&"+qpBoardID+"="+card3x3n10ID+"&"+qpType+"="+typeFourCorners, nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "check board - Custom (false)",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-4-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeCustom+"&"+qpPattern+"="+patternBColumnID, nil),
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerContentType: {contentTypeHTML},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=v2-4-" + board1257894001IDNumbers + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + typeCustom + "&" + qpPattern + "=" + patternBColumnID},
+			},
+		},
+		{
+			name:           "get game with checked board",
+			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeHasLine+"&"+qpBingo, nil),
+			wantStatusCode: 200,
+			wantHeader:     htmlContentTypeHeader,
+		},
+		{
+			name:           "get game with custom pattern",
+			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpPattern+"="+patternBColumnID, nil),
+			wantStatusCode: 200,
+			wantHeader:     htmlContentTypeHeader,
+		},
+		{
+			name:           "set custom pattern",
+			r:              httptest.NewRequest(methodGet, urlPathGamePattern+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&cell=0&cell=1&cell=2&cell=3&cell=4", nil),
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerContentType: {contentTypeHTML},
+				headerLocation:    {urlPathGame + "?" + qpGameID + "=v2-5-" + board1257894001IDNumbers + "&" + qpPattern + "=" + patternBColumnID},
 			},
 		},
 		{
@@ -300,6 +853,59 @@ var (
 				headerLocation: {urlPathGameBoard + "?" + qpBoardID + "=" + board1257894001ID + "&" + qpBarcodeFormat + "=anything"},
 			},
 		},
+		{
+			name:           "create board without free cell",
+			r:              httptest.NewRequest(methodPost, urlPathGameBoard, strings.NewReader("noFreeCell=on&freeSpace=FREE+SPACE")),
+			header:         formContentTypeHeader,
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerLocation: {urlPathGameBoard + "?" + qpBoardID + "=5x5n15.5zuTsMm6CSNkCztp0A&" + qpBarcodeFormat + "=&freeSpace=FREE+SPACE"},
+			},
+		},
+		{
+			name:           "create board - free space too long",
+			r:              httptest.NewRequest(methodPost, urlPathGameBoard, strings.NewReader("freeSpace=thirteen+chars")),
+			header:         formContentTypeHeader,
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "get board without free cell",
+			r:              httptest.NewRequest(methodGet, urlPathGameBoard+"?"+qpBoardID+"=5x5n15.5zuTsMm6CSNkCztp0A&freeSpace=FREE", nil),
+			Barcoder:       okMockBarcoder,
+			wantStatusCode: 200,
+			wantHeader:     htmlContentTypeHeader,
+		},
+		{
+			name:           "create board from seed",
+			r:              httptest.NewRequest(methodPost, urlPathGameBoard, strings.NewReader("seed=42")),
+			header:         formContentTypeHeader,
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerLocation: {urlPathGameBoard + "?" + qpBoardID + "=SyUGB8UNeAZxikgX&" + qpBarcodeFormat + "=&seed=42"},
+			},
+		},
+		{
+			name:           "create board - seed without free cell",
+			r:              httptest.NewRequest(methodPost, urlPathGameBoard, strings.NewReader("seed=42&noFreeCell=on")),
+			header:         formContentTypeHeader,
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "get board from seed",
+			r:              httptest.NewRequest(methodGet, urlPathGameBoard+"?"+qpBoardID+"=SyUGB8UNeAZxikgX&seed=42", nil),
+			Barcoder:       okMockBarcoder,
+			wantStatusCode: 200,
+			wantHeader:     htmlContentTypeHeader,
+		},
+		{
+			name:           "get board - not from seed",
+			r:              httptest.NewRequest(methodGet, urlPathGameBoard+"?"+qpBoardID+"="+board1257894001ID+"&seed=42", nil),
+			Barcoder:       okMockBarcoder,
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
 		{
 			name:           "get board by id",
 			r:              httptest.NewRequest(methodGet, urlPathGameBoard+"?"+qpBoardID+"="+board1257894001ID, nil),
@@ -307,6 +913,13 @@ var (
 			wantStatusCode: 200,
 			wantHeader:     htmlContentTypeHeader,
 		},
+		{
+			name:           "get board replayed in game",
+			r:              httptest.NewRequest(methodGet, urlPathGameBoard+"?"+qpBoardID+"="+board1257894001ID+"&"+qpGameID+"=v2-24-"+board1257894001IDNumbers, nil),
+			Barcoder:       okMockBarcoder,
+			wantStatusCode: 200,
+			wantHeader:     htmlContentTypeHeader,
+		},
 		{
 			name:           "help",
 			r:              httptest.NewRequest(methodGet, urlPathHelp, nil),
@@ -329,20 +942,61 @@ var (
 				headerLocation: {urlPathGame + "?" + qpGameID + "=0"},
 			},
 		},
+		{
+			name:           "create 80-ball game",
+			r:              httptest.NewRequest(methodPost, urlPathGame, strings.NewReader("balls=80")),
+			header:         formContentTypeHeader,
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerLocation: {urlPathGame + "?" + qpGameID + "=v2-0-AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"},
+			},
+		},
+		{
+			name:           "create rules game",
+			r:              httptest.NewRequest(methodPost, urlPathGame, strings.NewReader("rules=3x3n10")),
+			header:         formContentTypeHeader,
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerLocation: {urlPathGame + "?" + qpGameID + "=3x3n10.v2-0-AAAAAAAAAAAAAAAAAAA"},
+			},
+		},
+		{
+			name:           "create game - bad balls",
+			r:              httptest.NewRequest(methodPost, urlPathGame, strings.NewReader("balls=eighty")),
+			header:         formContentTypeHeader,
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
 		{
 			name:      "draw number",
 			time:      func() string { return "the_past_a" },
 			gameInfos: append(make([]gameInfo, 0, 10), gameInfo{ID: "1"}, gameInfo{ID: "2"}, gameInfo{ID: "3"}),
 			wantGameInfos: []gameInfo{{
-				ID:          "9-" + board1257894001IDNumbers,
+				ID:          "v2-9-" + board1257894001IDNumbers,
 				ModTime:     "the_past_a",
 				NumbersLeft: 66,
 			}, {ID: "1"}, {ID: "2"}, {ID: "3"}},
-			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=8-"+board1257894001IDNumbers)),
+			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=v2-8-"+board1257894001IDNumbers)),
 			header:         formContentTypeHeader,
 			wantStatusCode: 303,
 			wantHeader: http.Header{
-				headerLocation: {urlPathGame + "?" + qpGameID + "=9-" + board1257894001IDNumbers},
+				headerLocation: {urlPathGame + "?" + qpGameID + "=v2-9-" + board1257894001IDNumbers},
+			},
+		},
+		{
+			name:      "draw number (legacy game id)",
+			time:      func() string { return "the_past_a" },
+			gameInfos: make([]gameInfo, 0, 10),
+			wantGameInfos: []gameInfo{{
+				ID:          "v2-9-" + board1257894001IDNumbers,
+				ModTime:     "the_past_a",
+				NumbersLeft: 66,
+			}},
+			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=8-"+board1257894001LegacyIDNumbers)),
+			header:         formContentTypeHeader,
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerLocation: {urlPathGame + "?" + qpGameID + "=v2-9-" + board1257894001IDNumbers},
 			},
 		},
 		{
@@ -350,22 +1004,65 @@ var (
 			time:      func() string { return "the_past_b" },
 			gameInfos: []gameInfo{{ID: "1"}, {ID: "2"}, {ID: "3"}},
 			wantGameInfos: []gameInfo{{
-				ID:          "9-" + board1257894001IDNumbers,
+				ID:          "v2-9-" + board1257894001IDNumbers,
 				ModTime:     "the_past_b",
 				NumbersLeft: 66,
 			}, {ID: "1"}, {ID: "2"}},
-			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=8-"+board1257894001IDNumbers)),
+			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=v2-8-"+board1257894001IDNumbers)),
+			header:         formContentTypeHeader,
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerLocation: {urlPathGame + "?" + qpGameID + "=v2-9-" + board1257894001IDNumbers},
+			},
+		},
+		{
+			name:      "draw number (keeps custom pattern)",
+			time:      func() string { return "the_past_c" },
+			gameInfos: []gameInfo{{ID: "1"}},
+			wantGameInfos: []gameInfo{{
+				ID:          "v2-9-" + board1257894001IDNumbers,
+				ModTime:     "the_past_c",
+				NumbersLeft: 66,
+			}},
+			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=v2-8-"+board1257894001IDNumbers+"&"+qpPattern+"="+patternBColumnID)),
 			header:         formContentTypeHeader,
 			wantStatusCode: 303,
 			wantHeader: http.Header{
-				headerLocation: {urlPathGame + "?" + qpGameID + "=9-" + board1257894001IDNumbers},
+				headerLocation: {urlPathGame + "?" + qpGameID + "=v2-9-" + board1257894001IDNumbers + "&" + qpPattern + "=" + patternBColumnID},
 			},
 		},
 		{
 			name:           "draw number - do not change game infos if all numbers are drawn",
 			gameInfos:      append(make([]gameInfo, 0, 10), gameInfo{ID: "1"}, gameInfo{ID: "2"}, gameInfo{ID: "3"}),
 			wantGameInfos:  append(make([]gameInfo, 0, 10), gameInfo{ID: "1"}, gameInfo{ID: "2"}, gameInfo{ID: "3"}),
-			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=75-"+board1257894001IDNumbers)),
+			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=v2-75-"+board1257894001IDNumbers)),
+			header:         formContentTypeHeader,
+			wantStatusCode: 304,
+			wantHeader:     http.Header{},
+		},
+		{
+			name:      "undo draw",
+			time:      func() string { return "the_past_a" },
+			gameInfos: append(make([]gameInfo, 0, 10), gameInfo{ID: "1"}),
+			wantGameInfos: []gameInfo{{
+				ID:          "v2-7-" + board1257894001IDNumbers,
+				ModTime:     "the_past_a",
+				NumbersLeft: 68,
+				Undone:      "I 16",
+				Reason:      "drew twice",
+			}, {ID: "1"}},
+			r:              httptest.NewRequest(methodPost, urlPathGameUndoDraw, strings.NewReader(qpGameID+"=v2-8-"+board1257894001IDNumbers+"&reason=+drew+twice+&"+qpPattern+"="+patternBColumnID)),
+			header:         formContentTypeHeader,
+			wantStatusCode: 303,
+			wantHeader: http.Header{
+				headerLocation: {urlPathGame + "?" + qpGameID + "=v2-7-" + board1257894001IDNumbers + "&" + qpPattern + "=" + patternBColumnID},
+			},
+		},
+		{
+			name:           "undo draw - do not change game infos if no numbers are drawn",
+			gameInfos:      append(make([]gameInfo, 0, 10), gameInfo{ID: "1"}),
+			wantGameInfos:  append(make([]gameInfo, 0, 10), gameInfo{ID: "1"}),
+			r:              httptest.NewRequest(methodPost, urlPathGameUndoDraw, strings.NewReader(qpGameID+"=0&reason=oops")),
 			header:         formContentTypeHeader,
 			wantStatusCode: 304,
 			wantHeader:     http.Header{},
@@ -381,6 +1078,39 @@ var (
 				headerContentDisposition: {"attachment; filename=bingo-boards.zip"},
 			},
 		},
+		{
+			name:           "create boards without free cells",
+			r:              httptest.NewRequest(methodPost, urlPathGameBoards, strings.NewReader("n=2&noFreeCell=on")),
+			header:         formContentTypeHeader,
+			Barcoder:       okMockBarcoder,
+			wantStatusCode: 200,
+			wantHeader: http.Header{
+				headerContentType:        {"application/zip"},
+				headerContentDisposition: {"attachment; filename=bingo-boards.zip"},
+			},
+		},
+		{
+			name:           "create boards from seed",
+			r:              httptest.NewRequest(methodPost, urlPathGameBoards, strings.NewReader("n=2&seed=42")),
+			header:         formContentTypeHeader,
+			Barcoder:       okMockBarcoder,
+			wantStatusCode: 200,
+			wantHeader: http.Header{
+				headerContentType:        {"application/zip"},
+				headerContentDisposition: {"attachment; filename=bingo-boards.zip"},
+			},
+		},
+		{
+			name:           "create boards with minimum distance",
+			r:              httptest.NewRequest(methodPost, urlPathGameBoards, strings.NewReader("n=5&minDistance=12")),
+			header:         formContentTypeHeader,
+			Barcoder:       okMockBarcoder,
+			wantStatusCode: 200,
+			wantHeader: http.Header{
+				headerContentType:        {"application/zip"},
+				headerContentDisposition: {"attachment; filename=bingo-boards.zip"},
+			},
+		},
 		{
 			name:           "get game - bad id",
 			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"="+badID, nil),
@@ -405,15 +1135,87 @@ var (
 			wantStatusCode: 400,
 			wantHeader:     errorHeader,
 		},
+		{
+			name:           "check board - readable id mistyped",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpBoardID+"=DTC2H-8T21S-MNA42-8PJZ&"+qpType+"="+typeHasLine, nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
 		{
 			name:           "check board - bad board id",
-			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+badID+"&"+qpType+"="+typeHasLine, nil),
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+badID+"&"+qpType+"="+typeHasLine, nil),
 			wantStatusCode: 400,
 			wantHeader:     errorHeader,
 		},
 		{
 			name:           "check board - bad check type",
-			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+badID, nil),
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+badID, nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "check board - Custom with bad pattern id",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeCustom+"&"+qpPattern+"="+badID, nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "check board - HasLine with bad pattern id (pattern is added to redirect)",
+			r:              httptest.NewRequest(methodGet, urlPathGameCheckBoard+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeHasLine+"&"+qpPattern+"="+url.QueryEscape(patternBColumnID+"&bingo"), nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "get game - checked board has bad id",
+			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+badID+"&"+qpType+"="+typeHasLine, nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "get game - checked board has bad check type",
+			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+badID, nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "get game - checked board in 90-ball game",
+			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"="+game90ID+"&"+qpBoardID+"="+board1257894001ID+"&"+qpType+"="+typeHasLine, nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "get game - bad pattern id",
+			r:              httptest.NewRequest(methodGet, urlPathGame+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&"+qpPattern+"="+badID, nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "set custom pattern - bad game id",
+			r:              httptest.NewRequest(methodGet, urlPathGamePattern+"?"+qpGameID+"="+badID+"&cell=0", nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "set custom pattern - bad cell",
+			r:              httptest.NewRequest(methodGet, urlPathGamePattern+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers+"&cell=B", nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "set custom pattern - no cells",
+			r:              httptest.NewRequest(methodGet, urlPathGamePattern+"?"+qpGameID+"=v2-5-"+board1257894001IDNumbers, nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "get board - bad game id",
+			r:              httptest.NewRequest(methodGet, urlPathGameBoard+"?"+qpBoardID+"="+board1257894001ID+"&"+qpGameID+"="+badID, nil),
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "get board - 90-ball game",
+			r:              httptest.NewRequest(methodGet, urlPathGameBoard+"?"+qpBoardID+"="+board1257894001ID+"&"+qpGameID+"="+game90ID, nil),
 			wantStatusCode: 400,
 			wantHeader:     errorHeader,
 		},
@@ -441,7 +1243,7 @@ var (
 			name:           "draw number - no form content type header (cannot parse game id)",
 			gameInfos:      []gameInfo{{}},
 			wantGameInfos:  []gameInfo{{}},
-			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=8-"+board1257894001IDNumbers)),
+			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=v2-8-"+board1257894001IDNumbers)),
 			wantStatusCode: 400,
 			wantHeader:     errorHeader,
 		},
@@ -452,6 +1254,41 @@ var (
 			wantStatusCode: 400,
 			wantHeader:     errorHeader,
 		},
+		{
+			name:           "draw number - bad pattern",
+			r:              httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=v2-8-"+board1257894001IDNumbers+"&"+qpPattern+"="+url.QueryEscape(patternBColumnID+"&bingo"))),
+			header:         formContentTypeHeader,
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "undo draw - bad pattern",
+			r:              httptest.NewRequest(methodPost, urlPathGameUndoDraw, strings.NewReader(qpGameID+"=v2-8-"+board1257894001IDNumbers+"&reason=oops&"+qpPattern+"="+url.QueryEscape(patternBColumnID+"#x"))),
+			header:         formContentTypeHeader,
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "undo draw - missing reason",
+			r:              httptest.NewRequest(methodPost, urlPathGameUndoDraw, strings.NewReader(qpGameID+"=v2-8-"+board1257894001IDNumbers+"&reason=+")),
+			header:         formContentTypeHeader,
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "undo draw - reason too long",
+			r:              httptest.NewRequest(methodPost, urlPathGameUndoDraw, strings.NewReader(qpGameID+"=v2-8-"+board1257894001IDNumbers+"&reason="+strings.Repeat("x", 101))),
+			header:         formContentTypeHeader,
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "undo draw - bad game id",
+			r:              httptest.NewRequest(methodPost, urlPathGameUndoDraw, strings.NewReader(qpGameID+"="+badID+"&reason=oops")),
+			header:         formContentTypeHeader,
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
 		{
 			name:           "create boards - no form content type header (missing number)",
 			r:              httptest.NewRequest(methodPost, urlPathGameBoards, strings.NewReader("n=5")),
@@ -479,6 +1316,27 @@ var (
 			wantStatusCode: 400,
 			wantHeader:     errorHeader,
 		},
+		{
+			name:           "create boards - bad minimum distance",
+			r:              httptest.NewRequest(methodPost, urlPathGameBoards, strings.NewReader("n=2&minDistance=many")),
+			header:         formContentTypeHeader,
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "create boards - not unique enough",
+			r:              httptest.NewRequest(methodPost, urlPathGameBoards, strings.NewReader("n=5&minDistance=24")),
+			header:         formContentTypeHeader,
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
+		{
+			name:           "create boards from seed - not unique enough",
+			r:              httptest.NewRequest(methodPost, urlPathGameBoards, strings.NewReader("n=2&seed=42&minDistance=24")),
+			header:         formContentTypeHeader,
+			wantStatusCode: 400,
+			wantHeader:     errorHeader,
+		},
 		{
 			name:           "create boards - Barcoder error",
 			r:              httptest.NewRequest(methodPost, urlPathGameBoards, strings.NewReader("n=1")),
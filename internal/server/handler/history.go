@@ -0,0 +1,40 @@
+package handler
+
+import "sync"
+
+// gameHistory is the stack of the infos of the most recently changed games, newest first.
+// It is safe for concurrent use: the infos are locked while they are read or changed.
+type gameHistory struct {
+	mu    sync.Mutex
+	infos []gameInfo
+}
+
+// newGameHistory creates a history that keeps the infos of up to count games.
+func newGameHistory(count int) *gameHistory {
+	return &gameHistory{
+		infos: make([]gameInfo, 0, count),
+	}
+}
+
+// add puts the gameInfo on the top of the stack.  If the stack is full, the last item is discarded.
+func (gh *gameHistory) add(gi gameInfo) {
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+	if cap(gh.infos) < 1 && len(gh.infos) == 0 {
+		gh.infos = make([]gameInfo, 1)
+	}
+	if cap(gh.infos) > len(gh.infos) {
+		gh.infos = append(gh.infos, gameInfo{}) // increase length
+	}
+	copy(gh.infos[1:], gh.infos) // shift right, overwriting last
+	gh.infos[0] = gi             // set first
+}
+
+// list copies the game infos, newest first, so they can be read while other games are added.
+func (gh *gameHistory) list() []gameInfo {
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+	infos := make([]gameInfo, len(gh.infos))
+	copy(infos, gh.infos)
+	return infos
+}
@@ -0,0 +1,53 @@
+package handler
+
+import "sync"
+
+type (
+	// gameLocks serializes the changes to stored games, keyed by game code.
+	// A stored game is read, changed, and updated while its lock is held, so concurrent draws do not overwrite each other.
+	gameLocks struct {
+		mu    sync.Mutex
+		locks map[string]*gameLock
+	}
+	// gameLock is the lock of a stored game and the number of requests that hold or wait for it.
+	gameLock struct {
+		sync.Mutex
+		users int
+	}
+)
+
+// lock waits to hold the lock of the game with the code.
+// The unlock function must be called when the game is updated.
+func (gl *gameLocks) lock(code string) (unlock func()) {
+	gl.mu.Lock()
+	if gl.locks == nil {
+		gl.locks = make(map[string]*gameLock)
+	}
+	l, ok := gl.locks[code]
+	if !ok {
+		l = new(gameLock)
+		gl.locks[code] = l
+	}
+	l.users++
+	gl.mu.Unlock()
+	l.Lock()
+	return func() {
+		l.Unlock()
+		gl.mu.Lock()
+		defer gl.mu.Unlock()
+		l.users--
+		if l.users == 0 {
+			delete(gl.locks, code)
+		}
+	}
+}
+
+// lockGame holds the lock of the stored game if the id is the code of a stored game, so it can be read and updated without other changes.
+// The unlock function must be called when the game is updated.  It does nothing if the game is not stored.
+func (h handler) lockGame(gameID string) (unlock func()) {
+	code, ok := h.gameCode(gameID)
+	if !ok {
+		return func() {}
+	}
+	return h.gameLocks.lock(code)
+}
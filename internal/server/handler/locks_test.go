@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
+)
+
+func TestGameLocks(t *testing.T) {
+	var gl gameLocks
+	unlock := gl.lock("K7QF")
+	unlockOther := gl.lock("AB2C") // other games are not blocked
+	unlockOther()
+	locked := make(chan struct{})
+	go func() {
+		unlock2 := gl.lock("K7QF")
+		close(locked)
+		unlock2()
+	}()
+	select {
+	case <-locked:
+		t.Fatalf("wanted second lock of game to wait until the first is unlocked")
+	case <-time.After(10 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("wanted second lock of game after the first is unlocked")
+	}
+	gl.mu.Lock()
+	defer gl.mu.Unlock()
+	if len(gl.locks) != 0 {
+		t.Errorf("wanted locks of games to be removed when they are not used: %v", gl.locks)
+	}
+}
+
+func TestHandlerConcurrentDraws(t *testing.T) {
+	store := &mockGameStore{
+		games: map[string]string{
+			"K7QF": "0",
+		},
+	}
+	h := &handler{
+		games:     slowGameStore{store},
+		dealer:    bingo.NewDealer(1257894001),
+		events:    new(gameChannels),
+		gameLocks: new(gameLocks),
+		gameInfos: newGameHistory(10),
+	}
+	h.Handler = newMux(h)
+	const n = 20
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"=K7QF"))
+			r.Header = formContentTypeHeader
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if want, got := 303, w.Code; want != got {
+				t.Errorf("status codes not equal: wanted %v, got %v", want, got)
+			}
+		}()
+	}
+	wg.Wait()
+	gameID, err := store.Get("K7QF")
+	if err != nil {
+		t.Fatalf("getting stored game: %v", err)
+	}
+	g, err := bingo.GameFromID(gameID)
+	switch {
+	case err != nil:
+		t.Errorf("getting game from stored id %q: %v", gameID, err)
+	case len(g.DrawnNumbers()) != n:
+		t.Errorf("wanted each concurrent draw to draw a number of the stored game: wanted %v drawn, got %v", n, len(g.DrawnNumbers()))
+	}
+}
+
+// slowGameStore waits before getting games so concurrent requests read the same game if they are not serialized.
+type slowGameStore struct {
+	*mockGameStore
+}
+
+// Get waits before finding the game with the code.
+func (s slowGameStore) Get(code string) (string, error) {
+	time.Sleep(time.Millisecond)
+	return s.mockGameStore.Get(code)
+}
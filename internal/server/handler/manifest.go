@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// manifestFileName is the name of the file in zips of boards that lists the ids of the boards in the batch.
+	manifestFileName = "manifest.txt"
+	// maxManifestBoards is the most boards that can be read from a manifest, the same as the most boards that can be created at once.
+	maxManifestBoards = 1000
+)
+
+// writeManifest writes the board ids of the batch, one per line.
+func writeManifest(w io.Writer, boardIDs []string) error {
+	for _, id := range boardIDs {
+		if _, err := fmt.Fprintln(w, id); err != nil {
+			return fmt.Errorf("writing board id %q: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// readManifest reads the board ids of the batch, ignoring blank lines.
+func readManifest(r io.Reader) ([]string, error) {
+	var boardIDs []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		id := strings.TrimSpace(s.Text())
+		if len(id) == 0 {
+			continue
+		}
+		if len(boardIDs) == maxManifestBoards {
+			return nil, fmt.Errorf("manifest has more than %v boards", maxManifestBoards)
+		}
+		boardIDs = append(boardIDs, id)
+	}
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("reading manifest: %v", err)
+	}
+	if len(boardIDs) == 0 {
+		return nil, errors.New("manifest has no boards")
+	}
+	return boardIDs, nil
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteManifest(t *testing.T) {
+	var w bytes.Buffer
+	boardIDs := []string{"5zuTsMm6CTZAs7ad", "toxxg-ZbqBo4dGq8"}
+	if err := writeManifest(&w, boardIDs); err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	if want, got := "5zuTsMm6CTZAs7ad\ntoxxg-ZbqBo4dGq8\n", w.String(); want != got {
+		t.Errorf("manifests not equal:\nwanted: %q\ngot:    %q", want, got)
+	}
+}
+
+func TestWriteManifestError(t *testing.T) {
+	w := errWriter{errors.New("mock write error")}
+	if err := writeManifest(w, []string{"5zuTsMm6CTZAs7ad"}); err == nil {
+		t.Errorf("wanted write error")
+	}
+}
+
+func TestReadManifest(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		r := strings.NewReader("5zuTsMm6CTZAs7ad\r\n\n  toxxg-ZbqBo4dGq8  \n")
+		want := []string{"5zuTsMm6CTZAs7ad", "toxxg-ZbqBo4dGq8"}
+		got, err := readManifest(r)
+		switch {
+		case err != nil:
+			t.Errorf("unwanted error: %v", err)
+		case !reflect.DeepEqual(want, got):
+			t.Errorf("board ids not equal:\nwanted: %q\ngot:    %q", want, got)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			manifest string
+		}{
+			{"empty", ""},
+			{"only blank lines", "\n \n\t\n"},
+			{"too many boards", strings.Repeat("5zuTsMm6CTZAs7ad\n", maxManifestBoards+1)},
+			{"line too long", strings.Repeat("A", 1<<17)},
+		}
+		for i, test := range tests {
+			r := strings.NewReader(test.manifest)
+			if _, err := readManifest(r); err == nil {
+				t.Errorf("test %v (%v): wanted error", i, test.name)
+			}
+		}
+	})
+}
@@ -2,8 +2,12 @@ package handler
 
 import (
 	"bytes"
+	"errors"
 	"image"
 	"net/http"
+	"sync"
+
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
 )
 
 // mockBarcoder always returns the image and error.
@@ -11,11 +15,13 @@ type mockBarcoder struct {
 	image.Image
 	err        error
 	lastFormat string
+	lastText   string
 }
 
 // Barcode returns the image and error set in the struct.
 func (m *mockBarcoder) Barcode(format string, boardID string, width, height int) (image.Image, error) {
 	m.lastFormat = format
+	m.lastText = boardID
 	return m.Image, m.err
 }
 
@@ -43,3 +49,83 @@ func (m *mockResponseWriter) WriteHeader(statusCode int) {
 	m.headerWrittenFirst = !m.writeCalled
 	m.statusCode = statusCode
 }
+
+// errWriter always returns the error when writing.
+type errWriter struct {
+	err error
+}
+
+// Write returns the error set in the struct.
+func (w errWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+// mockGameStore is a GameStore that keeps games in a map.  It is safe for concurrent use.
+type mockGameStore struct {
+	mu        sync.Mutex
+	games     map[string]string
+	nextCode  string
+	createErr error
+	updateErr error
+}
+
+// Create saves the game with the next code set in the struct.
+func (s *mockGameStore) Create(gameID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.createErr != nil {
+		return "", s.createErr
+	}
+	s.games[s.nextCode] = gameID
+	return s.nextCode, nil
+}
+
+// Get finds the game with the code in the map.
+func (s *mockGameStore) Get(code string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	gameID, ok := s.games[code]
+	if !ok {
+		return "", errors.New("unknown code")
+	}
+	return gameID, nil
+}
+
+// Update changes the game with the code in the map.
+func (s *mockGameStore) Update(code, gameID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.updateErr != nil {
+		return s.updateErr
+	}
+	s.games[code] = gameID
+	return nil
+}
+
+// mockBoardRegistry is a BoardRegistry that keeps the ids of boards in a map.
+type mockBoardRegistry struct {
+	boardIDs    map[string]struct{}
+	registerErr error
+}
+
+// Issued determines if the board id is in the map.
+func (r *mockBoardRegistry) Issued(boardID string) (bool, error) {
+	_, ok := r.boardIDs[boardID]
+	return ok, nil
+}
+
+// RegisterNew adds the board ids to the map if none of them are in it.
+func (r *mockBoardRegistry) RegisterNew(boardIDs []string) error {
+	if r.registerErr != nil {
+		return r.registerErr
+	}
+	for _, boardID := range boardIDs {
+		if _, ok := r.boardIDs[boardID]; ok {
+			return bingo.ErrBoardIssued
+		}
+	}
+	for _, boardID := range boardIDs {
+		r.boardIDs[boardID] = struct{}{}
+	}
+	return nil
+}
@@ -1,27 +1,38 @@
 package handler
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+)
 
 // Mux is http Handler that maps methods to paths to handlers.
 type Mux map[string]map[string]http.HandlerFunc
 
+// apiPathPrefix starts the paths of the JSON api, which writes errors as JSON.
+const apiPathPrefix = "/api/v1/"
+
 // ServeHTTP serves to the path for the method of the request on the handler if such a Handler exists.
 func (m Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	methodHandlers, ok := m[r.Method]
 	if !ok {
-		httpError(w, http.StatusMethodNotAllowed)
+		httpError(w, r, http.StatusMethodNotAllowed)
 		return
 	}
 	h, ok := methodHandlers[r.URL.Path]
 	if !ok {
-		httpError(w, http.StatusNotFound)
+		httpError(w, r, http.StatusNotFound)
 		return
 	}
 	h.ServeHTTP(w, r)
 }
 
 // httpError writes the message for the statusCode to the response.
-func httpError(w http.ResponseWriter, statusCode int) {
+// The message is written as a JSON error for requests to the JSON api.
+func httpError(w http.ResponseWriter, r *http.Request, statusCode int) {
 	message := http.StatusText(statusCode)
+	if strings.HasPrefix(r.URL.Path, apiPathPrefix) {
+		jsonErrors{}.write(w, statusCode, message)
+		return
+	}
 	http.Error(w, message, statusCode)
 }
@@ -10,8 +10,11 @@ func TestMuxServeHTTP(t *testing.T) {
 	for i, test := range muxTests {
 		w := httptest.NewRecorder()
 		test.Mux.ServeHTTP(w, test.Request)
-		if want, got := test.wantStatusCode, w.Code; want != got {
-			t.Errorf("test %v (%v): status codes not equal: wanted %v, got %v", i, test.name, want, got)
+		switch {
+		case test.wantStatusCode != w.Code:
+			t.Errorf("test %v (%v): status codes not equal: wanted %v, got %v", i, test.name, test.wantStatusCode, w.Code)
+		case len(test.wantContentType) != 0 && test.wantContentType != w.Header().Get(headerContentType):
+			t.Errorf("test %v (%v): content types not equal: wanted %q, got %q", i, test.name, test.wantContentType, w.Header().Get(headerContentType))
 		}
 	}
 }
@@ -23,8 +26,9 @@ var (
 	muxTests = []struct {
 		Mux
 		*http.Request
-		name           string
-		wantStatusCode int
+		name            string
+		wantStatusCode  int
+		wantContentType string
 	}{
 		{
 			name:           "empty mux",
@@ -38,6 +42,27 @@ var (
 			Request:        httptest.NewRequest(methodGet, "/a", nil),
 			wantStatusCode: 404,
 		},
+		{
+			name:            "api page not found",
+			Mux:             Mux{methodGet: {"/api/v1/game": okHandler}},
+			Request:         httptest.NewRequest(methodGet, "/api/v1/games", nil),
+			wantStatusCode:  404,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "api bad method",
+			Mux:             Mux{methodGet: {"/api/v1/game": okHandler}},
+			Request:         httptest.NewRequest("DELETE", "/api/v1/game", nil),
+			wantStatusCode:  405,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "page not found (plain text)",
+			Mux:             Mux{methodGet: {"/b": okHandler}},
+			Request:         httptest.NewRequest(methodGet, "/api/v2/game", nil),
+			wantStatusCode:  404,
+			wantContentType: "text/plain; charset=utf-8",
+		},
 		{
 			name:           "get to post endpoint",
 			Mux:            Mux{methodGet: {"/": okHandler}},
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
+	"github.com/jacobpatterson1549/bitty-bingo/internal/server/handler/play"
+	"github.com/jacobpatterson1549/bitty-bingo/internal/server/handler/websocket"
+)
+
+// playGame upgrades the request to a WebSocket connection for a player to play a stored game with a board.
+// The messages of the connection are described by the play package.
+func (h handler) playGame(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		message := fmt.Sprintf("upgrading to websocket connection: %v", err)
+		h.badRequest(w, message)
+		return
+	}
+	defer conn.Close()
+	var join play.Message
+	if err := conn.ReadJSON(&join); err != nil {
+		return
+	}
+	code, b, joined, err := h.joinGame(join)
+	if err != nil {
+		conn.WriteJSON(playError(err))
+		return
+	}
+	events, unsubscribe := h.events.subscribe(code)
+	defer unsubscribe()
+	if err := conn.WriteJSON(joined); err != nil {
+		return
+	}
+	messages := make(chan play.Message)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(messages)
+		for {
+			var m play.Message
+			if err := conn.ReadJSON(&m); err != nil {
+				return
+			}
+			select {
+			case messages <- m:
+			case <-done:
+				return
+			}
+		}
+	}()
+	for {
+		var reply play.Message
+		select {
+		case <-r.Context().Done():
+			return
+		case m, ok := <-messages:
+			if !ok {
+				return
+			}
+			reply = h.handlePlayMessage(code, join.BoardID, *b, m)
+		case e := <-events:
+			reply = playEvent(e)
+		}
+		if err := conn.WriteJSON(reply); err != nil {
+			return
+		}
+	}
+}
+
+// joinGame validates the join message, returning the code of the stored game, the board of the player, and the joined message.
+func (h handler) joinGame(join play.Message) (code string, b *bingo.Board, joined *play.Message, err error) {
+	if join.Type != play.Join {
+		return "", nil, nil, fmt.Errorf("wanted %v message, got %q", play.Join, join.Type)
+	}
+	code, ok := h.gameCode(join.GameID)
+	if !ok {
+		return "", nil, nil, fmt.Errorf("game %q does not have a code to play", join.GameID)
+	}
+	g, err := h.storedGame(code)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	gameID, err := g.ID() // the stored id of committed games has their order
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("getting game id: %v", err)
+	}
+	boardID, _, _ := h.boardSigner.verify(join.BoardID)
+	b, err = bingo.BoardFromID(boardID)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("getting board: %v", err)
+	}
+	drawnNumbers := g.DrawnNumbers()
+	joined = &play.Message{
+		Type:         play.Joined,
+		GameID:       h.signer.sign(gameID),
+		BoardID:      join.BoardID,
+		DrawnNumbers: make([]int, len(drawnNumbers)),
+		NumbersLeft:  g.NumbersLeft(),
+	}
+	for i, n := range drawnNumbers {
+		joined.DrawnNumbers[i] = n.Value()
+	}
+	return code, b, joined, nil
+}
+
+// handlePlayMessage creates the reply to a message from the player of the board.
+// Claims are checked on the current state of the game and sent to the other players, flagged if the board is counterfeit.
+func (h handler) handlePlayMessage(code, boardID string, b bingo.Board, m play.Message) play.Message {
+	if m.Type != play.Claim {
+		err := fmt.Errorf("unknown message type %q", m.Type)
+		return playError(err)
+	}
+	p, err := findPattern(m.Pattern, m.PatternID)
+	if err != nil {
+		return playError(err)
+	}
+	g, err := h.storedGame(code)
+	switch {
+	case err != nil:
+		return playError(err)
+	case !g.Classic():
+		return playError(errNotClassicGame)
+	}
+	result := b.Check(*g, *p)
+	_, _, authErr := h.boardSigner.verify(boardID)
+	h.publishClaim(code, boardID, m.Pattern, result.Bingo, authErr != nil)
+	reply := play.Message{
+		Type:      play.Result,
+		BoardID:   boardID,
+		Pattern:   m.Pattern,
+		PatternID: m.PatternID,
+		Bingo:     result.Bingo,
+		Line:      result.LineName(),
+		Number:    result.Number.Value(),
+	}
+	for i := range b {
+		if result.Cells.Has(i) {
+			reply.Cells = append(reply.Cells, i)
+		}
+	}
+	return reply
+}
+
+// storedGame gets the current state of the game with the code.
+func (h handler) storedGame(code string) (*bingo.Game, error) {
+	if h.games == nil {
+		return nil, errors.New("games are not stored")
+	}
+	gameID, err := h.games.Get(code)
+	if err != nil {
+		return nil, fmt.Errorf("getting stored game: %v", err)
+	}
+	g, err := bingo.GameFromID(gameID)
+	if err != nil {
+		return nil, fmt.Errorf("getting game from stored id: %v", err)
+	}
+	return g, nil
+}
+
+// playEvent converts the event of a game to a message for players.
+func playEvent(e gameEvent) play.Message {
+	switch data := e.Data.(type) {
+	case drawEvent:
+		return play.Message{
+			Type:        play.Draw,
+			GameID:      data.GameID,
+			Number:      data.Number,
+			Label:       data.Label,
+			NumbersLeft: data.NumbersLeft,
+		}
+	case undoEvent:
+		return play.Message{
+			Type:        play.Undo,
+			GameID:      data.GameID,
+			Number:      data.Number,
+			Label:       data.Label,
+			NumbersLeft: data.NumbersLeft,
+			Reason:      data.Reason,
+		}
+	case claimEvent:
+		return play.Message{
+			Type:    play.Claim,
+			BoardID: data.BoardID,
+			Pattern: data.Type,
+			Bingo:   data.Bingo,
+		}
+	case endEvent:
+		return play.Message{
+			Type:   play.End,
+			GameID: data.GameID,
+		}
+	}
+	err := fmt.Errorf("unknown %v event", e.Type)
+	return playError(err)
+}
+
+// playError creates a message for the error.
+func playError(err error) play.Message {
+	return play.Message{
+		Type:  play.Error,
+		Error: err.Error(),
+	}
+}
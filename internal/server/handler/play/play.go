@@ -0,0 +1,143 @@
+// Package play is the message protocol for players to play games over WebSocket connections.
+//
+// A player connects to the /game/play endpoint of the server and sends a join message with the code of a stored game and the id of their board.
+// The server responds with a joined message that has the numbers drawn so far, or an error message if the game or board is not valid.
+// While the player is connected, the server sends:
+//   - a draw message when a number is drawn,
+//   - an undo message when the last drawn number is put back, so it is no longer daubed,
+//   - a claim message when any board is checked for a BINGO in the game,
+//   - an end message when the last number is drawn.
+//
+// The player can send a claim message with the name of a pattern (or Custom and a pattern id) to check their board.
+// The server responds with a result message that has the winning cells, or an error message if the pattern is not valid.
+//
+// Messages are JSON text messages, such as {"type":"join","gameID":"K7QF","boardID":"5zuTsMm6CTZAs7ad"}.
+package play
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jacobpatterson1549/bitty-bingo/internal/server/handler/websocket"
+)
+
+type (
+	// Message is sent between players and the server.  The Type determines which other fields are set.
+	Message struct {
+		// Type is the kind of message.
+		Type string `json:"type"`
+		// GameID is the code of the game, for join messages, or the id of the game after numbers are drawn or undone.
+		GameID string `json:"gameID,omitempty"`
+		// BoardID is the board of the player, for join and claim messages.
+		BoardID string `json:"boardID,omitempty"`
+		// Pattern is the name of the pattern to check the board for, for claim and result messages.
+		Pattern string `json:"pattern,omitempty"`
+		// PatternID is the id of the custom pattern to check the board for, if the Pattern is Custom.
+		PatternID string `json:"patternID,omitempty"`
+		// DrawnNumbers are the numbers that have been drawn when the player joins.
+		DrawnNumbers []int `json:"drawnNumbers,omitempty"`
+		// Number is the drawn number for draw messages, the number that was put back for undo messages, or the number that completed the pattern for result messages.
+		Number int `json:"number,omitempty"`
+		// Label is the text of the drawn number, such as "B 15".
+		Label string `json:"label,omitempty"`
+		// NumbersLeft is the amount of numbers that can still be drawn.
+		NumbersLeft int `json:"numbersLeft,omitempty"`
+		// Reason is why the last draw was undone, for undo messages.
+		Reason string `json:"reason,omitempty"`
+		// Bingo is whether the board has the pattern, for claim and result messages.
+		Bingo bool `json:"bingo,omitempty"`
+		// Line describes the winning line of a result, if the winning cells are a line.
+		Line string `json:"line,omitempty"`
+		// Cells are the indexes of the winning cells on the board of a result.
+		Cells []int `json:"cells,omitempty"`
+		// Error describes a problem with the previous message from the player.
+		Error string `json:"error,omitempty"`
+	}
+	// Client is a connection of a player to a game.
+	Client struct {
+		conn *websocket.Conn
+	}
+)
+
+// Types of messages.
+const (
+	// Join is sent by players to join a game with a board.
+	Join = "join"
+	// Joined is sent to players when they join a game.
+	Joined = "joined"
+	// Claim is sent by players to check their board for a pattern.  It is also sent to players when any board is checked.
+	Claim = "claim"
+	// Result is sent to a player with the check of their board.
+	Result = "result"
+	// Draw is sent to players when a number is drawn.
+	Draw = "draw"
+	// Undo is sent to players when the last drawn number is put back.
+	Undo = "undo"
+	// End is sent to players when all the numbers have been drawn.
+	End = "end"
+	// Error is sent to a player when their message could not be handled.
+	Error = "error"
+)
+
+// JoinGame connects to the play url of the server, joining the game with the board.
+// The joined message is returned with the client.
+func JoinGame(url, gameID, boardID string) (*Client, *Message, error) {
+	conn, err := websocket.Dial(url)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connecting to game: %v", err)
+	}
+	c := Client{
+		conn: conn,
+	}
+	join := Message{
+		Type:    Join,
+		GameID:  gameID,
+		BoardID: boardID,
+	}
+	if err := conn.WriteJSON(join); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("joining game: %v", err)
+	}
+	m, err := c.Next()
+	switch {
+	case err != nil:
+		conn.Close()
+		return nil, nil, fmt.Errorf("joining game: %v", err)
+	case m.Type != Joined:
+		conn.Close()
+		return nil, nil, fmt.Errorf("wanted %v message, got %v", Joined, m.Type)
+	}
+	return &c, m, nil
+}
+
+// Claim asks the server to check the board of the player for the pattern.
+// The result is received as a later message.
+func (c *Client) Claim(pattern, patternID string) error {
+	claim := Message{
+		Type:      Claim,
+		Pattern:   pattern,
+		PatternID: patternID,
+	}
+	if err := c.conn.WriteJSON(claim); err != nil {
+		return fmt.Errorf("sending claim: %v", err)
+	}
+	return nil
+}
+
+// Next reads the next message from the server.
+// Error messages are returned as errors.
+func (c *Client) Next() (*Message, error) {
+	var m Message
+	if err := c.conn.ReadJSON(&m); err != nil {
+		return nil, err
+	}
+	if m.Type == Error {
+		return nil, errors.New(m.Error)
+	}
+	return &m, nil
+}
+
+// Close leaves the game.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,62 @@
+package play
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jacobpatterson1549/bitty-bingo/internal/server/handler/websocket"
+)
+
+func TestJoinGame(t *testing.T) {
+	tests := []struct {
+		name   string
+		reply  Message
+		wantOk bool
+	}{
+		{"joined", Message{Type: Joined, DrawnNumbers: []int{7}}, true},
+		{"error", Message{Type: Error, Error: "unknown game"}, false},
+		{"wrong type", Message{Type: Draw}, false},
+	}
+	for i, test := range tests {
+		joins := make(chan Message, 1)
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := websocket.Upgrade(w, r)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			var join Message
+			conn.ReadJSON(&join)
+			joins <- join
+			conn.WriteJSON(test.reply)
+		})
+		s := httptest.NewServer(h)
+		c, joined, err := JoinGame(s.URL, "K7QF", "board-id")
+		wantJoin := Message{Type: Join, GameID: "K7QF", BoardID: "board-id"}
+		switch {
+		case !test.wantOk:
+			if err == nil {
+				t.Errorf("test %v (%v): wanted error", i, test.name)
+				c.Close()
+			}
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+		default:
+			if got := <-joins; got.Type != wantJoin.Type || got.GameID != wantJoin.GameID || got.BoardID != wantJoin.BoardID {
+				t.Errorf("test %v (%v): join messages not equal:\nwanted: %+v\ngot:    %+v", i, test.name, wantJoin, got)
+			}
+			if len(joined.DrawnNumbers) != 1 {
+				t.Errorf("test %v (%v): wanted joined message to be returned, got %+v", i, test.name, joined)
+			}
+			c.Close()
+		}
+		s.Close()
+	}
+}
+
+func TestJoinGameNoServer(t *testing.T) {
+	if _, _, err := JoinGame("ws://127.0.0.1:1", "K7QF", "board-id"); err == nil {
+		t.Errorf("wanted error joining game without server")
+	}
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
+	"github.com/jacobpatterson1549/bitty-bingo/internal/server/handler/play"
+)
+
+func TestHandlerPlayGame(t *testing.T) {
+	store := &mockGameStore{
+		games: map[string]string{
+			"K7QF": "v2-4-" + board1257894001IDNumbers,
+		},
+	}
+	h := &handler{
+		games: store,
+	}
+	s := httptest.NewServer(h)
+	defer s.Close()
+	playURL := "ws" + strings.TrimPrefix(s.URL, "http") + urlPathGamePlay
+	t.Run("invalid joins", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			gameID  string
+			boardID string
+		}{
+			{"legacy game id", "4-" + board1257894001LegacyIDNumbers, board1257894001ID},
+			{"v2 game id", "v2-4-" + board1257894001IDNumbers, board1257894001ID},
+			{"unknown code", "ZZZZ", board1257894001ID},
+			{"bad board id", "K7QF", badID},
+		}
+		for i, test := range tests {
+			if c, _, err := play.JoinGame(playURL, test.gameID, test.boardID); err == nil {
+				c.Close()
+				t.Errorf("test %v (%v): wanted error", i, test.name)
+			}
+		}
+	})
+	t.Run("not a websocket request", func(t *testing.T) {
+		res, err := http.Get(s.URL + urlPathGamePlay)
+		if err != nil {
+			t.Fatalf("requesting play: %v", err)
+		}
+		res.Body.Close()
+		if want, got := 400, res.StatusCode; want != got {
+			t.Errorf("status codes not equal: wanted %v, got %v", want, got)
+		}
+	})
+	c, joined, err := play.JoinGame(playURL, "K7QF", board1257894001ID)
+	if err != nil {
+		t.Fatalf("joining game: %v", err)
+	}
+	defer c.Close()
+	wantJoined := &play.Message{
+		Type:         play.Joined,
+		GameID:       "v2-4-" + board1257894001IDNumbers,
+		BoardID:      board1257894001ID,
+		DrawnNumbers: []int{15, 8, 4, 12},
+		NumbersLeft:  71,
+	}
+	if !reflect.DeepEqual(wantJoined, joined) {
+		t.Errorf("joined messages not equal:\nwanted: %+v\ngot:    %+v", wantJoined, joined)
+	}
+	form := url.Values{qpGameID: {"K7QF"}}
+	if _, err := http.PostForm(s.URL+urlPathGameDrawNumber, form); err != nil {
+		t.Fatalf("drawing number: %v", err)
+	}
+	if err := c.Claim(typeHasLine, ""); err != nil {
+		t.Fatalf("claiming bingo: %v", err)
+	}
+	want := []play.Message{
+		{
+			Type:        play.Draw,
+			GameID:      "v2-5-" + board1257894001IDNumbers,
+			Number:      10,
+			Label:       "B 10",
+			NumbersLeft: 70,
+		},
+		{
+			Type:    play.Result,
+			BoardID: board1257894001ID,
+			Pattern: typeHasLine,
+			Bingo:   true,
+			Line:    "B column",
+			Number:  10,
+			Cells:   []int{0, 1, 2, 3, 4},
+		},
+		{
+			Type:    play.Claim,
+			BoardID: board1257894001ID,
+			Pattern: typeHasLine,
+			Bingo:   true,
+		},
+	}
+	for i, w := range want {
+		got, err := c.Next()
+		switch {
+		case err != nil:
+			t.Fatalf("message %v: reading: %v", i, err)
+		case !reflect.DeepEqual(w, *got):
+			t.Errorf("message %v: not equal:\nwanted: %+v\ngot:    %+v", i, w, *got)
+		}
+	}
+	if err := c.Claim(badID, ""); err != nil {
+		t.Fatalf("claiming bad pattern: %v", err)
+	}
+	if _, err := c.Next(); err == nil {
+		t.Errorf("wanted error message for unknown pattern")
+	}
+}
+
+func TestHandlerPlayCommittedGame(t *testing.T) {
+	g, err := bingo.NewGame(bingo.Balls75)
+	if err != nil {
+		t.Fatalf("creating game: %v", err)
+	}
+	if err := g.Commit(); err != nil {
+		t.Fatalf("committing game: %v", err)
+	}
+	g.DrawNumber()
+	secretID, err := g.SecretID()
+	if err != nil {
+		t.Fatalf("getting secret id: %v", err)
+	}
+	publicID, err := g.ID()
+	if err != nil {
+		t.Fatalf("getting public id: %v", err)
+	}
+	store := &mockGameStore{
+		games: map[string]string{
+			"K7QF": secretID,
+		},
+	}
+	h := &handler{
+		games: store,
+	}
+	s := httptest.NewServer(h)
+	defer s.Close()
+	playURL := "ws" + strings.TrimPrefix(s.URL, "http") + urlPathGamePlay
+	c, joined, err := play.JoinGame(playURL, "K7QF", board1257894001ID)
+	if err != nil {
+		t.Fatalf("joining game: %v", err)
+	}
+	defer c.Close()
+	if want, got := publicID, joined.GameID; want != got {
+		t.Errorf("wanted public id of committed game when joined, not the id with its salt and order:\nwanted: %q\ngot:    %q", want, got)
+	}
+}
+
+func TestHandlerPlayOtherBallsGame(t *testing.T) {
+	store := &mockGameStore{
+		games: map[string]string{
+			"K7QF": game90ID,
+		},
+	}
+	h := &handler{
+		games: store,
+	}
+	s := httptest.NewServer(h)
+	defer s.Close()
+	playURL := "ws" + strings.TrimPrefix(s.URL, "http") + urlPathGamePlay
+	c, _, err := play.JoinGame(playURL, "K7QF", board1257894001ID)
+	if err != nil {
+		t.Fatalf("joining game: %v", err)
+	}
+	defer c.Close()
+	if err := c.Claim(typeHasLine, ""); err != nil {
+		t.Fatalf("claiming bingo: %v", err)
+	}
+	if m, err := c.Next(); err == nil {
+		t.Errorf("wanted error message for claim of classic board in 90-ball game, got %+v", m)
+	}
+}
+
+func TestPlayEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		gameEvent
+		want play.Message
+	}{
+		{
+			name:      "end",
+			gameEvent: gameEvent{"end", endEvent{GameID: "75-x"}},
+			want:      play.Message{Type: play.End, GameID: "75-x"},
+		},
+		{
+			name:      "undo",
+			gameEvent: gameEvent{"undo", undoEvent{GameID: "74-x", Number: 75, Label: "O 75", NumbersLeft: 1, Reason: "oops"}},
+			want:      play.Message{Type: play.Undo, GameID: "74-x", Number: 75, Label: "O 75", NumbersLeft: 1, Reason: "oops"},
+		},
+		{
+			name:      "unknown",
+			gameEvent: gameEvent{"other", 7},
+			want:      play.Message{Type: play.Error, Error: "unknown other event"},
+		},
+	}
+	for i, test := range tests {
+		if got := playEvent(test.gameEvent); !reflect.DeepEqual(test.want, got) {
+			t.Errorf("test %v (%v): messages not equal:\nwanted: %+v\ngot:    %+v", i, test.name, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
+)
+
+type (
+	// gameSigner is the secret key that the ids of games are signed with so ids that are made by hand can be detected.
+	// Ids are not signed if the key is empty.
+	gameSigner []byte
+	// boardSigner is the secret key that the authenticity codes of boards are made with so cards that are made by hand can be detected.
+	// Boards do not have codes if the key is empty.
+	boardSigner []byte
+)
+
+const (
+	// gameSignatureSeparator separates the id of a game from its signature.
+	// It is not used in game ids or base64 url encoding.
+	gameSignatureSeparator = "~"
+	// gameSignatureLength is the number of bytes of the HMAC-SHA256 of game ids that are kept in signatures.
+	gameSignatureLength = 12
+	// boardCodeSeparator separates the id of a board from its authenticity code.
+	// It is not used in board ids or authenticity codes.
+	boardCodeSeparator = "~"
+	// boardIssueSeparator separates the issue number of an authenticity code from its signature.
+	boardIssueSeparator = "."
+	// boardSignatureLength is the number of bytes of the HMAC-SHA256 of board ids and issue numbers that are kept in authenticity codes.
+	// It is short so codes can be printed on the free space and typed by hand.
+	boardSignatureLength = 6
+)
+
+// sign adds the signature of the game id to the end of it.  The id is not changed if the signer does not have a key.
+func (key gameSigner) sign(gameID string) string {
+	if len(key) == 0 {
+		return gameID
+	}
+	return gameID + gameSignatureSeparator + key.signature(gameID)
+}
+
+// verify removes the signature from the end of the signed game id, returning the game id.
+// An error is returned if the signer has a key and the id is not signed or the signature is not for the id.
+// Signatures are removed without being checked if the signer does not have a key, so ids can be unsigned or signed.
+func (key gameSigner) verify(signedID string) (gameID string, err error) {
+	gameID, signature, signed := strings.Cut(signedID, gameSignatureSeparator)
+	switch {
+	case len(key) == 0:
+		return gameID, nil
+	case !signed:
+		return "", errors.New("game id is not signed")
+	case !hmac.Equal([]byte(signature), []byte(key.signature(gameID))):
+		return "", errors.New("game id signature is not valid")
+	}
+	return gameID, nil
+}
+
+// signature is the truncated HMAC-SHA256 of the game id, encoded as unpadded base64 url text.
+func (key gameSigner) signature(gameID string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(gameID))
+	sum := mac.Sum(nil)
+	return base64.RawURLEncoding.EncodeToString(sum[:gameSignatureLength])
+}
+
+// sign adds the authenticity code of the board id and issue number to the end of it.  The id is not changed if the signer does not have a key.
+// The issue number is the batch or event that the board is printed for.
+func (key boardSigner) sign(boardID string, issue uint64) string {
+	return signedBoardID(boardID, key.code(boardID, issue))
+}
+
+// verify removes the authenticity code from the end of the signed board id, returning the board id and the code.
+// The code is checked against the base64 id of the board, so it can be typed after the readable id of the board.
+// The code is empty if the signer does not have a key, since it cannot be checked.
+// An error is returned with the board id if the signer has a key and the id does not have a code or the code is not for the board, meaning the board is counterfeit.
+func (key boardSigner) verify(signedID string) (boardID, code string, err error) {
+	boardID, code, signed := strings.Cut(signedID, boardCodeSeparator)
+	if len(key) == 0 {
+		return boardID, "", nil
+	}
+	if !signed {
+		return boardID, "", errors.New("board does not have an authenticity code")
+	}
+	if b, err := bingo.BoardFromID(boardID); err == nil {
+		boardID, _ = b.ID() // the board is valid, so it has an id
+	}
+	issueText, _, _ := strings.Cut(code, boardIssueSeparator)
+	issue, err := strconv.ParseUint(issueText, 10, 64)
+	if err != nil || !hmac.Equal([]byte(code), []byte(key.code(boardID, issue))) {
+		return boardID, "", errors.New("board authenticity code is not valid")
+	}
+	return boardID, code, nil
+}
+
+// code is the authenticity code of the board for the issue number.  The code is empty if the signer does not have a key.
+// It is the issue number and the truncated HMAC-SHA256 of the board id and issue number, encoded as unpadded base64 url text.
+func (key boardSigner) code(boardID string, issue uint64) string {
+	if len(key) == 0 {
+		return ""
+	}
+	issueText := strconv.FormatUint(issue, 10)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(boardID + boardCodeSeparator + issueText))
+	sum := mac.Sum(nil)
+	return issueText + boardIssueSeparator + base64.RawURLEncoding.EncodeToString(sum[:boardSignatureLength])
+}
+
+// signedBoardID adds the authenticity code to the end of the board id.  The id is not changed if the code is empty.
+func signedBoardID(boardID, code string) string {
+	if len(code) == 0 {
+		return boardID
+	}
+	return boardID + boardCodeSeparator + code
+}
@@ -0,0 +1,398 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
+)
+
+func TestGameSigner(t *testing.T) {
+	key := gameSigner("secret-key")
+	gameID := "v2-8-" + board1257894001IDNumbers
+	signedID := key.sign(gameID)
+	_, signature, _ := strings.Cut(signedID, gameSignatureSeparator)
+	tests := []struct {
+		name    string
+		signer  gameSigner
+		id      string
+		want    string
+		wantErr bool
+	}{
+		{"signed", key, signedID, gameID, false},
+		{"not signed", key, gameID, "", true},
+		{"altered id", key, "v2-9-" + board1257894001IDNumbers + gameSignatureSeparator + signature, "", true},
+		{"altered signature", key, gameID + gameSignatureSeparator + "AAAAAAAAAAAAAAAA", "", true},
+		{"other key", gameSigner("other-key"), signedID, "", true},
+		{"no key, not signed", nil, gameID, gameID, false},
+		{"no key, signed", nil, signedID, gameID, false},
+	}
+	for i, test := range tests {
+		got, err := test.signer.verify(test.id)
+		switch {
+		case test.wantErr:
+			if err == nil {
+				t.Errorf("test %v (%v): wanted error", i, test.name)
+			}
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+		case test.want != got:
+			t.Errorf("test %v (%v): game ids not equal: wanted %q, got %q", i, test.name, test.want, got)
+		}
+	}
+	if want, got := gameID, gameSigner(nil).sign(gameID); want != got {
+		t.Errorf("wanted id to not be signed without a key: wanted %q, got %q", want, got)
+	}
+	if want, got := len(gameID)+1+16, len(signedID); want != got {
+		t.Errorf("wanted 16 character signature: wanted id length %v, got %v: %q", want, got, signedID)
+	}
+}
+
+func TestHandlerSignedGameIDs(t *testing.T) {
+	key := gameSigner("secret-key")
+	gameID := "v2-8-" + board1257894001IDNumbers
+	signedID := key.sign(gameID)
+	signedAfterID := key.sign("v2-9-" + board1257894001IDNumbers)
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		wantStatusCode int
+		wantLocation   string
+		wantBodyPart   string
+	}{
+		{
+			name:           "create game",
+			method:         methodPost,
+			target:         urlPathGame,
+			wantStatusCode: 303,
+			wantLocation:   urlPathGame + "?" + qpGameID + "=" + key.sign("0"),
+		},
+		{
+			name:           "get signed game",
+			method:         methodGet,
+			target:         urlPathGame + "?" + qpGameID + "=" + signedID,
+			wantStatusCode: 200,
+			wantBodyPart:   "Numbers left: 67",
+		},
+		{
+			name:           "get unsigned game",
+			method:         methodGet,
+			target:         urlPathGame + "?" + qpGameID + "=" + gameID,
+			wantStatusCode: 400,
+		},
+		{
+			name:           "draw number",
+			method:         methodPost,
+			target:         urlPathGameDrawNumber,
+			body:           qpGameID + "=" + signedID,
+			wantStatusCode: 303,
+			wantLocation:   urlPathGame + "?" + qpGameID + "=" + signedAfterID,
+		},
+		{
+			name:           "draw number - forged game",
+			method:         methodPost,
+			target:         urlPathGameDrawNumber,
+			body:           qpGameID + "=v2-9-" + board1257894001IDNumbers + strings.TrimPrefix(signedID, gameID),
+			wantStatusCode: 400,
+		},
+		{
+			name:           "api draw number",
+			method:         methodPost,
+			target:         urlPathAPIGameDrawNumber,
+			body:           qpGameID + "=" + signedID,
+			wantStatusCode: 200,
+			wantBodyPart:   `"id":"` + signedAfterID + `"`,
+		},
+		{
+			name:           "api check unsigned game",
+			method:         methodGet,
+			target:         urlPathAPIBoardCheck + "?" + qpGameID + "=" + gameID + "&" + qpBoardID + "=" + board1257894001ID + "&" + qpType + "=" + typeHasLine,
+			wantStatusCode: 400,
+		},
+	}
+	for i, test := range tests {
+		r := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+		r.Header = formContentTypeHeader
+		w := httptest.NewRecorder()
+		h := handler{
+			signer: key,
+		}
+		h.ServeHTTP(w, r)
+		switch {
+		case test.wantStatusCode != w.Code:
+			t.Errorf("test %v (%v): status codes not equal: wanted %v, got %v: %v", i, test.name, test.wantStatusCode, w.Code, w.Body.String())
+		case test.wantLocation != w.Header().Get("Location"):
+			t.Errorf("test %v (%v): locations not equal:\nwanted: %q\ngot:    %q", i, test.name, test.wantLocation, w.Header().Get("Location"))
+		case !strings.Contains(w.Body.String(), test.wantBodyPart):
+			t.Errorf("test %v (%v): wanted body to contain %q:\n%v", i, test.name, test.wantBodyPart, w.Body.String())
+		}
+	}
+	t.Run("games list", func(t *testing.T) {
+		h := handler{
+			signer:    key,
+			gameInfos: newGameHistory(1),
+		}
+		r := httptest.NewRequest(methodPost, urlPathGameDrawNumber, strings.NewReader(qpGameID+"="+signedID))
+		r.Header = formContentTypeHeader
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		if len(h.gameInfos.infos) != 1 || h.gameInfos.infos[0].ID != signedAfterID {
+			t.Errorf("wanted signed id of game in games list, got %v", h.gameInfos.infos)
+		}
+	})
+	t.Run("stored game code", func(t *testing.T) {
+		h := handler{
+			signer: key,
+			games: &mockGameStore{
+				games: map[string]string{
+					"K7QF": gameID,
+				},
+			},
+		}
+		r := httptest.NewRequest(methodGet, urlPathAPIGame+"?"+qpGameID+"=K7QF", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if want, got := `"id":"`+signedID+`","code":"K7QF"`, w.Body.String(); w.Code != 200 || !strings.Contains(got, want) {
+			t.Errorf("wanted stored game to be found by code and its id to be signed (%v):\n%v", want, got)
+		}
+	})
+}
+
+func TestBoardSigner(t *testing.T) {
+	key := boardSigner("secret-key")
+	signedID := key.sign(board1257894001ID, 7)
+	_, code, _ := strings.Cut(signedID, boardCodeSeparator)
+	otherIssueCode := key.code(board1257894001ID, 8)
+	tests := []struct {
+		name     string
+		signer   boardSigner
+		id       string
+		wantCode string
+		wantErr  bool
+	}{
+		{"signed", key, signedID, code, false},
+		{"not signed", key, board1257894001ID, "", true},
+		{"altered id", key, "4kXCOHldCpztBiBe" + boardCodeSeparator + code, "", true},
+		{"altered issue", key, board1257894001ID + boardCodeSeparator + "8" + strings.TrimPrefix(code, "7"), "", true},
+		{"other issue", key, board1257894001ID + boardCodeSeparator + otherIssueCode, otherIssueCode, false},
+		{"no issue", key, board1257894001ID + boardCodeSeparator + strings.TrimPrefix(code, "7"), "", true},
+		{"other key", boardSigner("other-key"), signedID, "", true},
+		{"no key, not signed", nil, board1257894001ID, "", false},
+		{"no key, signed", nil, signedID, "", false},
+	}
+	for i, test := range tests {
+		boardID, gotCode, err := test.signer.verify(test.id)
+		switch {
+		case boardID != strings.Split(test.id, boardCodeSeparator)[0]:
+			t.Errorf("test %v (%v): wanted board id without code, got %q", i, test.name, boardID)
+		case test.wantErr:
+			if err == nil {
+				t.Errorf("test %v (%v): wanted error", i, test.name)
+			}
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+		case test.wantCode != gotCode:
+			t.Errorf("test %v (%v): codes not equal: wanted %q, got %q", i, test.name, test.wantCode, gotCode)
+		}
+	}
+	if want, got := board1257894001ID, boardSigner(nil).sign(board1257894001ID, 7); want != got {
+		t.Errorf("wanted board id to not have a code without a key: wanted %q, got %q", want, got)
+	}
+	if want, got := len("7.")+8, len(code); want != got {
+		t.Errorf("wanted issue number and 8 character signature in code: wanted length %v, got %v: %q", want, got, code)
+	}
+}
+
+func TestHandlerBoardCodes(t *testing.T) {
+	key := boardSigner("secret-key")
+	signedID := key.sign(board1257894001ID, 7)
+	forgedID := board1257894001ID + boardCodeSeparator + "7.AAAAAAAA"
+	gameID := "v2-5-" + board1257894001IDNumbers
+	checkTarget := func(boardID string) string {
+		return urlPathGameCheckBoard + "?" + qpGameID + "=" + gameID + "&" + qpBoardID + "=" + boardID + "&" + qpType + "=" + typeHasLine
+	}
+	gameTarget := func(boardID string) string {
+		return urlPathGame + "?" + qpGameID + "=" + gameID + "&" + qpBoardID + "=" + boardID + "&" + qpType + "=" + typeHasLine
+	}
+	apiCheckTarget := func(boardID string) string {
+		return urlPathAPIBoardCheck + "?" + qpGameID + "=" + gameID + "&" + qpBoardID + "=" + boardID + "&" + qpType + "=" + typeHasLine
+	}
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		wantStatusCode int
+		wantLocation   string
+		wantBodyPart   string
+		wantNotPart    string
+	}{
+		{
+			name:           "create board",
+			method:         methodPost,
+			target:         urlPathGameBoard,
+			body:           "issue=7",
+			wantStatusCode: 303,
+			wantLocation:   urlPathGameBoard + "?" + qpBoardID + "=" + signedID + "&" + qpBarcodeFormat + "=",
+		},
+		{
+			name:           "create board with bad issue",
+			method:         methodPost,
+			target:         urlPathGameBoard,
+			body:           "issue=-7",
+			wantStatusCode: 400,
+		},
+		{
+			name:           "get signed board",
+			method:         methodGet,
+			target:         urlPathGameBoard + "?" + qpBoardID + "=" + signedID,
+			wantStatusCode: 200,
+			wantBodyPart:   `<tspan class="code">` + strings.TrimPrefix(signedID, board1257894001ID+boardCodeSeparator) + `</tspan>`,
+		},
+		{
+			name:           "get forged board",
+			method:         methodGet,
+			target:         urlPathGameBoard + "?" + qpBoardID + "=" + forgedID,
+			wantStatusCode: 200,
+			wantNotPart:    `class="code"`,
+		},
+		{
+			name:           "check signed board",
+			method:         methodGet,
+			target:         checkTarget(signedID),
+			wantStatusCode: 303,
+			wantLocation:   urlPathGame + "?" + qpGameID + "=" + gameID + "&" + qpBoardID + "=" + signedID + "&" + qpType + "=" + typeHasLine + "&" + qpBingo,
+		},
+		{
+			name:           "game with signed board",
+			method:         methodGet,
+			target:         gameTarget(signedID),
+			wantStatusCode: 200,
+			wantNotPart:    "COUNTERFEIT",
+		},
+		{
+			name:           "game with readable signed board",
+			method:         methodGet,
+			target:         gameTarget("DTC2H-8T21S-MNA24-8PJZ" + strings.TrimPrefix(signedID, board1257894001ID)),
+			wantStatusCode: 200,
+			wantNotPart:    "COUNTERFEIT",
+		},
+		{
+			name:           "game with unsigned board",
+			method:         methodGet,
+			target:         gameTarget(board1257894001ID),
+			wantStatusCode: 200,
+			wantBodyPart:   "COUNTERFEIT",
+		},
+		{
+			name:           "game with forged board",
+			method:         methodGet,
+			target:         gameTarget(forgedID),
+			wantStatusCode: 200,
+			wantBodyPart:   "COUNTERFEIT",
+		},
+		{
+			name:           "api check signed board",
+			method:         methodGet,
+			target:         apiCheckTarget(signedID),
+			wantStatusCode: 200,
+			wantNotPart:    "counterfeit",
+		},
+		{
+			name:           "api check forged board",
+			method:         methodGet,
+			target:         apiCheckTarget(forgedID),
+			wantStatusCode: 200,
+			wantBodyPart:   `"counterfeit":true`,
+		},
+		{
+			name:           "api create board",
+			method:         methodPost,
+			target:         urlPathAPIBoard,
+			body:           "issue=7",
+			wantStatusCode: 201,
+			wantBodyPart:   `"id":"` + signedID + `"`,
+		},
+		{
+			name:           "api create board with bad issue",
+			method:         methodPost,
+			target:         urlPathAPIBoard,
+			body:           "issue=-7",
+			wantStatusCode: 400,
+		},
+		{
+			name:           "api get signed board",
+			method:         methodGet,
+			target:         urlPathAPIBoard + "?" + qpBoardID + "=" + signedID,
+			wantStatusCode: 200,
+			wantBodyPart:   `"id":"` + signedID + `"`,
+		},
+		{
+			name:           "api create boards",
+			method:         methodPost,
+			target:         urlPathAPIBoards,
+			body:           "n=1&issue=7",
+			wantStatusCode: 201,
+			wantBodyPart:   `"ids":["` + signedID + `"]`,
+		},
+	}
+	for i, test := range tests {
+		r := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+		r.Header = formContentTypeHeader
+		w := httptest.NewRecorder()
+		h := handler{
+			dealer:      bingo.NewDealer(1257894001),
+			boardSigner: key,
+		}
+		h.ServeHTTP(w, r)
+		switch {
+		case test.wantStatusCode != w.Code:
+			t.Errorf("test %v (%v): status codes not equal: wanted %v, got %v: %v", i, test.name, test.wantStatusCode, w.Code, w.Body.String())
+		case test.wantLocation != w.Header().Get("Location"):
+			t.Errorf("test %v (%v): locations not equal:\nwanted: %q\ngot:    %q", i, test.name, test.wantLocation, w.Header().Get("Location"))
+		case !strings.Contains(w.Body.String(), test.wantBodyPart):
+			t.Errorf("test %v (%v): wanted body to contain %q:\n%v", i, test.name, test.wantBodyPart, w.Body.String())
+		case len(test.wantNotPart) != 0 && strings.Contains(w.Body.String(), test.wantNotPart):
+			t.Errorf("test %v (%v): wanted body to not contain %q:\n%v", i, test.name, test.wantNotPart, w.Body.String())
+		}
+	}
+	t.Run("zip", func(t *testing.T) {
+		barcoder := &mockBarcoder{
+			Image: image.NewGray16(image.Rect(0, 0, 1, 1)),
+		}
+		h := handler{
+			Barcoder:    barcoder,
+			dealer:      bingo.NewDealer(1257894001),
+			boardSigner: key,
+		}
+		r := httptest.NewRequest(methodPost, urlPathGameBoards, strings.NewReader("n=1&issue=7"))
+		r.Header = formContentTypeHeader
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		body := w.Body.Bytes()
+		z, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			t.Fatalf("reading zip file: %v", err)
+		}
+		f, err := z.Open("bingo_1.svg")
+		if err != nil {
+			t.Fatalf("opening board: %v", err)
+		}
+		defer f.Close()
+		svg, err := io.ReadAll(f)
+		switch {
+		case err != nil:
+			t.Errorf("reading board: %v", err)
+		case signedID != barcoder.lastText:
+			t.Errorf("wanted board id with code in bar code: wanted %q, got %q", signedID, barcoder.lastText)
+		case !strings.Contains(string(svg), strings.TrimPrefix(signedID, board1257894001ID+boardCodeSeparator)):
+			t.Errorf("wanted code on board: %s", svg)
+		}
+	})
+}
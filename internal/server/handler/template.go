@@ -2,6 +2,7 @@ package handler
 
 import (
 	"embed"
+	"fmt"
 	"html/template"
 	"io"
 
@@ -20,6 +21,9 @@ const (
 	indexTemplateName       = "index.html"
 	faviconTemplateName     = "favicon.svg"
 	boardExportTemplateName = "board.svg"
+	stripExportTemplateName = "strip.svg"
+	// maxFreeSpaceLength is the most characters of text that fit in the free space of a board.
+	maxFreeSpaceLength = 12
 )
 
 type (
@@ -36,18 +40,136 @@ type (
 	// gamePage contains the fields to render a gamePage page.
 	gamePage struct {
 		page
-		Game     bingo.Game
-		GameID   string
-		BoardID  string
+		gameCheck
+		Game   bingo.Game
+		GameID string
+		// GameCode is the short code of the game if it is stored.
+		GameCode string
+		// Patterns are the types of bingos that boards can be checked for.
+		Patterns []bingo.Pattern
+		// CheckType is the name of the pattern to check boards for by default.
+		CheckType string
+		// PatternID is the id of the custom pattern for the game, if any.
+		PatternID string
+		// PatternRows are the cells of the custom pattern editor, by row.
+		PatternRows [][]patternCell
+	}
+	// gameCheck is the result of checking a board in a game.
+	gameCheck struct {
+		// BoardID is the id of the checked board.
+		BoardID string
+		// HasBingo is whether the checked board has a BINGO.
 		HasBingo bool
+		// Counterfeit is whether boards are signed and the checked board does not have a valid authenticity code.
+		Counterfeit bool
+		// Board is the checked board with the drawn cells daubed, if it should be shown.
+		Board *boardPage
+		// Win is when the checked board first had the pattern in the game.
+		Win *bingo.Win
+	}
+	// verifyPage contains the fields to render the page to verify the commitment of a game.
+	verifyPage struct {
+		page
+		commitmentCheck
+	}
+	// commitmentCheck is a commitment of a game with the revealed salt and order of its numbers.
+	commitmentCheck struct {
+		// Commitment is the hash of the salt and order that was published when the game was created.
+		Commitment string
+		// Salt is the revealed salt of the game.
+		Salt string
+		// Order is the revealed order of all the numbers of the game.
+		Order string
+		// Drawn is the numbers that were drawn in the game, which should be the start of the order.
+		Drawn string
+		// Checked is whether the commitment was verified.
+		Checked bool
+		// Problem is why the commitment could not be verified, if it was checked.
+		Problem string
+	}
+	// winnersPage contains the fields to render the boards of a batch that have a BINGO.
+	winnersPage struct {
+		page
+		GameID     string
+		BoardCount int
+		Winners    []boardWinner
+	}
+	// boardWinner is a board in a batch that has a BINGO for some patterns.
+	boardWinner struct {
+		BoardID  string
+		Patterns []bingo.Pattern
+	}
+	// patternCell is a checkbox in the custom pattern editor.
+	patternCell struct {
+		Index   int
+		Checked bool
+		Free    bool
 	}
 	// boardPage contains the field to export a boardPage
 	boardPage struct {
 		page
 		Board   bingo.Board
 		BoardID string
+		// Code is the authenticity code of the board, if it is signed.
+		Code string
 		// Barcode is a base64 encoded png image of a bar code that should be placed in the free space in the middle of the board
+		// Boards without a free cell have the bar code below the numbers.
 		Barcode string
+		// FreeSpace is text that is shown in the free space instead of the bar code, if set.
+		FreeSpace string
+		// Seed is the seed the board was created from, if any.
+		Seed string
+		// Result is the outcome of checking the board, if it was checked.
+		Result *bingo.Result
+		// Daubs mark the drawn cells on the board.
+		Daubs []daub
+		// WinLine is drawn through the winning cells if they form a line.
+		WinLine *svgLine
+		// GameID is the id of the game the board was replayed in, if any.
+		GameID string
+		// GameCode is the short code of the game if it is stored, so the board can be updated as numbers are drawn.
+		GameCode string
+		// Game is the game the board is played in, if any.
+		Game *bingo.Game
+		// Cells are the called state of each cell on the board in the game, if any.
+		Cells []boardCell
+		// Wins are when the board first had each pattern in the game.
+		Wins []bingo.Win
+	}
+	// boardCell is a cell of a board played in a game.
+	boardCell struct {
+		Number bingo.Number
+		// Called is whether the number of the cell has been drawn.  The free cell is always called.
+		Called bool
+		// OneAway is whether drawing the number of the cell would complete a pattern.
+		OneAway bool
+	}
+	// daub is a mark on a drawn cell of a board.
+	daub struct {
+		X, Y    int
+		Winning bool
+		// OneAway marks an undrawn cell that would complete a pattern.
+		OneAway bool
+	}
+	// stripImage contains the fields to export a strip of 90-ball tickets.
+	stripImage struct {
+		Tickets []ticketImage
+	}
+	// ticketImage is a 90-ball ticket on a strip image, offset vertically by Y.
+	ticketImage struct {
+		ID    string
+		Y     int
+		Cells []ticketCell
+	}
+	// ticketCell is a cell of a 90-ball ticket image with the location of its top left corner.
+	// The number of blank cells is zero.
+	ticketCell struct {
+		X, Y   int
+		Number bingo.Number
+	}
+	// svgLine is a line segment on an svg image.
+	svgLine struct {
+		X1, Y1, X2, Y2 int
 	}
 )
 
@@ -70,20 +192,118 @@ func executeAboutTemplate(w io.Writer, favicon string) error {
 }
 
 // executeGameTemplate renders the game html page.
-func executeGameTemplate(w io.Writer, favicon string, g bingo.Game, gameID, boardID string, hasBingo bool) error {
+// The custom pattern is optional, when it is provided, it is checked by default.
+// Cards of games with rules are only checked for lines and being filled, so custom patterns are not used.
+// The gameCode is set if the game is stored.
+func executeGameTemplate(w io.Writer, favicon string, g bingo.Game, gameID, gameCode string, check gameCheck, custom *bingo.Pattern, patternID string) error {
+	patterns := bingo.Patterns()
+	checkType := patterns[0].Name
+	if g.Rules() != nil {
+		patterns = cardPatterns(patterns)
+		custom = nil
+	}
+	if custom != nil {
+		patterns = append(patterns, *custom)
+		checkType = custom.Name
+	}
 	p := gamePage{
 		page: page{
 			Name:    "game",
 			Favicon: favicon,
 		},
-		Game:     g,
-		GameID:   gameID,
-		BoardID:  boardID,
-		HasBingo: hasBingo,
+		gameCheck:   check,
+		Game:        g,
+		GameID:      gameID,
+		GameCode:    gameCode,
+		Patterns:    patterns,
+		CheckType:   checkType,
+		PatternID:   patternID,
+		PatternRows: patternRows(custom),
 	}
 	return embeddedTemplate.ExecuteTemplate(w, indexTemplateName, p)
 }
 
+// cardPatterns are the library patterns that cards with rules can be checked for.
+func cardPatterns(patterns []bingo.Pattern) []bingo.Pattern {
+	var cp []bingo.Pattern
+	for _, p := range patterns {
+		if p.Name == cardLinePattern || p.Name == cardFilledPattern {
+			cp = append(cp, p)
+		}
+	}
+	return cp
+}
+
+// patternRows creates the rows of cells for the pattern editor, checking the cells in the custom pattern.
+// The middle cell is always free.
+func patternRows(custom *bingo.Pattern) [][]patternCell {
+	var m bingo.Mask
+	if custom != nil {
+		for _, cm := range custom.Masks {
+			m |= cm
+		}
+	}
+	rows := make([][]patternCell, 5)
+	for r := range rows {
+		rows[r] = make([]patternCell, 5)
+		for c := range rows[r] {
+			i := c*5 + r
+			rows[r][c] = patternCell{
+				Index:   i,
+				Checked: m.Has(i),
+				Free:    i == 12,
+			}
+		}
+	}
+	return rows
+}
+
+// newCheckedBoardPage creates the data to render a board with the drawn cells of the result daubed.
+// The winning cells are highlighted, with a line through them if they form a line.
+func newCheckedBoardPage(b bingo.Board, boardID string, r bingo.Result) *boardPage {
+	p := boardPage{
+		Board:   b,
+		BoardID: boardID,
+		Result:  &r,
+	}
+	for i := range b {
+		if r.Drawn.Has(i) {
+			x, y := cellCenter(i)
+			d := daub{
+				X:       x,
+				Y:       y,
+				Winning: r.Cells.Has(i),
+			}
+			p.Daubs = append(p.Daubs, d)
+		}
+	}
+	if r.Bingo && r.Line != bingo.NoLine {
+		first, last := r.Index, r.Index // cell indexes
+		switch r.Line {
+		case bingo.Column:
+			first, last = r.Index*5, r.Index*5+4
+		case bingo.Row:
+			last = 20 + r.Index
+		case bingo.Diagonal:
+			first, last = 0, 24
+			if r.Index == 1 {
+				first, last = 4, 20
+			}
+		}
+		x1, y1 := cellCenter(first)
+		x2, y2 := cellCenter(last)
+		p.WinLine = &svgLine{x1, y1, x2, y2}
+	}
+	return &p
+}
+
+// cellCenter is the location of the middle of the cell on the board svg image.
+// The first row of the image has the column headers.
+func cellCenter(index int) (x, y int) {
+	c, r := index/5, index%5
+	return 50 + c*100, 150 + r*100
+}
+
 // executeGamesTemplate renders the games list html page.
 func executeGamesTemplate(w io.Writer, favicon string, gameInfos []gameInfo) error {
 	p := gamesPage{
@@ -96,30 +316,138 @@ func executeGamesTemplate(w io.Writer, favicon string, gameInfos []gameInfo) err
 	return embeddedTemplate.ExecuteTemplate(w, indexTemplateName, p)
 }
 
-// executeBoardTemplate renders the board on the html page.
-func executeBoardTemplate(w io.Writer, favicon string, b bingo.Board, boardID, barcode string) error {
-	p := boardPage{
+// executeVerifyTemplate renders the page to verify the commitment of a game.
+func executeVerifyTemplate(w io.Writer, favicon string, check commitmentCheck) error {
+	p := verifyPage{
 		page: page{
-			Name:    "board",
+			Name:    "verify",
 			Favicon: favicon,
 		},
-		Board:   b,
-		BoardID: boardID,
-		Barcode: barcode,
+		commitmentCheck: check,
+	}
+	return embeddedTemplate.ExecuteTemplate(w, indexTemplateName, p)
+}
+
+// executeWinnersTemplate renders the winning boards of a batch on the html page.
+func executeWinnersTemplate(w io.Writer, favicon string, gameID string, boardCount int, winners []boardWinner) error {
+	p := winnersPage{
+		page: page{
+			Name:    "winners",
+			Favicon: favicon,
+		},
+		GameID:     gameID,
+		BoardCount: boardCount,
+		Winners:    winners,
+	}
+	return embeddedTemplate.ExecuteTemplate(w, indexTemplateName, p)
+}
+
+// executeBoardTemplate renders the board of the page on the html page.
+// If the board is played in a game, the called cells are daubed, the cells that would complete a pattern are marked, and the wins of the board are shown.
+// The name and favicon of the page are set by the template.
+func executeBoardTemplate(w io.Writer, favicon string, p boardPage) error {
+	p.page = page{
+		Name:    "board",
+		Favicon: favicon,
+	}
+	if p.Game != nil {
+		p.daubGame(*p.Game)
 	}
 	return embeddedTemplate.ExecuteTemplate(w, indexTemplateName, p)
 }
 
+// daubGame sets the called state of the cells of the board in the game, daubing the called cells and marking the cells that are one away from a pattern.
+func (p *boardPage) daubGame(g bingo.Game) {
+	p.Game = &g
+	called := p.Board.Daubed(g)
+	oneAway := p.Board.OneAway(g, bingo.Patterns()...)
+	p.Cells = make([]boardCell, len(p.Board))
+	for i, n := range p.Board {
+		p.Cells[i] = boardCell{
+			Number:  n,
+			Called:  called.Has(i),
+			OneAway: oneAway.Has(i),
+		}
+		if called.Has(i) || oneAway.Has(i) {
+			x, y := cellCenter(i)
+			d := daub{
+				X:       x,
+				Y:       y,
+				OneAway: oneAway.Has(i),
+			}
+			p.Daubs = append(p.Daubs, d)
+		}
+	}
+}
+
 // executeBoardExportTemplate renders the board onto an svg image.
-func executeBoardExportTemplate(w io.Writer, b bingo.Board, boardID, barcode string) error {
+func executeBoardExportTemplate(w io.Writer, b bingo.Board, boardID, code, barcode, freeSpace, seed string) error {
 	data := boardPage{
-		Board:   b,
-		BoardID: boardID,
-		Barcode: barcode,
+		Board:     b,
+		BoardID:   boardID,
+		Code:      code,
+		Barcode:   barcode,
+		FreeSpace: freeSpace,
+		Seed:      seed,
 	}
 	return embeddedTemplate.ExecuteTemplate(w, boardExportTemplateName, data)
 }
 
+// Height is the height of the board svg image.  Boards without a free cell have an extra row below the numbers for the bar code and id.
+func (p boardPage) Height() int {
+	if p.Board.HasFreeCell() {
+		return 600
+	}
+	return 700
+}
+
+// ReadableID is the id of the board that is easy to type by hand, which is printed instead of the board id.
+// It is empty if the board is not valid, so the board id is printed.
+func (p boardPage) ReadableID() string {
+	id, err := p.Board.ReadableID()
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+// OneAwayNumbers are the numbers of the cells on the board that would complete a pattern in the game if drawn next.
+func (p boardPage) OneAwayNumbers() []bingo.Number {
+	var nums []bingo.Number
+	for _, c := range p.Cells {
+		if c.OneAway {
+			nums = append(nums, c.Number)
+		}
+	}
+	return nums
+}
+
+// executeStripExportTemplate renders the tickets of the 90-ball strip onto an svg image, one above another.
+func executeStripExportTemplate(w io.Writer, s bingo.Strip) error {
+	var data stripImage
+	for i, t := range s {
+		ticketID, err := t.ID()
+		if err != nil {
+			return fmt.Errorf("getting id of ticket #%v: %v", i+1, err)
+		}
+		ti := ticketImage{
+			ID: ticketID,
+			Y:  i * 210,
+		}
+		for j, n := range t {
+			c, r := j/bingo.TicketRows, j%bingo.TicketRows
+			tc := ticketCell{
+				X:      c * 60,
+				Y:      r * 60,
+				Number: n,
+			}
+			ti.Cells = append(ti.Cells, tc)
+		}
+		data.Tickets = append(data.Tickets, ti)
+	}
+	return embeddedTemplate.ExecuteTemplate(w, stripExportTemplateName, data)
+}
+
 // executeFaviconTemplate renders the favicon without line breaks.
 func executeFaviconTemplate(w io.Writer) error {
 	return embeddedTemplate.ExecuteTemplate(w, faviconTemplateName, nil)
@@ -2,6 +2,9 @@ package handler
 
 import (
 	"bytes"
+	"html"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -37,14 +40,20 @@ func TestExecuteGameTemplate(t *testing.T) {
 	}
 	var oneNumberDrawnGame bingo.Game
 	oneNumberDrawnGame.DrawNumber()
+	customPattern, err := bingo.CustomPattern(0, 4, 20, 24)
+	if err != nil {
+		t.Fatalf("creating custom pattern: %v", err)
+	}
 	tests :=
 		[]struct {
-			name     string
-			game     bingo.Game
-			boardID  string
-			hasBingo bool
-			want     string
-			negate   bool
+			name      string
+			game      bingo.Game
+			gameCode  string
+			check     gameCheck
+			custom    *bingo.Pattern
+			patternID string
+			want      string
+			negate    bool
 		}{
 			{
 				name: "game has drawn tile",
@@ -52,24 +61,22 @@ func TestExecuteGameTemplate(t *testing.T) {
 				want: "B 2",
 			},
 			{
-				name:    "checked board value",
-				game:    oneNumberDrawnGame,
-				boardID: "board_id_input_value",
-				want:    "board_id_input_value",
+				name:  "checked board value",
+				game:  oneNumberDrawnGame,
+				check: gameCheck{BoardID: "board_id_input_value"},
+				want:  "board_id_input_value",
 			},
 			{
-				name:     "checked board has no bingo",
-				game:     oneNumberDrawnGame,
-				boardID:  "board_id_input_value",
-				hasBingo: false,
-				want:     "No Bingo :(</a>",
+				name:  "checked board has no bingo",
+				game:  oneNumberDrawnGame,
+				check: gameCheck{BoardID: "board_id_input_value", HasBingo: false},
+				want:  "No Bingo :(</a>",
 			},
 			{
-				name:     "checked board has bingo",
-				game:     oneNumberDrawnGame,
-				boardID:  "board_id_input_value",
-				hasBingo: true,
-				want:     "BINGO !!!</a>",
+				name:  "checked board has bingo",
+				game:  oneNumberDrawnGame,
+				check: gameCheck{BoardID: "board_id_input_value", HasBingo: true},
+				want:  "BINGO !!!</a>",
 			},
 			{
 				name: "has previous number",
@@ -93,10 +100,113 @@ func TestExecuteGameTemplate(t *testing.T) {
 				game: oneNumberDrawnGame,
 				want: "Check Board",
 			},
+			{
+				name: "has pattern type to check",
+				game: oneNumberDrawnGame,
+				want: `value="PostageStamp"`,
+			},
+			{
+				name: "line checked by default",
+				game: oneNumberDrawnGame,
+				want: `value="HasLine" checked="true"`,
+			},
+			{
+				name:   "no custom pattern",
+				game:   oneNumberDrawnGame,
+				want:   `value="Custom"`,
+				negate: true,
+			},
+			{
+				name:      "custom pattern checked",
+				game:      oneNumberDrawnGame,
+				custom:    customPattern,
+				patternID: "custom-pattern-id",
+				want:      `value="Custom" checked="true"`,
+			},
+			{
+				name:      "custom pattern id kept",
+				game:      oneNumberDrawnGame,
+				custom:    customPattern,
+				patternID: "custom-pattern-id",
+				want:      `name="pattern" value="custom-pattern-id"`,
+			},
+			{
+				name:      "custom pattern cell checked in editor",
+				game:      oneNumberDrawnGame,
+				custom:    customPattern,
+				patternID: "custom-pattern-id",
+				want:      `value="20" aria-label="cell 20" checked="true"`,
+			},
+			{
+				name:  "checked board shown",
+				game:  oneNumberDrawnGame,
+				check: gameCheck{BoardID: "board-id", HasBingo: true, Board: newCheckedBoardPage(bingo.Board{}, "board-id", bingo.Result{Bingo: true, Line: bingo.Row, Index: 4, Cells: 1<<4 | 1<<9 | 1<<14 | 1<<19 | 1<<24, Drawn: 1<<4 | 1<<9 | 1<<14 | 1<<19 | 1<<24, Number: 9})},
+				want:  "Winning line: row 5",
+			},
+			{
+				name:  "checked board completing number shown",
+				game:  oneNumberDrawnGame,
+				check: gameCheck{BoardID: "board-id", HasBingo: true, Board: newCheckedBoardPage(bingo.Board{}, "board-id", bingo.Result{Bingo: true, Cells: 1, Drawn: 1, Number: 9})},
+				want:  "Completed by: B 9",
+			},
+			{
+				name:  "checked board has daubs",
+				game:  oneNumberDrawnGame,
+				check: gameCheck{BoardID: "board-id", Board: newCheckedBoardPage(bingo.Board{}, "board-id", bingo.Result{Drawn: 1 << 12})},
+				want:  `<circle cx="250" cy="350" r="45" class="daub" />`,
+			},
+			{
+				name:  "checked board first win",
+				game:  oneNumberDrawnGame,
+				check: gameCheck{BoardID: "board-id", HasBingo: true, Win: &bingo.Win{Draw: 12, Number: 64}},
+				want:  "First BINGO on draw 12: O 64",
+			},
+			{
+				name:   "checked board not sleeper",
+				game:   oneNumberDrawnGame,
+				check:  gameCheck{BoardID: "board-id", HasBingo: true, Win: &bingo.Win{Draw: 12, Number: 64}},
+				want:   "Sleeper",
+				negate: true,
+			},
+			{
+				name:  "checked board sleeper",
+				game:  oneNumberDrawnGame,
+				check: gameCheck{BoardID: "board-id", HasBingo: true, Win: &bingo.Win{Draw: 12, Number: 64, Sleeper: true}},
+				want:  "Sleeper",
+			},
+			{
+				name:     "game code",
+				game:     oneNumberDrawnGame,
+				gameCode: "K7QF",
+				want:     `Game code: <span>K7QF</span>`,
+			},
+			{
+				name:     "game code subscribes to events",
+				game:     oneNumberDrawnGame,
+				gameCode: "K7QF",
+				want:     `new EventSource(`,
+			},
+			{
+				name:   "no game code does not subscribe to events",
+				game:   oneNumberDrawnGame,
+				want:   `new EventSource(`,
+				negate: true,
+			},
+			{
+				name:   "no game code",
+				game:   oneNumberDrawnGame,
+				want:   `Game code:`,
+				negate: true,
+			},
+			{
+				name: "pattern editor free cell",
+				game: bingo.Game{},
+				want: `value="12" aria-label="cell 12" checked="true" disabled="true"`,
+			},
 		}
 	for i, test := range tests {
 		var w bytes.Buffer
-		err := executeGameTemplate(&w, "FAVICON-3", test.game, "game-id", test.boardID, test.hasBingo)
+		err := executeGameTemplate(&w, "FAVICON-3", test.game, "game-id", test.gameCode, test.check, test.custom, test.patternID)
 		got := w.String()
 		switch {
 		case err != nil:
@@ -109,6 +219,77 @@ func TestExecuteGameTemplate(t *testing.T) {
 	}
 }
 
+func TestNewCheckedBoardPage(t *testing.T) {
+	tests := []struct {
+		name string
+		bingo.Result
+		wantDaubs   []daub
+		wantWinLine *svgLine
+	}{
+		{
+			name: "no bingo",
+			Result: bingo.Result{
+				Drawn: 1<<0 | 1<<12,
+			},
+			wantDaubs: []daub{{X: 50, Y: 150}, {X: 250, Y: 350}},
+		},
+		{
+			name: "I column",
+			Result: bingo.Result{
+				Bingo: true,
+				Line:  bingo.Column,
+				Index: 1,
+				Cells: 0b11111_00000,
+				Drawn: 0b11111_00001,
+			},
+			wantDaubs:   []daub{{X: 50, Y: 150}, {X: 150, Y: 150, Winning: true}, {X: 150, Y: 250, Winning: true}, {X: 150, Y: 350, Winning: true}, {X: 150, Y: 450, Winning: true}, {X: 150, Y: 550, Winning: true}},
+			wantWinLine: &svgLine{X1: 150, Y1: 150, X2: 150, Y2: 550},
+		},
+		{
+			name: "row 2",
+			Result: bingo.Result{
+				Bingo: true,
+				Line:  bingo.Row,
+				Index: 1,
+				Cells: 1<<1 | 1<<6 | 1<<11 | 1<<16 | 1<<21,
+				Drawn: 1<<1 | 1<<6 | 1<<11 | 1<<16 | 1<<21,
+			},
+			wantDaubs:   []daub{{X: 50, Y: 250, Winning: true}, {X: 150, Y: 250, Winning: true}, {X: 250, Y: 250, Winning: true}, {X: 350, Y: 250, Winning: true}, {X: 450, Y: 250, Winning: true}},
+			wantWinLine: &svgLine{X1: 50, Y1: 250, X2: 450, Y2: 250},
+		},
+		{
+			name: "trailing diagonal",
+			Result: bingo.Result{
+				Bingo: true,
+				Line:  bingo.Diagonal,
+				Index: 1,
+				Cells: 1<<4 | 1<<8 | 1<<12 | 1<<16 | 1<<20,
+				Drawn: 1<<4 | 1<<8 | 1<<12 | 1<<16 | 1<<20,
+			},
+			wantDaubs:   []daub{{X: 50, Y: 550, Winning: true}, {X: 150, Y: 450, Winning: true}, {X: 250, Y: 350, Winning: true}, {X: 350, Y: 250, Winning: true}, {X: 450, Y: 150, Winning: true}},
+			wantWinLine: &svgLine{X1: 50, Y1: 550, X2: 450, Y2: 150},
+		},
+		{
+			name: "four corners",
+			Result: bingo.Result{
+				Bingo: true,
+				Cells: 1<<0 | 1<<4 | 1<<20 | 1<<24,
+				Drawn: 1<<0 | 1<<4 | 1<<20 | 1<<24,
+			},
+			wantDaubs: []daub{{X: 50, Y: 150, Winning: true}, {X: 50, Y: 550, Winning: true}, {X: 450, Y: 150, Winning: true}, {X: 450, Y: 550, Winning: true}},
+		},
+	}
+	for i, test := range tests {
+		p := newCheckedBoardPage(bingo.Board{}, "board-id", test.Result)
+		switch {
+		case !reflect.DeepEqual(test.wantDaubs, p.Daubs):
+			t.Errorf("test %v (%v): daubs not equal:\nwanted: %v\ngot:    %v", i, test.name, test.wantDaubs, p.Daubs)
+		case !reflect.DeepEqual(test.wantWinLine, p.WinLine):
+			t.Errorf("test %v (%v): win lines not equal:\nwanted: %v\ngot:    %v", i, test.name, test.wantWinLine, p.WinLine)
+		}
+	}
+}
+
 func TestExecuteGamesTemplate(t *testing.T) {
 	var w bytes.Buffer
 	gi := gameInfo{
@@ -133,12 +314,140 @@ func TestExecuteGamesTemplate(t *testing.T) {
 	}
 }
 
+func TestExecuteGamesTemplateUndo(t *testing.T) {
+	var w bytes.Buffer
+	gameInfos := []gameInfo{
+		{ID: "1847", NumbersLeft: 36, Undone: "N 40", Reason: "drew twice"},
+		{ID: "1848", NumbersLeft: 35},
+	}
+	if err := executeGamesTemplate(&w, "", gameInfos); err != nil {
+		t.Fatal(err)
+	}
+	got := w.String()
+	if want := "Undid N 40: drew twice"; strings.Count(got, "Undid") != 1 || !strings.Contains(got, want) {
+		t.Errorf("wanted only the undo of the first game to be shown as %q: %v", want, got)
+	}
+}
+
+func TestExecuteGameTemplateUndoDraw(t *testing.T) {
+	g := bingo.NewGameFromSeed(42)
+	tests := []struct {
+		name     string
+		drawn    int
+		wantUndo bool
+	}{
+		{"no numbers drawn", 0, false},
+		{"number drawn", 2, true},
+	}
+	for i, test := range tests {
+		for g.NumbersLeft() > bingo.Balls75-test.drawn {
+			g.DrawNumber()
+		}
+		var w bytes.Buffer
+		if err := executeGameTemplate(&w, "", *g, "game-id", "", gameCheck{}, nil, ""); err != nil {
+			t.Errorf("test %v (%v): %v", i, test.name, err)
+			continue
+		}
+		got := w.String()
+		switch {
+		case test.wantUndo != strings.Contains(got, `action="/game/undo_draw"`):
+			t.Errorf("test %v (%v): wanted undo form: %v: %v", i, test.name, test.wantUndo, got)
+		case test.wantUndo && !strings.Contains(got, "Undo Draw of "+g.Label(g.PreviousNumberDrawn())):
+			t.Errorf("test %v (%v): wanted undo form to show the previous number: %v", i, test.name, got)
+		}
+	}
+}
+
+func TestExecuteGameTemplateBalls(t *testing.T) {
+	g, err := bingo.NewGame(bingo.Balls90)
+	if err != nil {
+		t.Fatalf("creating game: %v", err)
+	}
+	for g.PreviousNumberDrawn() != 90 {
+		g.DrawNumber()
+	}
+	var w bytes.Buffer
+	err = executeGameTemplate(&w, "", *g, "game-id-90", "", gameCheck{}, nil, "")
+	got := w.String()
+	switch {
+	case err != nil:
+		t.Error(err)
+	case !strings.Contains(got, `<th scope="col">80-90</th>`):
+		t.Errorf("wanted ticket column ranges as drawn number headers: %v", got)
+	case !strings.Contains(got, "<span>90</span>"):
+		t.Errorf("wanted previous number without column letter: %v", got)
+	case strings.Contains(got, `class="check-board"`), strings.Contains(got, `class="edit-pattern"`):
+		t.Errorf("wanted no classic board forms for 90-ball game: %v", got)
+	}
+}
+
+func TestExecuteGameTemplateBoardIDPattern(t *testing.T) {
+	g := bingo.NewGameFromSeed(42)
+	g.DrawNumber()
+	var w bytes.Buffer
+	if err := executeGameTemplate(&w, "", *g, "game-id", "", gameCheck{}, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	m := regexp.MustCompile(`id="board-id"[^>]* pattern="([^"]*)"`).FindStringSubmatch(w.String())
+	if m == nil {
+		t.Fatalf("board id input pattern not found: %v", w.String())
+	}
+	boardIDPattern := regexp.MustCompile("^(?:" + html.UnescapeString(m[1]) + ")$") // the html input pattern must match the whole value
+	tests := []struct {
+		name    string
+		boardID string
+		want    bool
+	}{
+		{"board", board1257894001ID, true},
+		{"url base64 characters", "wwXqkL5ocUlWp-_G", true},
+		{"not base64 characters", "wwXqkL5ocUlWp^[G", false},
+		{"readable", "dtc2h 8t21s mna24 8pjz", true},
+		{"no free cell", noFreeCellBoardID, true},
+		{"signed no free cell", noFreeCellBoardID + "~7.AbCd_-12", true},
+	}
+	for i, test := range tests {
+		if want, got := test.want, boardIDPattern.MatchString(test.boardID); want != got {
+			t.Errorf("test %v (%v): wanted %q matching board id input pattern %q to be %v", i, test.name, test.boardID, boardIDPattern, want)
+		}
+	}
+}
+
+func TestExecuteGameTemplateRules(t *testing.T) {
+	r := bingo.Rules{Width: 3, Height: 3, ColumnNumbers: 10}
+	g, err := r.NewGame()
+	if err != nil {
+		t.Fatalf("creating game: %v", err)
+	}
+	g.DrawNumber()
+	var w bytes.Buffer
+	check := gameCheck{
+		BoardID:  card3x3n10ID,
+		HasBingo: true,
+	}
+	err = executeGameTemplate(&w, "", *g, rulesGame3ID, "", check, nil, "")
+	got := w.String()
+	switch {
+	case err != nil:
+		t.Error(err)
+	case !strings.Contains(got, `class="check-board"`):
+		t.Errorf("wanted form to check cards of game with rules: %v", got)
+	case !strings.Contains(got, `pattern="3x3n10\.`):
+		t.Errorf("wanted card ids with the rules of the game to be checked: %v", got)
+	case !strings.Contains(got, `value="HasLine"`), !strings.Contains(got, `value="IsFilled"`):
+		t.Errorf("wanted cards to be checked for lines and being filled: %v", got)
+	case strings.Contains(got, `value="FourCorners"`):
+		t.Errorf("wanted cards not to be checked for patterns of classic boards: %v", got)
+	case !strings.Contains(got, `<span class="has-bingo">BINGO !!!</span>`), strings.Contains(got, "/game/board?"):
+		t.Errorf("wanted check result without link to board page: %v", got)
+	}
+}
+
 func TestExecuteBoardTemplate(t *testing.T) {
 	var w bytes.Buffer
 	var b bingo.Board
 	boardID := "board-313"
 	barcode := "barcode-png-base64-data"
-	err := executeBoardTemplate(&w, "FAVICON-5", b, boardID, barcode)
+	err := executeBoardTemplate(&w, "FAVICON-5", boardPage{Board: b, BoardID: boardID, Barcode: barcode})
 	got := w.String()
 	switch {
 	case err != nil:
@@ -147,6 +456,101 @@ func TestExecuteBoardTemplate(t *testing.T) {
 		t.Errorf("wanted page to contain FAVICON-5: %v", w.String())
 	case !strings.Contains(got, boardID):
 		t.Errorf("board ID missing: %v", got)
+	case strings.Contains(got, "First BINGO"):
+		t.Errorf("wanted no wins when board is not replayed in a game: %v", got)
+	case strings.Contains(got, `class="daub`):
+		t.Errorf("wanted no daubs when board is not played in a game: %v", got)
+	}
+}
+
+func TestExecuteBoardTemplateWins(t *testing.T) {
+	var w bytes.Buffer
+	var b bingo.Board
+	gameID := "game-id-1257"
+	wins := []bingo.Win{
+		{Pattern: bingo.Pattern{Label: "pattern-label-1"}, Draw: 17, Number: 64, Sleeper: true},
+		{Pattern: bingo.Pattern{Label: "pattern-label-2"}},
+	}
+	err := executeBoardTemplate(&w, "FAVICON-6", boardPage{Board: b, BoardID: "board-314", GameID: gameID, Wins: wins})
+	got := w.String()
+	switch {
+	case err != nil:
+		t.Error(err)
+	case !strings.Contains(got, "First BINGO in game"):
+		t.Errorf("wins table missing: %v", got)
+	case !strings.Contains(got, gameID):
+		t.Errorf("game ID missing: %v", got)
+	case !strings.Contains(got, "pattern-label-1"), !strings.Contains(got, "pattern-label-2"):
+		t.Errorf("pattern labels missing: %v", got)
+	case !strings.Contains(got, "<td>17</td>"), !strings.Contains(got, "<td>O 64</td>"):
+		t.Errorf("first win draw and number missing: %v", got)
+	case !strings.Contains(got, `class="sleeper"`):
+		t.Errorf("sleeper win not marked: %v", got)
+	}
+}
+
+func TestExecuteBoardTemplateGame(t *testing.T) {
+	var w bytes.Buffer
+	b, err := bingo.BoardFromID(board1257894001ID)
+	if err != nil {
+		t.Fatalf("creating board: %v", err)
+	}
+	g, err := bingo.GameFromID("v2-4-" + board1257894001IDNumbers) // B column missing 10
+	if err != nil {
+		t.Fatalf("creating game: %v", err)
+	}
+	err = executeBoardTemplate(&w, "FAVICON-7", boardPage{Board: *b, BoardID: board1257894001ID, GameID: "game-id-4", GameCode: "AB2C", Game: g})
+	got := w.String()
+	switch {
+	case err != nil:
+		t.Error(err)
+	case strings.Count(got, `class="daub"`) != 5:
+		t.Errorf("wanted the four called cells and the free cell daubed: %v", got)
+	case strings.Count(got, `class="daub one-away"`) != 1:
+		t.Errorf("wanted the last cell of the B column marked as one away: %v", got)
+	case !strings.Contains(got, "One away: B 10"):
+		t.Errorf("one away numbers missing: %v", got)
+	case !strings.Contains(got, `data-game-code="AB2C"`), !strings.Contains(got, "new EventSource"):
+		t.Errorf("live board updates missing: %v", got)
+	}
+}
+
+func TestExecuteWinnersTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		winners []boardWinner
+		want    string
+	}{
+		{
+			name: "no winners",
+			want: "No boards have BINGO.",
+		},
+		{
+			name: "winners",
+			winners: []boardWinner{
+				{
+					BoardID: "board-id-1257",
+					Patterns: []bingo.Pattern{
+						{Label: "pattern-label-1"},
+						{Label: "pattern-label-2"},
+					},
+				},
+			},
+			want: "<td>pattern-label-1, pattern-label-2</td>",
+		},
+	}
+	for i, test := range tests {
+		var w bytes.Buffer
+		err := executeWinnersTemplate(&w, "FAVICON-7", "game-id-7", 9, test.winners)
+		got := w.String()
+		switch {
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+		case !strings.Contains(got, "Checked 9 boards"), !strings.Contains(got, "game-id-7"):
+			t.Errorf("test %v (%v): board count and game ID missing: %v", i, test.name, got)
+		case !strings.Contains(got, test.want):
+			t.Errorf("test %v (%v): wanted page to contain %q: %v", i, test.name, test.want, got)
+		}
 	}
 }
 
@@ -155,7 +559,7 @@ func TestExecuteBoardExportTemplate(t *testing.T) {
 	var b bingo.Board
 	boardID := "board-313"
 	barcode := "barcode-png-base64-data-2"
-	err := executeBoardExportTemplate(&w, b, boardID, barcode)
+	err := executeBoardExportTemplate(&w, b, boardID, "", barcode, "", "")
 	got := w.String()
 	switch {
 	case err != nil:
@@ -167,6 +571,95 @@ func TestExecuteBoardExportTemplate(t *testing.T) {
 	}
 }
 
+func TestExecuteBoardExportTemplateCenter(t *testing.T) {
+	noFreeCellBoard := bingo.Board{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 33, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65}
+	barcode := "barcode-png-base64-data-3"
+	tests := []struct {
+		name        string
+		b           bingo.Board
+		freeSpace   string
+		wantContain []string
+		wantMissing []string
+	}{
+		{
+			name:        "free space text",
+			freeSpace:   "FREE <3",
+			wantContain: []string{`class="free-text">FREE &lt;3</text>`, `height="600"`},
+			wantMissing: []string{barcode},
+		},
+		{
+			name:        "no free cell",
+			b:           noFreeCellBoard,
+			freeSpace:   "FREE",
+			wantContain: []string{`class="number">33</text>`, `class="footer"`, barcode, `height="700"`},
+			wantMissing: []string{`class="free-space"`, "FREE"},
+		},
+	}
+	for i, test := range tests {
+		var w bytes.Buffer
+		if err := executeBoardExportTemplate(&w, test.b, "board-315", "", barcode, test.freeSpace, ""); err != nil {
+			t.Errorf("test %v (%v): %v", i, test.name, err)
+			continue
+		}
+		got := w.String()
+		for _, want := range test.wantContain {
+			if !strings.Contains(got, want) {
+				t.Errorf("test %v (%v): wanted image to contain %q: %v", i, test.name, want, got)
+			}
+		}
+		for _, want := range test.wantMissing {
+			if strings.Contains(got, want) {
+				t.Errorf("test %v (%v): wanted image not to contain %q: %v", i, test.name, want, got)
+			}
+		}
+	}
+}
+
+func TestExecuteSeededTemplates(t *testing.T) {
+	var w bytes.Buffer
+	g := bingo.NewGameFromSeed(42)
+	if err := executeGameTemplate(&w, "", *g, "s42-0", "", gameCheck{}, nil, ""); err != nil {
+		t.Fatalf("rendering game: %v", err)
+	}
+	if got := w.String(); !strings.Contains(got, "Seed: <span>42</span>") {
+		t.Errorf("wanted seed on game page: %v", got)
+	}
+	w.Reset()
+	b := bingo.NewBoardFromSeed(42)
+	if err := executeBoardExportTemplate(&w, *b, "board-316", "", "", "", "42"); err != nil {
+		t.Fatalf("rendering board: %v", err)
+	}
+	if got := w.String(); !strings.Contains(got, "seed 42") {
+		t.Errorf("wanted seed on board: %v", got)
+	}
+}
+
+func TestExecuteStripExportTemplate(t *testing.T) {
+	var w bytes.Buffer
+	s := bingo.NewStrip()
+	err := executeStripExportTemplate(&w, *s)
+	got := w.String()
+	ticketID, _ := s[5].ID()
+	switch {
+	case err != nil:
+		t.Error(err)
+	case strings.Count(got, `class="ticket"`) != bingo.StripTickets:
+		t.Errorf("wanted six tickets: %v", got)
+	case strings.Count(got, `class="number"`) != 90:
+		t.Errorf("wanted 90 numbers: %v", got)
+	case !strings.Contains(got, ticketID):
+		t.Errorf("ticket ID missing: %v", got)
+	}
+}
+
+func TestExecuteStripExportTemplateInvalid(t *testing.T) {
+	var w bytes.Buffer
+	var s bingo.Strip
+	if err := executeStripExportTemplate(&w, s); err == nil {
+		t.Error("wanted error rendering strip of empty tickets")
+	}
+}
+
 func TestExecuteFaviconTemplate(t *testing.T) {
 	var w bytes.Buffer
 	err := executeFaviconTemplate(&w)
@@ -179,3 +672,50 @@ func TestExecuteFaviconTemplate(t *testing.T) {
 		t.Errorf("wanted favicon to start with %q:\n%v", wantPrefix, got)
 	}
 }
+
+func TestExecuteBoardExportTemplateReadableID(t *testing.T) {
+	noFreeCellBoard := bingo.Board{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 33, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65}
+	tests := []struct {
+		name string
+		b    bingo.Board
+		want string
+	}{
+		{"free cell", *bingo.NewBoardFromSeed(42), `<text x="250" y="390" class="id readable-id">`},
+		{"no free cell", noFreeCellBoard, `<text x="250" y="650" class="id">00000-`},
+		{"invalid board", bingo.Board{}, `<text x="250" y="390" class="id">board-318</text>`},
+	}
+	for i, test := range tests {
+		var w bytes.Buffer
+		if err := executeBoardExportTemplate(&w, test.b, "board-318", "", "", "", ""); err != nil {
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+			continue
+		}
+		if got := w.String(); !strings.Contains(got, test.want) {
+			t.Errorf("test %v (%v): wanted board to contain %q: %v", i, test.name, test.want, got)
+		}
+	}
+}
+
+func TestExecuteBoardExportTemplateCode(t *testing.T) {
+	noFreeCellBoard := bingo.Board{1, 2, 3, 4, 5, 16, 17, 18, 19, 20, 31, 32, 33, 34, 35, 46, 47, 48, 49, 50, 61, 62, 63, 64, 65}
+	tests := []struct {
+		name string
+		b    bingo.Board
+		seed string
+		want string
+	}{
+		{"free cell", *bingo.NewBoardFromSeed(42), "", `<text x="250" y="304" class="id"><tspan class="code">7.Xk2_a9Qp</tspan></text>`},
+		{"free cell with seed", *bingo.NewBoardFromSeed(42), "42", `<tspan class="seed">seed 42</tspan> <tspan class="code">7.Xk2_a9Qp</tspan>`},
+		{"no free cell", noFreeCellBoard, "", `<text x="250" y="630" class="id code">7.Xk2_a9Qp</text>`},
+	}
+	for i, test := range tests {
+		var w bytes.Buffer
+		if err := executeBoardExportTemplate(&w, test.b, "board-317", "7.Xk2_a9Qp", "", "", test.seed); err != nil {
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+			continue
+		}
+		if got := w.String(); !strings.Contains(got, test.want) {
+			t.Errorf("test %v (%v): wanted board to contain %q: %v", i, test.name, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+window.addEventListener('load', () => {
+    const boardEvents = document.querySelector('.board-events');
+
+    const log = (text) => {
+        boardEvents.innerText = text;
+    };
+    const handleDraw = () => {
+        window.location.reload(); // render the board with the new number daubed
+    };
+    const init = () => {
+        if (!('EventSource' in window)) {
+            log('browser cannot receive live game updates');
+            return;
+        }
+        const gameCode = boardEvents.dataset.gameCode;
+        const source = new EventSource('/game/events?gameID=' + encodeURIComponent(gameCode));
+        source.addEventListener('draw', handleDraw);
+        source.addEventListener('undo', handleDraw); // the number that was put back is no longer daubed
+        source.addEventListener('end', () => log('all numbers have been drawn'));
+    };
+    init();
+});
\ No newline at end of file
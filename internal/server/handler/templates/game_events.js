@@ -0,0 +1,50 @@
+window.addEventListener('load', () => {
+    const gameEvents = document.querySelector('.game-events');
+    const previousNumber = document.querySelector('.previous-number > span');
+    const numbersLeft = document.querySelector('.numbers-left');
+    const drawNumberSubmit = document.querySelector('.draw-number input[type=submit]');
+    const drawnNumberCells = document.querySelectorAll('.game-drawn-numbers tbody td');
+
+    const log = (text) => {
+        gameEvents.innerText = text;
+    };
+    const handleDraw = (event) => {
+        const draw = JSON.parse(event.data);
+        if (!previousNumber || drawnNumberCells.length == 0) {
+            window.location.reload(); // the first number was drawn, so the drawn numbers are not shown yet
+            return;
+        }
+        previousNumber.innerText = draw.label;
+        numbersLeft.innerText = 'Numbers left: ' + draw.numbersLeft;
+        const p = document.createElement('p');
+        p.innerText = draw.label;
+        drawnNumberCells[draw.column].appendChild(p);
+        log('drew ' + draw.label);
+    };
+    const handleUndo = () => {
+        window.location.reload(); // render the game without the number that was put back
+    };
+    const handleClaim = (event) => {
+        const claim = JSON.parse(event.data);
+        const result = claim.bingo ? 'BINGO !!!' : 'No Bingo :(';
+        const counterfeit = claim.counterfeit ? ' (COUNTERFEIT)' : '';
+        log('board ' + claim.boardID + counterfeit + ' checked for ' + claim.type + ': ' + result);
+    };
+    const handleEnd = () => {
+        drawNumberSubmit.disabled = true;
+        log('all numbers have been drawn');
+    };
+    const init = () => {
+        if (!('EventSource' in window)) {
+            log('browser cannot receive live game updates');
+            return;
+        }
+        const gameCode = gameEvents.dataset.gameCode;
+        const source = new EventSource('/game/events?gameID=' + encodeURIComponent(gameCode));
+        source.addEventListener('draw', handleDraw);
+        source.addEventListener('undo', handleUndo);
+        source.addEventListener('claim', handleClaim);
+        source.addEventListener('end', handleEnd);
+    };
+    init();
+});
\ No newline at end of file
@@ -0,0 +1,394 @@
+// Package websocket implements the framing of the WebSocket protocol (RFC 6455) for servers and clients.
+// Only the parts of the protocol needed to send text and binary messages are implemented: extensions and subprotocols are not supported.
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Conn is a WebSocket connection.
+	// Messages can be read by one goroutine while being written by others.
+	Conn struct {
+		conn     net.Conn
+		br       *bufio.Reader
+		isClient bool
+		writeMu  sync.Mutex
+		closed   bool
+	}
+	// Opcode is the type of a frame.
+	Opcode byte
+	// frameHeader is the start of a frame, before the payload.
+	frameHeader struct {
+		fin     bool
+		opcode  Opcode
+		masked  bool
+		maskKey [4]byte
+		length  uint64
+	}
+)
+
+const (
+	// ContinuationMessage is a frame that continues a fragmented message.
+	ContinuationMessage Opcode = 0
+	// TextMessage is a message of UTF-8 text.
+	TextMessage Opcode = 1
+	// BinaryMessage is a message of bytes.
+	BinaryMessage Opcode = 2
+	// CloseMessage is sent to close the connection.
+	CloseMessage Opcode = 8
+	// PingMessage is sent to check that the connection is open.
+	PingMessage Opcode = 9
+	// PongMessage is the response to a ping.
+	PongMessage Opcode = 10
+	// MaxMessageSize is the largest message that is read, in bytes.
+	MaxMessageSize = 1 << 16
+	// acceptGUID is joined to the key of a handshake to create the accept header.
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	// closeNormal is the status code sent when the connection is closed normally.
+	closeNormal = 1000
+	// closeWriteDur is the maximum time taken to write the close message.
+	closeWriteDur = time.Second
+)
+
+// ErrClosed is returned when reading from a connection that the other endpoint closed.
+var ErrClosed = errors.New("websocket connection closed")
+
+// Upgrade changes the HTTP request to a WebSocket connection.
+// An error is returned if the request is not a valid WebSocket handshake.  Nothing is written to the response in that case.
+// The deadlines of the server do not apply to the connection.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	switch {
+	case r.Method != http.MethodGet:
+		return nil, errors.New("websocket handshake must use GET method")
+	case !headerContains(r.Header, "Connection", "upgrade"):
+		return nil, errors.New("websocket handshake must have connection upgrade header")
+	case !headerContains(r.Header, "Upgrade", "websocket"):
+		return nil, errors.New("websocket handshake must have upgrade websocket header")
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		return nil, errors.New("websocket handshake must use version 13")
+	case len(key) == 0:
+		return nil, errors.New("websocket handshake missing key")
+	}
+	netConn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("hijacking connection: %v", err)
+	}
+	if err := netConn.SetDeadline(time.Time{}); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("clearing connection deadline: %v", err)
+	}
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := brw.WriteString(response); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("writing handshake response: %v", err)
+	}
+	if err := brw.Flush(); err != nil {
+		netConn.Close()
+		return nil, fmt.Errorf("writing handshake response: %v", err)
+	}
+	c := Conn{
+		conn: netConn,
+		br:   brw.Reader,
+	}
+	return &c, nil
+}
+
+// Dial opens a WebSocket connection to the url.
+// The url scheme can be ws or http.
+func Dial(url string) (*Conn, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating handshake request: %v", err)
+	}
+	switch req.URL.Scheme {
+	case "ws", "http":
+	default:
+		return nil, fmt.Errorf("unsupported websocket url scheme %q", req.URL.Scheme)
+	}
+	host := req.URL.Host
+	if len(req.URL.Port()) == 0 {
+		host += ":80"
+	}
+	netConn, err := net.Dial("tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to server: %v", err)
+	}
+	c, err := clientHandshake(netConn, req)
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// clientHandshake sends the request to upgrade the connection to a WebSocket connection.
+func clientHandshake(netConn net.Conn, req *http.Request) (*Conn, error) {
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return nil, fmt.Errorf("creating handshake key: %v", err)
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	if err := req.Write(netConn); err != nil {
+		return nil, fmt.Errorf("writing handshake request: %v", err)
+	}
+	br := bufio.NewReader(netConn)
+	res, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, fmt.Errorf("reading handshake response: %v", err)
+	}
+	switch {
+	case res.StatusCode != http.StatusSwitchingProtocols:
+		return nil, fmt.Errorf("server did not switch protocols: %v", res.Status)
+	case res.Header.Get("Sec-WebSocket-Accept") != acceptKey(key):
+		return nil, errors.New("server did not accept handshake key")
+	}
+	c := Conn{
+		conn:     netConn,
+		br:       br,
+		isClient: true,
+	}
+	return &c, nil
+}
+
+// acceptKey creates the value of the accept header for the handshake key.
+func acceptKey(key string) string {
+	h := sha1.New()
+	io.WriteString(h, key+acceptGUID)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerContains determines if the comma-separated values of the header contain the token, ignoring case.
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, s := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(s), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadMessage reads the next text or binary message, joining fragmented frames.
+// Pings are answered while reading.  ErrClosed is returned when the other endpoint closes the connection.
+func (c *Conn) ReadMessage() (Opcode, []byte, error) {
+	var opcode Opcode
+	var message []byte
+	for {
+		fh, err := c.readFrameHeader()
+		if err != nil {
+			return 0, nil, err
+		}
+		payload, err := c.readPayload(fh)
+		if err != nil {
+			return 0, nil, err
+		}
+		switch fh.opcode {
+		case PingMessage:
+			if err := c.writeFrame(PongMessage, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case PongMessage:
+			continue
+		case CloseMessage:
+			c.Close()
+			return 0, nil, ErrClosed
+		case TextMessage, BinaryMessage:
+			if opcode != 0 {
+				return 0, nil, errors.New("new message started before previous message finished")
+			}
+			opcode = fh.opcode
+		case ContinuationMessage:
+			if opcode == 0 {
+				return 0, nil, errors.New("continuation frame without a message")
+			}
+		default:
+			return 0, nil, fmt.Errorf("unknown opcode %v", fh.opcode)
+		}
+		if len(message)+len(payload) > MaxMessageSize {
+			return 0, nil, fmt.Errorf("message larger than %v bytes", MaxMessageSize)
+		}
+		message = append(message, payload...)
+		if fh.fin {
+			return opcode, message, nil
+		}
+	}
+}
+
+// readFrameHeader reads the header of the next frame.
+// Frames from clients must be masked and frames from servers must not be.
+func (c *Conn) readFrameHeader() (*frameHeader, error) {
+	var b [2]byte
+	if _, err := io.ReadFull(c.br, b[:]); err != nil {
+		return nil, fmt.Errorf("reading frame header: %v", err)
+	}
+	fh := frameHeader{
+		fin:    b[0]&0x80 != 0,
+		opcode: Opcode(b[0] & 0x0f),
+		masked: b[1]&0x80 != 0,
+		length: uint64(b[1] & 0x7f),
+	}
+	switch {
+	case b[0]&0x70 != 0:
+		return nil, errors.New("reserved bits set without extension")
+	case fh.masked == c.isClient:
+		return nil, errors.New("frame masking does not match endpoint")
+	case fh.opcode >= CloseMessage && (!fh.fin || fh.length > 125):
+		return nil, errors.New("control frames must not be fragmented or longer than 125 bytes")
+	}
+	switch fh.length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return nil, fmt.Errorf("reading frame length: %v", err)
+		}
+		fh.length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return nil, fmt.Errorf("reading frame length: %v", err)
+		}
+		fh.length = binary.BigEndian.Uint64(ext[:])
+	}
+	if fh.length > MaxMessageSize {
+		return nil, fmt.Errorf("frame larger than %v bytes", MaxMessageSize)
+	}
+	if fh.masked {
+		if _, err := io.ReadFull(c.br, fh.maskKey[:]); err != nil {
+			return nil, fmt.Errorf("reading frame mask key: %v", err)
+		}
+	}
+	return &fh, nil
+}
+
+// readPayload reads the payload of the frame, unmasking it.
+func (c *Conn) readPayload(fh *frameHeader) ([]byte, error) {
+	payload := make([]byte, fh.length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return nil, fmt.Errorf("reading frame payload: %v", err)
+	}
+	if fh.masked {
+		mask(payload, fh.maskKey)
+	}
+	return payload, nil
+}
+
+// WriteMessage writes the data as a single frame.
+func (c *Conn) WriteMessage(opcode Opcode, data []byte) error {
+	switch opcode {
+	case TextMessage, BinaryMessage:
+	default:
+		return fmt.Errorf("cannot write message with opcode %v", opcode)
+	}
+	return c.writeFrame(opcode, data)
+}
+
+// writeFrame writes the data in one final frame.  Frames written by clients are masked.
+func (c *Conn) writeFrame(opcode Opcode, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	frame := make([]byte, 0, 14+len(data))
+	frame = append(frame, 0x80|byte(opcode))
+	var maskBit byte
+	if c.isClient {
+		maskBit = 0x80
+	}
+	switch n := len(data); {
+	case n <= 125:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xffff:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	payload := make([]byte, len(data))
+	copy(payload, data)
+	if c.isClient {
+		var maskKey [4]byte
+		if _, err := rand.Read(maskKey[:]); err != nil {
+			return fmt.Errorf("creating frame mask key: %v", err)
+		}
+		frame = append(frame, maskKey[:]...)
+		mask(payload, maskKey)
+	}
+	frame = append(frame, payload...)
+	if _, err := c.conn.Write(frame); err != nil {
+		return fmt.Errorf("writing frame: %v", err)
+	}
+	return nil
+}
+
+// mask toggles the masking of the data with the key.
+func mask(data []byte, key [4]byte) {
+	for i := range data {
+		data[i] ^= key[i%4]
+	}
+}
+
+// ReadJSON reads the next message as JSON into the value.
+func (c *Conn) ReadJSON(v interface{}) error {
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decoding message: %v", err)
+	}
+	return nil
+}
+
+// WriteJSON writes the value as a JSON text message.
+func (c *Conn) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding message: %v", err)
+	}
+	return c.WriteMessage(TextMessage, data)
+}
+
+// Close sends a close message and closes the connection.
+// It is safe to call Close more than once.
+func (c *Conn) Close() error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	c.conn.SetWriteDeadline(time.Now().Add(closeWriteDur))
+	closeFrame := []byte{0x80 | byte(CloseMessage), 2, byte(closeNormal >> 8), byte(closeNormal & 0xff)}
+	if c.isClient {
+		closeFrame[1] |= 0x80
+		closeFrame = append(closeFrame[:2], 0, 0, 0, 0, closeFrame[2], closeFrame[3]) // zero mask key
+	}
+	c.conn.Write(closeFrame) // the other endpoint might already be closed
+	return c.conn.Close()
+}
@@ -0,0 +1,252 @@
+package websocket
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// echoServer creates a test server that writes back each message it reads.
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer c.Close()
+		for {
+			opcode, data, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := c.WriteMessage(opcode, data); err != nil {
+				return
+			}
+		}
+	})
+	s := httptest.NewServer(h)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestEcho(t *testing.T) {
+	s := echoServer(t)
+	c, err := Dial(s.URL)
+	if err != nil {
+		t.Fatalf("dialing: %v", err)
+	}
+	defer c.Close()
+	tests := []struct {
+		name   string
+		opcode Opcode
+		data   []byte
+	}{
+		{"short text", TextMessage, []byte("hello")},
+		{"empty", TextMessage, nil},
+		{"16-bit length", BinaryMessage, bytes.Repeat([]byte{7}, 300)},
+		{"64-bit length", BinaryMessage, bytes.Repeat([]byte{9}, MaxMessageSize)},
+	}
+	for i, test := range tests {
+		if err := c.WriteMessage(test.opcode, test.data); err != nil {
+			t.Errorf("test %v (%v): writing message: %v", i, test.name, err)
+			continue
+		}
+		opcode, data, err := c.ReadMessage()
+		switch {
+		case err != nil:
+			t.Errorf("test %v (%v): reading message: %v", i, test.name, err)
+		case test.opcode != opcode:
+			t.Errorf("test %v (%v): opcodes not equal: wanted %v, got %v", i, test.name, test.opcode, opcode)
+		case !bytes.Equal(test.data, data):
+			t.Errorf("test %v (%v): messages not equal: wanted %v bytes, got %v", i, test.name, len(test.data), len(data))
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	s := echoServer(t)
+	c, err := Dial(s.URL)
+	if err != nil {
+		t.Fatalf("dialing: %v", err)
+	}
+	defer c.Close()
+	type message struct {
+		Type string `json:"type"`
+	}
+	want := message{"join"}
+	if err := c.WriteJSON(want); err != nil {
+		t.Fatalf("writing json: %v", err)
+	}
+	var got message
+	switch err := c.ReadJSON(&got); {
+	case err != nil:
+		t.Errorf("reading json: %v", err)
+	case want != got:
+		t.Errorf("messages not equal: wanted %v, got %v", want, got)
+	}
+}
+
+func TestReadMessageFragmentedAndPing(t *testing.T) {
+	s := echoServer(t)
+	netConn, err := net.Dial("tcp", s.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("connecting: %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, s.URL, nil)
+	c, err := clientHandshake(netConn, req)
+	if err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+	defer c.Close()
+	frames := [][]byte{
+		{0x01, 0x80, 0, 0, 0, 0},                            // empty text frame, not final
+		{0x89, 0x82, 0, 0, 0, 0, 'h', 'i'},                  // ping in the middle of the message
+		{0x80, 0x83, 1, 2, 3, 4, 'a' ^ 1, 'b' ^ 2, 'c' ^ 3}, // final continuation frame
+	}
+	for _, f := range frames {
+		if _, err := netConn.Write(f); err != nil {
+			t.Fatalf("writing frame: %v", err)
+		}
+	}
+	var got []string
+	for i := 0; i < 2; i++ {
+		opcode, data, err := c.readFrameHeaderAndPayload()
+		if err != nil {
+			t.Fatalf("reading frame %v: %v", i, err)
+		}
+		got = append(got, string(rune('0'+opcode))+string(data))
+	}
+	want := []string{":hi", "1abc"} // pong (10 = ':') then the echoed text message
+	if strings.Join(want, ",") != strings.Join(got, ",") {
+		t.Errorf("frames not equal: wanted %q, got %q", want, got)
+	}
+}
+
+// readFrameHeaderAndPayload reads a single frame without handling control frames.
+func (c *Conn) readFrameHeaderAndPayload() (Opcode, []byte, error) {
+	fh, err := c.readFrameHeader()
+	if err != nil {
+		return 0, nil, err
+	}
+	payload, err := c.readPayload(fh)
+	return fh.opcode, payload, err
+}
+
+func TestReadMessageInvalidFrames(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame []byte
+	}{
+		{"unmasked client frame", []byte{0x81, 0x01, 'a'}},
+		{"reserved bit", []byte{0xc1, 0x80, 0, 0, 0, 0}},
+		{"fragmented control frame", []byte{0x09, 0x80, 0, 0, 0, 0}},
+		{"continuation without message", []byte{0x80, 0x80, 0, 0, 0, 0}},
+		{"unknown opcode", []byte{0x83, 0x80, 0, 0, 0, 0}},
+		{"too large", []byte{0x82, 0xff, 0, 0, 0, 0, 0, 1, 0, 1}},
+	}
+	for i, test := range tests {
+		errC := make(chan error, 1)
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c, err := Upgrade(w, r)
+			if err != nil {
+				errC <- err
+				return
+			}
+			defer c.Close()
+			_, _, err = c.ReadMessage()
+			errC <- err
+		})
+		s := httptest.NewServer(h)
+		c, err := Dial(s.URL)
+		if err != nil {
+			t.Fatalf("test %v (%v): dialing: %v", i, test.name, err)
+		}
+		c.conn.Write(test.frame)
+		if err := <-errC; err == nil || errors.Is(err, ErrClosed) {
+			t.Errorf("test %v (%v): wanted read error, got %v", i, test.name, err)
+		}
+		c.Close()
+		s.Close()
+	}
+}
+
+func TestCloseMessage(t *testing.T) {
+	s := echoServer(t)
+	c, err := Dial(s.URL)
+	if err != nil {
+		t.Fatalf("dialing: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("unwanted error closing: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("wanted second close to be a no-op, got %v", err)
+	}
+	if err := c.WriteMessage(TextMessage, nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("wanted closed error writing after close, got %v", err)
+	}
+}
+
+func TestUpgradeInvalid(t *testing.T) {
+	validHeader := func() http.Header {
+		return http.Header{
+			"Connection":            {"keep-alive, Upgrade"},
+			"Upgrade":               {"websocket"},
+			"Sec-Websocket-Version": {"13"},
+			"Sec-Websocket-Key":     {"dGhlIHNhbXBsZSBub25jZQ=="},
+		}
+	}
+	tests := []struct {
+		name   string
+		method string
+		remove string
+	}{
+		{"not GET", http.MethodPost, ""},
+		{"missing connection", http.MethodGet, "Connection"},
+		{"missing upgrade", http.MethodGet, "Upgrade"},
+		{"missing version", http.MethodGet, "Sec-Websocket-Version"},
+		{"missing key", http.MethodGet, "Sec-Websocket-Key"},
+		{"not hijackable", http.MethodGet, ""},
+	}
+	for i, test := range tests {
+		r := httptest.NewRequest(test.method, "/", nil)
+		r.Header = validHeader()
+		r.Header.Del(test.remove)
+		w := httptest.NewRecorder()
+		if _, err := Upgrade(w, r); err == nil {
+			t.Errorf("test %v (%v): wanted error", i, test.name)
+		}
+	}
+}
+
+func TestDialInvalid(t *testing.T) {
+	notWebSocket := httptest.NewServer(http.NotFoundHandler())
+	defer notWebSocket.Close()
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"bad url", "ws://%"},
+		{"unsupported scheme", "ftp://example.com"},
+		{"no server", "ws://127.0.0.1:1"},
+		{"not a websocket server", notWebSocket.URL},
+	}
+	for i, test := range tests {
+		if _, err := Dial(test.url); err == nil {
+			t.Errorf("test %v (%v): wanted error", i, test.name)
+		}
+	}
+}
+
+func TestAcceptKey(t *testing.T) {
+	// example from RFC 6455
+	if want, got := "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); want != got {
+		t.Errorf("accept keys not equal: wanted %q, got %q", want, got)
+	}
+}
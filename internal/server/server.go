@@ -4,12 +4,17 @@ package server
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"image"
+	"net"
 	"net/http"
 	"time"
 
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
 	"github.com/jacobpatterson1549/bitty-bingo/internal/server/handler"
 	"github.com/jacobpatterson1549/bitty-bingo/internal/server/handler/barcode"
+	"github.com/jacobpatterson1549/bitty-bingo/internal/server/handler/boardregistry"
+	"github.com/jacobpatterson1549/bitty-bingo/internal/server/handler/gamestore"
 )
 
 type (
@@ -35,6 +40,19 @@ type (
 		GameCount int
 		// Time is a function that can add a timestamp to parts of the site.
 		Time func() string
+		// GameStoreFile is the name of the file that games are saved to with short codes.  Games are not saved if it is empty.
+		GameStoreFile string
+		// Dealer shuffles new games and boards.  A dealer that is seeded to the time the server is created is used if it is nil.
+		Dealer *bingo.Dealer
+		// BoardRegistryFile is the name of the file that the ids of created batches of boards are saved to so boards are never repeated.
+		// Boards are only unique within each batch if it is empty.
+		BoardRegistryFile string
+		// GameSigningKey is the secret key that the ids of games are signed with so players cannot make their own games.
+		// Game ids are not signed if it is empty.  When it is set, ids of games that are not signed with it are rejected.
+		GameSigningKey []byte
+		// BoardSigningKey is the secret key that the authenticity codes of new boards are made with so counterfeit cards can be detected.
+		// Boards do not have codes if it is empty.  When it is set, checked boards without valid codes are flagged as counterfeit.
+		BoardSigningKey []byte
 	}
 )
 
@@ -48,15 +66,24 @@ const (
 )
 
 // NewServer initializes HTTP and HTTPS TCP servers.
-func (cfg Config) NewServer() *Server {
-	httpsHandler := cfg.httpsHandler()
+// An error is returned if the game store or board registry file cannot be loaded.
+func (cfg Config) NewServer() (*Server, error) {
+	games, err := cfg.gameStore()
+	if err != nil {
+		return nil, err
+	}
+	boards, err := cfg.boardRegistry()
+	if err != nil {
+		return nil, err
+	}
+	httpsHandler := cfg.httpsHandler(games, boards)
 	httpHandler := cfg.httpHandler()
 	s := Server{
 		config:      cfg,
 		httpsServer: httpServer(cfg.HTTPSPort, httpsHandler, true),
 		httpServer:  httpServer(cfg.HTTPPort, httpHandler, false),
 	}
-	return &s
+	return &s, nil
 }
 
 // Run starts the HTTP and HTTPS TCP servers.
@@ -84,13 +111,19 @@ func (s *Server) Shutdown(ctx context.Context) error {
 }
 
 // httpServer creates a http server on the port with the handler, using default read and write timeouts.
+// The contexts of requests are canceled when the server shuts down so long-running event streams end.
 func httpServer(port string, h http.Handler, https bool) *http.Server {
+	ctx, cancelFunc := context.WithCancel(context.Background())
 	svr := http.Server{
 		Addr:         ":" + port,
 		Handler:      h,
 		ReadTimeout:  readDur,
 		WriteTimeout: writeDur,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
 	}
+	svr.RegisterOnShutdown(cancelFunc)
 	if https {
 		svr.TLSConfig = &tls.Config{
 			MinVersion: tls.VersionTLS13,
@@ -119,12 +152,47 @@ func (cfg Config) httpHandler() http.Handler {
 
 // httpsHandler creates a HTTP handler to serve the site.
 // The gameCount and time function are validated used from the config in the handler.
+// The games store, dealer, board registry, and signing keys are optional.
 // Responses are returned gzip compression when allowed.
-func (cfg Config) httpsHandler() http.Handler {
-	h := handler.New(cfg.GameCount, cfg.Time, cfg)
+func (cfg Config) httpsHandler(games handler.GameStore, boards bingo.BoardRegistry) http.Handler {
+	hCfg := handler.Config{
+		GameCount:       cfg.GameCount,
+		Time:            cfg.Time,
+		Barcoder:        cfg,
+		Games:           games,
+		Dealer:          cfg.Dealer,
+		Boards:          boards,
+		GameSigningKey:  cfg.GameSigningKey,
+		BoardSigningKey: cfg.BoardSigningKey,
+	}
+	h := handler.New(hCfg)
 	return handler.WithGzip(h)
 }
 
+// gameStore opens the file to save games to, if it is configured.
+func (cfg Config) gameStore() (handler.GameStore, error) {
+	if len(cfg.GameStoreFile) == 0 {
+		return nil, nil
+	}
+	f, err := gamestore.Open(cfg.GameStoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("opening game store: %v", err)
+	}
+	return f, nil
+}
+
+// boardRegistry opens the file to save the ids of batches of boards to, if it is configured.
+func (cfg Config) boardRegistry() (bingo.BoardRegistry, error) {
+	if len(cfg.BoardRegistryFile) == 0 {
+		return nil, nil
+	}
+	f, err := boardregistry.Open(cfg.BoardRegistryFile)
+	if err != nil {
+		return nil, fmt.Errorf("opening board registry: %v", err)
+	}
+	return f, nil
+}
+
 func (c Config) Barcode(format string, text string, width, height int) (image.Image, error) {
 	f := c.barcodeFormat(format)
 	return barcode.Image(f, text, width, height)
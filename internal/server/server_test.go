@@ -3,8 +3,11 @@ package server
 import (
 	"context"
 	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -34,8 +37,10 @@ func TestNewServer(t *testing.T) {
 		},
 	}
 	for i, test := range tests {
-		s := test.cfg.NewServer()
+		s, err := test.cfg.NewServer()
 		switch {
+		case err != nil:
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
 		case s.httpsServer == nil:
 			t.Errorf("test %v (%v): HTTPS server not set", i, test.name)
 		case s.httpServer == nil:
@@ -51,6 +56,105 @@ func TestNewServer(t *testing.T) {
 
 }
 
+func TestNewServerGameStore(t *testing.T) {
+	dir := t.TempDir()
+	t.Run("ok", func(t *testing.T) {
+		cfg := Config{
+			GameStoreFile: filepath.Join(dir, "games.json"),
+		}
+		s, err := cfg.NewServer()
+		if err != nil {
+			t.Fatalf("unwanted error: %v", err)
+		}
+		r := httptest.NewRequest("POST", "/game", nil)
+		w := httptest.NewRecorder()
+		s.httpsServer.Handler.ServeHTTP(w, r)
+		if want, got := "/game?gameID=", w.Header().Get("Location"); !strings.HasPrefix(got, want) || len(got) != len(want)+4 {
+			t.Errorf("wanted redirect to game with short code, got %q", got)
+		}
+	})
+	t.Run("bad file", func(t *testing.T) {
+		cfg := Config{
+			GameStoreFile: dir,
+		}
+		if _, err := cfg.NewServer(); err == nil {
+			t.Errorf("wanted error opening directory as game store file")
+		}
+	})
+}
+
+func TestNewServerBoardRegistry(t *testing.T) {
+	dir := t.TempDir()
+	t.Run("ok", func(t *testing.T) {
+		name := filepath.Join(dir, "boards.txt")
+		cfg := Config{
+			BoardRegistryFile: name,
+		}
+		s, err := cfg.NewServer()
+		if err != nil {
+			t.Fatalf("unwanted error: %v", err)
+		}
+		r := httptest.NewRequest("POST", "/api/v1/boards?n=3", nil)
+		w := httptest.NewRecorder()
+		s.httpsServer.Handler.ServeHTTP(w, r)
+		data, err := os.ReadFile(name)
+		switch {
+		case err != nil:
+			t.Errorf("reading board registry file: %v", err)
+		case strings.Count(string(data), "\n") != 3:
+			t.Errorf("wanted 3 registered boards, got:\n%s", data)
+		}
+	})
+	t.Run("bad file", func(t *testing.T) {
+		cfg := Config{
+			BoardRegistryFile: dir,
+		}
+		if _, err := cfg.NewServer(); err == nil {
+			t.Errorf("wanted error opening directory as board registry file")
+		}
+	})
+}
+
+func TestNewServerGameSigningKey(t *testing.T) {
+	cfg := Config{
+		GameSigningKey: []byte("secret-key"),
+	}
+	s, err := cfg.NewServer()
+	if err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	r := httptest.NewRequest("POST", "/game", nil)
+	w := httptest.NewRecorder()
+	s.httpsServer.Handler.ServeHTTP(w, r)
+	location := w.Header().Get("Location")
+	if want := "/game?gameID=0~"; !strings.HasPrefix(location, want) {
+		t.Errorf("wanted redirect to signed game id starting with %q, got %q", want, location)
+	}
+	r = httptest.NewRequest("GET", "/game?gameID=0", nil)
+	w = httptest.NewRecorder()
+	s.httpsServer.Handler.ServeHTTP(w, r)
+	if want, got := 400, w.Code; want != got {
+		t.Errorf("wanted unsigned game id to be rejected with status code %v, got %v", want, got)
+	}
+}
+
+func TestNewServerBoardSigningKey(t *testing.T) {
+	cfg := Config{
+		BoardSigningKey: []byte("secret-key"),
+	}
+	s, err := cfg.NewServer()
+	if err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	r := httptest.NewRequest("POST", "/game/board", nil)
+	w := httptest.NewRecorder()
+	s.httpsServer.Handler.ServeHTTP(w, r)
+	location := w.Header().Get("Location")
+	if want := "/game/board?boardID="; !strings.HasPrefix(location, want) || !strings.Contains(location, "~0.") {
+		t.Errorf("wanted redirect to board id with an authenticity code for issue 0, got %q", location)
+	}
+}
+
 func TestServerRunShutdown(t *testing.T) {
 	tests := []struct {
 		name string
@@ -80,6 +184,29 @@ func TestServerRunShutdown(t *testing.T) {
 	}
 }
 
+func TestHTTPServerShutdownCancelsRequests(t *testing.T) {
+	started := make(chan struct{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		http.NewResponseController(w).Flush()
+		close(started)
+		<-r.Context().Done() // like a stream of events
+	})
+	svr := httpServer("0", h, false)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("creating listener: %v", err)
+	}
+	go svr.Serve(l)
+	go http.Get("http://" + l.Addr().String())
+	<-started
+	ctx, cancelFunc := context.WithTimeout(context.Background(), stopDur)
+	defer cancelFunc()
+	if err := svr.Shutdown(ctx); err != nil {
+		t.Errorf("unwanted error shutting down server with open request: %v", err)
+	}
+}
+
 func TestConfigHTTPHandler(t *testing.T) {
 	cfg := Config{
 		HTTPSPort: "8000",
@@ -111,7 +238,7 @@ func TestConfigHTTPSHandler(t *testing.T) {
 	var cfg Config
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "https://example.com/", nil)
-	h := cfg.httpsHandler()
+	h := cfg.httpsHandler(nil, nil)
 	r.Header = http.Header{
 		"Accept-Encoding": {"gzip, deflate, br"},
 	}
@@ -145,8 +272,8 @@ func TestConfigHTTPSHandlerGetBoardWithBarcode(t *testing.T) {
 			want: `<svg`,
 		},
 		{
-			name: "The board's ID should be part of image.",
-			want: boardID,
+			name: "The board's readable ID should be part of image.",
+			want: "DTC2H-8T21S-MNA24-8PJZ",
 		},
 		{
 			name: "The bar codes seem to all start with this, the first 11 chars are from the png header.  This also checks the image width/height.",
@@ -162,7 +289,7 @@ func TestConfigHTTPSHandlerGetBoardWithBarcode(t *testing.T) {
 	for i, test := range tests {
 		for _, f := range formats {
 			var cfg Config
-			h := cfg.httpsHandler()
+			h := cfg.httpsHandler(nil, nil)
 			r := httptest.NewRequest("GET", "/game/board?boardID="+boardID+"&barcodeFormat="+f, nil)
 			w := httptest.NewRecorder()
 			h.ServeHTTP(w, r)
@@ -0,0 +1,171 @@
+// Package simulate plays many bingo games to find how many numbers are drawn before a board first has a pattern.
+package simulate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
+)
+
+type (
+	// Config is used to run a simulation.
+	Config struct {
+		// Boards is the number of boards in play in each game.
+		Boards int
+		// Games is the number of games to play.
+		Games int
+		// Pattern is the pattern that a board must have to win.
+		Pattern bingo.Pattern
+		// Workers is the number of games played at the same time.  The number of CPUs is used if it is zero.
+		Workers int
+		// Seed is the seed of the first game.  The seed of each other game is one more than the seed of the game before it.
+		Seed uint64
+	}
+	// Result is the distribution of the number of draws until the first winner of the simulated games.
+	Result struct {
+		// Games is the number of games that were played.
+		Games int
+		// Boards is the number of boards that were in play in each game.
+		Boards int
+		// Pattern is the pattern that a board had to have to win.
+		Pattern bingo.Pattern
+		// Draws is the number of games that were won after each number of draws, indexed by how many numbers were drawn.
+		Draws [bingo.MaxNumber + 1]int
+	}
+)
+
+// histogramWidth is the most characters in a bar of the histogram of a report.
+const histogramWidth = 50
+
+// percentiles are the percentiles of draws that are shown in reports.
+var percentiles = []float64{10, 25, 50, 75, 90, 99}
+
+// Run plays the games, each with new boards and numbers shuffled from its seed, so the result does not depend on the number of workers.
+// An error is returned if the config is not valid.
+func (cfg Config) Run() (*Result, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	workers := cfg.Workers
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+	draws := make([][bingo.MaxNumber + 1]int, workers)
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; i < cfg.Games; i += workers {
+				d := cfg.play(cfg.Seed + uint64(i))
+				draws[w][d]++
+			}
+		}()
+	}
+	wg.Wait()
+	r := Result{
+		Games:   cfg.Games,
+		Boards:  cfg.Boards,
+		Pattern: cfg.Pattern,
+	}
+	for _, wd := range draws {
+		for d, n := range wd {
+			r.Draws[d] += n
+		}
+	}
+	return &r, nil
+}
+
+// validate checks that the config can be run.
+func (cfg Config) validate() error {
+	switch {
+	case cfg.Boards < 1:
+		return errors.New("at least one board is needed")
+	case cfg.Games < 1:
+		return errors.New("at least one game is needed")
+	case cfg.Workers < 0:
+		return errors.New("the number of workers cannot be negative")
+	case len(cfg.Pattern.Masks) == 0:
+		return errors.New("pattern has no cells")
+	}
+	return nil
+}
+
+// play deals the boards and draws all the numbers of a game shuffled from the seed.
+// The number of draws when the first board had the pattern is returned.
+func (cfg Config) play(seed uint64) int {
+	d := bingo.NewDealerFrom(bingo.NewSeededResetter(seed))
+	boards := make([]*bingo.Board, cfg.Boards)
+	for i := range boards {
+		boards[i] = d.NewBoard()
+	}
+	var g bingo.Game
+	for g.NumbersLeft() != 0 {
+		d.DrawNumber(&g)
+	}
+	first := len(g.DrawnNumbers())
+	for _, b := range boards {
+		if w := b.Replay(g, cfg.Pattern)[0]; w.Draw != 0 && w.Draw < first {
+			first = w.Draw
+		}
+	}
+	return first
+}
+
+// Mean is the average number of draws until the first winner.
+func (r Result) Mean() float64 {
+	if r.Games == 0 {
+		return 0
+	}
+	sum := 0
+	for d, n := range r.Draws {
+		sum += d * n
+	}
+	return float64(sum) / float64(r.Games)
+}
+
+// Percentile is the fewest draws that the first winner was found by in at least p percent of the games.
+func (r Result) Percentile(p float64) int {
+	want := int(math.Ceil(p / 100 * float64(r.Games)))
+	sum := 0
+	for d, n := range r.Draws {
+		sum += n
+		if sum >= want && sum != 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// WriteReport writes the mean, percentiles, and a histogram of the draws until the first winner.
+func (r Result) WriteReport(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Simulated %v games with %v boards for pattern %v\n", r.Games, r.Boards, r.Pattern.Label)
+	fmt.Fprintf(&sb, "Mean draws until first winner: %.2f\n", r.Mean())
+	sb.WriteString("Percentiles:")
+	for _, p := range percentiles {
+		fmt.Fprintf(&sb, " %vth=%v", p, r.Percentile(p))
+	}
+	sb.WriteString("\nDraws  Games\n")
+	most := 0
+	for _, n := range r.Draws {
+		most = max(most, n)
+	}
+	for d, n := range r.Draws {
+		if n == 0 {
+			continue
+		}
+		bar := strings.Repeat("#", (n*histogramWidth+most-1)/most)
+		fmt.Fprintf(&sb, "%5v  %-*v %v\n", d, histogramWidth, bar, n)
+	}
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("writing report: %v", err)
+	}
+	return nil
+}
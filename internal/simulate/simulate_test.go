@@ -0,0 +1,147 @@
+package simulate
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
+)
+
+func TestConfigRun(t *testing.T) {
+	line, _ := bingo.PatternByName("HasLine")
+	cfg := Config{
+		Boards:  20,
+		Games:   200,
+		Pattern: line,
+		Workers: 1,
+		Seed:    42,
+	}
+	r1, err := cfg.Run()
+	if err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	cfg.Workers = 7
+	r7, err := cfg.Run()
+	if err != nil {
+		t.Fatalf("unwanted error with many workers: %v", err)
+	}
+	if !reflect.DeepEqual(r1, r7) {
+		t.Errorf("wanted the same result with any number of workers:\nwanted: %v\ngot:    %v", r1, r7)
+	}
+	total := 0
+	for d, n := range r1.Draws {
+		if d < 4 && n != 0 {
+			t.Errorf("wanted no games won before the fourth draw, got %v won after %v", n, d)
+		}
+		total += n
+	}
+	if total != cfg.Games {
+		t.Errorf("wanted %v games in the distribution, got %v", cfg.Games, total)
+	}
+	cfg.Boards = 200
+	r200, err := cfg.Run()
+	switch {
+	case err != nil:
+		t.Errorf("unwanted error with more boards: %v", err)
+	case r200.Mean() >= r1.Mean():
+		t.Errorf("wanted fewer draws until the first winner with more boards: 20 boards: %v, 200 boards: %v", r1.Mean(), r200.Mean())
+	}
+}
+
+func TestConfigRunInvalid(t *testing.T) {
+	line, _ := bingo.PatternByName("HasLine")
+	tests := []struct {
+		name string
+		Config
+	}{
+		{"no boards", Config{Games: 1, Pattern: line}},
+		{"no games", Config{Boards: 1, Pattern: line}},
+		{"negative workers", Config{Boards: 1, Games: 1, Pattern: line, Workers: -1}},
+		{"no pattern", Config{Boards: 1, Games: 1}},
+	}
+	for i, test := range tests {
+		if _, err := test.Config.Run(); err == nil {
+			t.Errorf("test %v (%v): wanted error", i, test.name)
+		}
+	}
+}
+
+func TestResultStatistics(t *testing.T) {
+	r := Result{
+		Games: 10,
+	}
+	r.Draws[5] = 2
+	r.Draws[6] = 5
+	r.Draws[10] = 3
+	if want, got := 7.0, r.Mean(); want != got {
+		t.Errorf("means not equal: wanted %v, got %v", want, got)
+	}
+	tests := []struct {
+		p    float64
+		want int
+	}{
+		{0, 5},
+		{10, 5},
+		{20, 5},
+		{21, 6},
+		{50, 6},
+		{70, 6},
+		{71, 10},
+		{100, 10},
+	}
+	for i, test := range tests {
+		if got := r.Percentile(test.p); test.want != got {
+			t.Errorf("test %v: percentile %v: wanted %v, got %v", i, test.p, test.want, got)
+		}
+	}
+	var empty Result
+	if empty.Mean() != 0 || empty.Percentile(50) != 0 {
+		t.Errorf("wanted zero statistics for empty result")
+	}
+}
+
+func TestResultWriteReport(t *testing.T) {
+	r := Result{
+		Games:  10,
+		Boards: 3,
+		Pattern: bingo.Pattern{
+			Label: "Line",
+		},
+	}
+	r.Draws[5] = 2
+	r.Draws[6] = 5
+	r.Draws[10] = 3
+	var buf bytes.Buffer
+	if err := r.WriteReport(&buf); err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	got := buf.String()
+	wants := []string{
+		"Simulated 10 games with 3 boards for pattern Line\n",
+		"Mean draws until first winner: 7.00\n",
+		"50th=6",
+		"    6  " + strings.Repeat("#", histogramWidth) + " 5\n",
+		"   10  " + strings.Repeat("#", 30) + strings.Repeat(" ", 20) + " 3\n",
+	}
+	for _, want := range wants {
+		if !strings.Contains(got, want) {
+			t.Errorf("wanted report to contain %q:\n%v", want, got)
+		}
+	}
+	if strings.Contains(got, "\n    7 ") {
+		t.Errorf("wanted draws without games to not be in histogram:\n%v", got)
+	}
+	if err := r.WriteReport(errWriter{}); err == nil {
+		t.Errorf("wanted write error")
+	}
+}
+
+// errWriter always returns an error when writing.
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("mock write error")
+}
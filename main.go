@@ -2,21 +2,39 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
 	"github.com/jacobpatterson1549/bitty-bingo/internal/server"
+	"github.com/jacobpatterson1549/bitty-bingo/internal/simulate"
 )
 
-// main runs a bingo server.
+// simulateCommand is the first argument that runs a simulation instead of the server.
+const simulateCommand = "simulate"
+
+// main runs a bingo server, or a simulation if the first argument is the simulate command.
 func main() {
 	programName, programArgs := os.Args[0], os.Args[1:]
+	if len(programArgs) != 0 && programArgs[0] == simulateCommand {
+		var cfg simulate.Config
+		fs := simulateFlagSet(&cfg, programName+" "+simulateCommand)
+		fs.Parse(programArgs[1:])
+		if err := runSimulation(cfg, os.Stdout); err != nil {
+			log.Fatalf("running simulation: %v", err)
+		}
+		return
+	}
 	portOverride, hasPortOverride := os.LookupEnv("PORT")
 	var cfg server.Config
 	fs := flagSet(&cfg, programName)
@@ -35,6 +53,7 @@ func flagSet(cfg *server.Config, programName string) *flag.FlagSet {
 	fs.Usage = func() {
 		fmt.Fprintln(fs.Output(), "Runs the server")
 		fmt.Fprintln(fs.Output(), "Providing PORT environment variable overrides the command line argument, runs the HTTPS Server on the specified port, does not provide a HTTP redirect, and does not load TLS certificates.")
+		fmt.Fprintf(fs.Output(), "Run %q with the %v command to simulate games instead.\n", programName+" "+simulateCommand+" -h", simulateCommand)
 		fs.PrintDefaults()
 	}
 	fs.StringVar(&cfg.HTTPPort, "http-port", "80", "The TCP port for HTTP requests.")
@@ -42,9 +61,81 @@ func flagSet(cfg *server.Config, programName string) *flag.FlagSet {
 	fs.StringVar(&cfg.TLSCertFile, "tls-cert-file", "", "The name of the TLS public certificate file")
 	fs.StringVar(&cfg.TLSKeyFile, "tls-key-file", "", "The name of the TLS private key file")
 	fs.IntVar(&cfg.GameCount, "game-count", 10, "The number of game states to keep in the history")
+	fs.StringVar(&cfg.GameStoreFile, "game-store-file", "", "The name of the JSON file to save games to with short codes.  Games are not saved if empty")
+	fs.StringVar(&cfg.BoardRegistryFile, "board-registry-file", "", "The name of the file to save the ids of created batches of boards to so boards are never repeated.  Boards are only unique within each batch if empty")
+	fs.Func("game-signing-key-file", "The name of the file with the secret key to sign game ids with so players cannot make their own games.  Game ids are not signed if not set", func(name string) (err error) {
+		cfg.GameSigningKey, err = readKeyFile(name)
+		return err
+	})
+	fs.Func("board-signing-key-file", "The name of the file with the secret key to make authenticity codes for new boards with so counterfeit cards can be detected.  Boards do not have codes if not set", func(name string) (err error) {
+		cfg.BoardSigningKey, err = readKeyFile(name)
+		return err
+	})
+	fs.BoolFunc("secure-shuffle", "Shuffle games and boards with crypto/rand instead of math/rand", func(value string) error {
+		secure, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		cfg.Dealer = nil
+		if secure {
+			cfg.Dealer = bingo.NewDealerFrom(bingo.CryptoResetter)
+		}
+		return nil
+	})
 	return fs
 }
 
+// readKeyFile reads the secret key from the file, without surrounding whitespace.
+// An error is returned if the file cannot be read or the key is empty.
+func readKeyFile(name string) ([]byte, error) {
+	key, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	key = bytes.TrimSpace(key)
+	if len(key) == 0 {
+		return nil, errors.New("key file is empty")
+	}
+	return key, nil
+}
+
+// simulateFlagSet creates a flag set for the simulate command that sets the config.
+// The pattern of the config is a line unless it is set.
+func simulateFlagSet(cfg *simulate.Config, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Simulates games to report how many numbers are drawn until a board first has the pattern")
+		fs.PrintDefaults()
+	}
+	cfg.Pattern, _ = bingo.PatternByName("HasLine")
+	fs.IntVar(&cfg.Boards, "boards", 200, "The number of boards in play in each game")
+	fs.IntVar(&cfg.Games, "games", 10000, "The number of games to play")
+	fs.IntVar(&cfg.Workers, "workers", 0, "The number of games to play at the same time.  The number of CPUs is used if zero")
+	fs.Uint64Var(&cfg.Seed, "seed", 1, "The seed of the first game.  The same seed always gives the same results")
+	fs.Func("pattern", "The name of the pattern, such as IsFilled or FourCorners, or the id of a custom pattern (default HasLine)", func(s string) error {
+		if p, ok := bingo.PatternByName(s); ok {
+			cfg.Pattern = p
+			return nil
+		}
+		p, err := bingo.PatternFromID(s)
+		if err != nil {
+			return fmt.Errorf("not a standard pattern name or custom pattern id: %v", err)
+		}
+		cfg.Pattern = *p
+		return nil
+	})
+	return fs
+}
+
+// runSimulation runs the simulation and writes the report.
+func runSimulation(cfg simulate.Config, w io.Writer) error {
+	r, err := cfg.Run()
+	if err != nil {
+		return err
+	}
+	return r.WriteReport(w)
+}
+
 // parseServerConfig parses command line flag set and environment variables into a server config.
 func parseServerConfig(cfg *server.Config, fs *flag.FlagSet, programArgs []string, portOverride string, hasPortOverride bool) {
 	fs.Parse(programArgs)
@@ -59,7 +150,10 @@ func parseServerConfig(cfg *server.Config, fs *flag.FlagSet, programArgs []strin
 
 // runServer creates and runs a bingo server from the config.
 func runServer(cfg server.Config, log *log.Logger) (err error) {
-	s := cfg.NewServer()
+	s, err := cfg.NewServer()
+	if err != nil {
+		return fmt.Errorf("creating server: %v", err)
+	}
 	done := make(chan os.Signal, 2)
 	signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
 	errC := s.Run()
@@ -6,11 +6,15 @@ import (
 	"log"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/jacobpatterson1549/bitty-bingo/bingo"
 	"github.com/jacobpatterson1549/bitty-bingo/internal/server"
+	"github.com/jacobpatterson1549/bitty-bingo/internal/simulate"
 )
 
 func TestFlagSet(t *testing.T) {
@@ -36,6 +40,15 @@ func TestFlagSet(t *testing.T) {
 			t.Errorf("wanted help to be printed")
 		}
 	})
+	t.Run("bad secure shuffle", func(t *testing.T) {
+		var cfg server.Config
+		fs := flagSet(&cfg, "name")
+		fs.Init("name", flag.ContinueOnError)
+		fs.SetOutput(new(bytes.Buffer))
+		if err := fs.Parse([]string{"-secure-shuffle=maybe"}); err == nil {
+			t.Errorf("wanted error parsing secure shuffle that is not a bool")
+		}
+	})
 }
 
 func TestParseServerConfig(t *testing.T) {
@@ -53,6 +66,138 @@ func TestParseServerConfig(t *testing.T) {
 	}
 }
 
+func TestFlagSetSigningKeyFiles(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key.txt")
+	emptyFile := filepath.Join(dir, "empty.txt")
+	if err := os.WriteFile(keyFile, []byte("secret-key\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(emptyFile, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	flags := []struct {
+		name string
+		key  func(cfg server.Config) []byte
+	}{
+		{"game-signing-key-file", func(cfg server.Config) []byte { return cfg.GameSigningKey }},
+		{"board-signing-key-file", func(cfg server.Config) []byte { return cfg.BoardSigningKey }},
+	}
+	tests := []struct {
+		name    string
+		file    string
+		wantKey string
+		wantErr bool
+	}{
+		{"key", keyFile, "secret-key", false},
+		{"empty", emptyFile, "", true},
+		{"missing", filepath.Join(dir, "missing.txt"), "", true},
+	}
+	for _, f := range flags {
+		for i, test := range tests {
+			var cfg server.Config
+			fs := flagSet(&cfg, "")
+			fs.Init("", flag.ContinueOnError)
+			fs.SetOutput(new(bytes.Buffer))
+			err := fs.Parse([]string{"-" + f.name + "=" + test.file})
+			switch {
+			case test.wantErr:
+				if err == nil {
+					t.Errorf("%v: test %v (%v): wanted error", f.name, i, test.name)
+				}
+			case err != nil:
+				t.Errorf("%v: test %v (%v): unwanted error: %v", f.name, i, test.name, err)
+			case test.wantKey != string(f.key(cfg)):
+				t.Errorf("%v: test %v (%v): keys not equal: wanted %q, got %q", f.name, i, test.name, test.wantKey, f.key(cfg))
+			}
+		}
+	}
+}
+
+func TestSimulateFlagSet(t *testing.T) {
+	corners, _ := bingo.PatternByName("FourCorners")
+	custom, _ := bingo.CustomPattern(0, 6, 18, 24)
+	customID, _ := custom.ID()
+	tests := []struct {
+		name        string
+		args        []string
+		wantPattern string
+		wantConfig  simulate.Config
+	}{
+		{
+			name:        "defaults",
+			wantPattern: "HasLine",
+			wantConfig: simulate.Config{
+				Boards: 200,
+				Games:  10000,
+				Seed:   1,
+			},
+		},
+		{
+			name:        "all flags",
+			args:        []string{"-boards=50", "-games=300", "-workers=3", "-seed=42", "-pattern=" + corners.Name},
+			wantPattern: corners.Name,
+			wantConfig: simulate.Config{
+				Boards:  50,
+				Games:   300,
+				Workers: 3,
+				Seed:    42,
+			},
+		},
+		{
+			name:        "custom pattern",
+			args:        []string{"-pattern=" + customID},
+			wantPattern: bingo.CustomPatternName,
+			wantConfig: simulate.Config{
+				Boards: 200,
+				Games:  10000,
+				Seed:   1,
+			},
+		},
+	}
+	for i, test := range tests {
+		var cfg simulate.Config
+		fs := simulateFlagSet(&cfg, "")
+		if err := fs.Parse(test.args); err != nil {
+			t.Errorf("test %v (%v): unwanted error: %v", i, test.name, err)
+			continue
+		}
+		if want, got := test.wantPattern, cfg.Pattern.Name; want != got {
+			t.Errorf("test %v (%v): pattern names not equal: wanted %q, got %q", i, test.name, want, got)
+		}
+		cfg.Pattern = bingo.Pattern{}
+		if want, got := test.wantConfig, cfg; !reflect.DeepEqual(want, got) {
+			t.Errorf("test %v (%v): configs are not equal:\nwanted: %#v\ngot:    %#v", i, test.name, want, got)
+		}
+	}
+	t.Run("bad pattern", func(t *testing.T) {
+		var cfg simulate.Config
+		fs := simulateFlagSet(&cfg, "")
+		fs.Init("", flag.ContinueOnError)
+		fs.SetOutput(new(bytes.Buffer))
+		if err := fs.Parse([]string{"-pattern=Unknown"}); err == nil {
+			t.Errorf("wanted error parsing unknown pattern")
+		}
+	})
+}
+
+func TestRunSimulation(t *testing.T) {
+	var cfg simulate.Config
+	fs := simulateFlagSet(&cfg, "")
+	fs.Parse([]string{"-boards=10", "-games=20"})
+	var buf bytes.Buffer
+	if err := runSimulation(cfg, &buf); err != nil {
+		t.Fatalf("unwanted error: %v", err)
+	}
+	if want, got := "Simulated 20 games with 10 boards for pattern Line", buf.String(); !strings.HasPrefix(got, want) {
+		t.Errorf("wanted report to start with %q, got:\n%v", want, got)
+	}
+	cfg.Games = 0
+	if err := runSimulation(cfg, &buf); err == nil {
+		t.Errorf("wanted error simulating no games")
+	}
+}
+
 func TestRunServer(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test that runs an HTTPS server on a tcp port")
@@ -120,6 +265,8 @@ var (
 		"--tls-cert-file=/home/jacobpatterson1549/tls-cert.pem",
 		"--tls-key-file=/home/jacobpatterson1549/tls-key.pem",
 		"--game-count=33",
+		"--game-store-file=/home/jacobpatterson1549/bingo-games.json",
+		"--board-registry-file=/home/jacobpatterson1549/bingo-boards.txt",
 	}
 	parseServerConfigTests = []struct {
 		name            string
@@ -141,27 +288,62 @@ var (
 			name:        "all flags",
 			programArgs: sampleProgramArgs,
 			wantConfig: server.Config{
-				GameCount:     33,
-				HTTPPort:      "8001",
-				HTTPSPort:     "8000",
-				TLSCertFile:   "/home/jacobpatterson1549/tls-cert.pem",
-				TLSKeyFile:    "/home/jacobpatterson1549/tls-key.pem",
-				HTTPSRedirect: true,
+				GameCount:         33,
+				GameStoreFile:     "/home/jacobpatterson1549/bingo-games.json",
+				BoardRegistryFile: "/home/jacobpatterson1549/bingo-boards.txt",
+				HTTPPort:          "8001",
+				HTTPSPort:         "8000",
+				TLSCertFile:       "/home/jacobpatterson1549/tls-cert.pem",
+				TLSKeyFile:        "/home/jacobpatterson1549/tls-key.pem",
+				HTTPSRedirect:     true,
 			},
 		},
 		{
 			name:        "PORT should override HTTPS port and not redirect",
 			programArgs: sampleProgramArgs,
 			wantConfig: server.Config{
-				GameCount:     33,
-				HTTPPort:      "8001",
-				HTTPSPort:     "444",
-				TLSCertFile:   "/home/jacobpatterson1549/tls-cert.pem",
-				TLSKeyFile:    "/home/jacobpatterson1549/tls-key.pem",
-				HTTPSRedirect: false,
+				GameCount:         33,
+				GameStoreFile:     "/home/jacobpatterson1549/bingo-games.json",
+				BoardRegistryFile: "/home/jacobpatterson1549/bingo-boards.txt",
+				HTTPPort:          "8001",
+				HTTPSPort:         "444",
+				TLSCertFile:       "/home/jacobpatterson1549/tls-cert.pem",
+				TLSKeyFile:        "/home/jacobpatterson1549/tls-key.pem",
+				HTTPSRedirect:     false,
 			},
 			portOverride:    "444",
 			hasPortOverride: true,
 		},
+		{
+			name:        "secure shuffle",
+			programArgs: []string{"-secure-shuffle"},
+			wantConfig: server.Config{
+				HTTPSRedirect: true,
+				HTTPPort:      "80",
+				HTTPSPort:     "443",
+				GameCount:     10,
+				Dealer:        bingo.NewDealerFrom(bingo.CryptoResetter),
+			},
+		},
+		{
+			name:        "secure shuffle disabled after enabled",
+			programArgs: []string{"-secure-shuffle", "-secure-shuffle=false"},
+			wantConfig: server.Config{
+				HTTPSRedirect: true,
+				HTTPPort:      "80",
+				HTTPSPort:     "443",
+				GameCount:     10,
+			},
+		},
+		{
+			name:        "secure shuffle disabled",
+			programArgs: []string{"-secure-shuffle=false"},
+			wantConfig: server.Config{
+				HTTPSRedirect: true,
+				HTTPPort:      "80",
+				HTTPSPort:     "443",
+				GameCount:     10,
+			},
+		},
 	}
 )